@@ -16,11 +16,21 @@ import (
 
 	cognitopkg "github.com/jaekwang-park/todo-api/internal/cognito"
 	"github.com/jaekwang-park/todo-api/internal/config"
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	todogrpc "github.com/jaekwang-park/todo-api/internal/grpc"
+	"github.com/jaekwang-park/todo-api/internal/health"
 	todohttp "github.com/jaekwang-park/todo-api/internal/http"
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 	"github.com/jaekwang-park/todo-api/internal/middleware"
 	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/openapi"
+	"github.com/jaekwang-park/todo-api/internal/pubsub"
 	"github.com/jaekwang-park/todo-api/internal/repository"
 	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/stream"
+	"github.com/jaekwang-park/todo-api/internal/telemetry"
+	"github.com/jaekwang-park/todo-api/internal/webhook"
+	"github.com/jaekwang-park/todo-api/migrations"
 )
 
 // userResolverAdapter adapts a user repository to the middleware.UserResolver interface.
@@ -30,15 +40,15 @@ type userResolverAdapter struct {
 	}
 }
 
-func (a *userResolverAdapter) ResolveUserID(ctx context.Context, cognitoSub string) (string, error) {
+func (a *userResolverAdapter) ResolveUser(ctx context.Context, cognitoSub string) (middleware.ResolvedUser, error) {
 	user, err := a.repo.GetByCognitoSub(ctx, cognitoSub)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", middleware.ErrUserNotFound
+			return middleware.ResolvedUser{}, middleware.ErrUserNotFound
 		}
-		return "", fmt.Errorf("failed to resolve user: %w", err)
+		return middleware.ResolvedUser{}, fmt.Errorf("failed to resolve user: %w", err)
 	}
-	return user.ID, nil
+	return middleware.ResolvedUser{ID: user.ID, Language: user.Language}, nil
 }
 
 func main() {
@@ -58,9 +68,10 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	// Records logged with a request context carry its request and user IDs
+	logger := slog.New(middleware.NewContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: cfg.ParseLogLevel(),
-	}))
+	})))
 	slog.SetDefault(logger)
 
 	logger.Info("config loaded",
@@ -68,8 +79,30 @@ func run(ctx context.Context) error {
 		"port", cfg.ServerPort,
 		"auth_dev_mode", cfg.AuthDevMode,
 		"log_level", cfg.LogLevel,
+		"pubsub_backend", cfg.PubSubBackend,
+		"rate_limit_backend", cfg.RateLimitBackend,
+		"tracing_exporter", cfg.TracingExporter,
+		"openapi_validation", cfg.OpenAPIValidation,
 	)
 
+	shutdownTracing, err := telemetry.Setup(ctx, telemetry.Config{
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		ServiceName:  "todo-api",
+		Environment:  cfg.AppEnv,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		// Flush spans buffered during shutdown.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+
 	// Database connection
 	db, err := repository.NewDB(cfg.DB.DSN())
 	if err != nil {
@@ -77,13 +110,56 @@ func run(ctx context.Context) error {
 	}
 	defer db.Close()
 	logger.Info("database connected")
+	if err := metrics.RegisterDB(db, "todo"); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
 
 	// Repositories
 	todoRepo := repository.NewPostgresTodo(db)
 	userRepo := repository.NewPostgresUser(db)
+	depRepo := repository.NewPostgresTodoDependency(db)
+	timeRepo := repository.NewPostgresTimeEntry(db)
+	webhookRepo := repository.NewPostgresWebhook(db)
+	idempotencyRepo := repository.NewPostgresIdempotency(db)
+	rateLimitRepo := repository.NewPostgresRateLimit(db)
+
+	// Webhook delivery
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Config{AllowPrivateTargets: cfg.WebhookAllowPrivateTargets})
+
+	// Domain events, fanned out to every instance
+	var ps pubsub.PubSub
+	if cfg.PubSubBackend == "postgres" {
+		ps = pubsub.NewPostgres(db, cfg.DB.DSN(), logger)
+	} else {
+		ps = pubsub.NewMemory()
+	}
+	defer ps.Close()
+	eventBus := pubsub.NewEventBus(ps, todoRepo)
+
+	// Live event stream, fed from the bus so clients see writes made on
+	// other instances
+	broker := stream.NewBroker(stream.Config{})
+	unsubscribe, err := eventBus.Subscribe(broker.Publish)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe event stream: %w", err)
+	}
+	defer unsubscribe()
 
 	// Services
-	todoSvc := service.NewTodoService(todoRepo)
+	todoSvc := service.NewTodoService(todoRepo,
+		service.WithDependencies(depRepo),
+		// Webhooks are queued by the instance that made the change rather
+		// than from the bus, so each event is delivered once.
+		service.WithEventPublisher(dispatcher),
+		service.WithEventPublisher(eventBus),
+	)
+	timeSvc := service.NewTimeService(timeRepo, todoRepo, cfg.TimerMaxDuration)
+	var webhookOpts []service.WebhookServiceOption
+	if !cfg.WebhookAllowPrivateTargets {
+		webhookOpts = append(webhookOpts, service.WithWebhookTargetCheck(webhook.CheckHost))
+	}
+	webhookSvc := service.NewWebhookService(webhookRepo, dispatcher, webhookOpts...)
+	syncSvc := service.NewSyncService(todoSvc, todoRepo, service.WithTombstoneRetention(cfg.SyncTombstoneRetention))
 
 	// Cognito client + Auth service
 	var authSvc *service.AuthService
@@ -97,7 +173,7 @@ func run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		authSvc = service.NewAuthService(cognitoClient, userRepo)
+		authSvc = service.NewAuthService(cognitopkg.WithMetrics(cognitoClient), userRepo)
 		logger.Info("cognito client initialized", "region", cfg.Cognito.Region)
 	} else {
 		logger.Warn("cognito client not initialized: COGNITO_APP_CLIENT_ID not set")
@@ -119,12 +195,133 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("failed to create auth middleware: %w", err)
 	}
 
+	// Readiness: the database, the schema this build expects and, with JWT
+	// auth, the Cognito signing keys
+	schemaVersion, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	checker.Add("database", health.Ping(db))
+	checker.Add("migrations", health.SchemaVersion(func(ctx context.Context) (uint, bool, error) {
+		return repository.SchemaVersion(ctx, db)
+	}, schemaVersion))
+	if authCfg.JWKSClient != nil {
+		checker.Add("jwks", authCfg.JWKSClient.Ready)
+	}
+
+	// Idempotency-Key replay for endpoints that create things
+	idempotency, err := middleware.NewIdempotency(middleware.IdempotencyConfig{
+		Store:  idempotencyRepo,
+		Logger: logger,
+		TTL:    cfg.IdempotencyTTL,
+		Routes: []string{
+			"POST /api/v1/todos",
+			"POST /api/v1/sync",
+			"POST /api/v1/auth/signup",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency middleware: %w", err)
+	}
+
+	// Rate limits. The auth endpoints are limited by client IP so they
+	// cannot be hammered until Cognito itself starts refusing requests.
+	// The default limit applies to every other request, so its buckets stay
+	// in memory rather than costing a database round trip per request.
+	var rateLimitStore middleware.RateLimitStore = middleware.NewMemoryRateLimitStore()
+	if cfg.RateLimitBackend == "postgres" {
+		rateLimitStore = rateLimitRepo
+	}
+	rateLimiter, err := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Store:          rateLimitStore,
+		DefaultStore:   middleware.NewMemoryRateLimitStore(),
+		Logger:         logger,
+		TrustedProxies: cfg.TrustedProxies,
+		Routes: map[string]model.RateLimit{
+			"POST /api/v1/auth/login":                   {Limit: 10, Window: time.Minute},
+			"POST /api/v1/auth/signup":                  {Limit: 5, Window: time.Minute},
+			"POST /api/v1/auth/confirm-signup":          {Limit: 10, Window: time.Minute},
+			"POST /api/v1/auth/resend-code":             {Limit: 3, Window: 15 * time.Minute},
+			"POST /api/v1/auth/refresh":                 {Limit: 30, Window: time.Minute},
+			"POST /api/v1/auth/forgot-password":         {Limit: 5, Window: 15 * time.Minute},
+			"POST /api/v1/auth/confirm-forgot-password": {Limit: 5, Window: 15 * time.Minute},
+		},
+		Default: model.RateLimit{Limit: 600, Window: time.Minute},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create rate limiter: %w", err)
+	}
+
+	gqlExec, err := graphql.NewExecutor(todoSvc, service.NewUserService(userRepo), graphql.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+
 	// HTTP Server
-	srv := todohttp.NewServer(cfg.ServerPort, logger, todoSvc, authSvc, auth)
+	opts := []todohttp.RouterOption{
+		todohttp.WithTimeService(timeSvc),
+		todohttp.WithWebhookService(webhookSvc),
+		todohttp.WithEventStream(broker),
+		todohttp.WithSyncService(syncSvc),
+		todohttp.WithGraphQL(gqlExec),
+		todohttp.WithIdempotency(idempotency),
+		todohttp.WithRateLimiter(rateLimiter),
+		todohttp.WithHealthChecker(checker, cfg.HealthDetails),
+	}
+	opts = append(opts,
+		todohttp.WithCORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}),
+		todohttp.WithSecurityHeaders(middleware.SecurityHeadersConfig{HSTSMaxAge: cfg.HSTSMaxAge}),
+	)
+	if cfg.Compression {
+		opts = append(opts, todohttp.WithCompression(middleware.DefaultCompressMinSize))
+	}
+	if cfg.OpenAPIValidation {
+		doc, err := openapi.Load()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, todohttp.WithRequestValidation(doc))
+	}
+	var metricsSrv *todohttp.Server
+	if cfg.MetricsPort != "" {
+		metricsSrv = todohttp.NewMetricsServer(cfg.MetricsPort, logger)
+	} else {
+		opts = append(opts, todohttp.WithMetricsHandler(metrics.Handler()))
+	}
+	srv := todohttp.NewServer(cfg.ServerPort, logger, todoSvc, authSvc, auth, opts...)
+	srv.RegisterOnShutdown(broker.Close)
+
+	var grpcSrv *todogrpc.Server
+	if cfg.GRPCPort != "" {
+		grpcSrv = todogrpc.NewServer(cfg.GRPCPort, logger, todoSvc, auth)
+	}
 
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Stop timers left running past TIMER_MAX_DURATION
+	go timeSvc.RunAutoStop(ctx, time.Minute)
+	// Webhook deliveries keep being recorded while requests drain, so the
+	// dispatcher stops only after the servers have.
+	dispatchCtx, stopDispatch := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopDispatch()
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		dispatcher.Run(dispatchCtx)
+	}()
+	go idempotency.RunCleanup(ctx, time.Hour)
+	go syncSvc.RunTombstoneCleanup(ctx, time.Hour)
+	go rateLimiter.RunCleanup(ctx, 10*time.Minute)
+
 	go func() {
 		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Error("server failed", "error", err)
@@ -132,17 +329,60 @@ func run(ctx context.Context) error {
 		}
 	}()
 
+	if metricsSrv != nil {
+		go func() {
+			if err := metricsSrv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("metrics server failed", "error", err)
+				stop()
+			}
+		}()
+	}
+
+	if grpcSrv != nil {
+		go func() {
+			if err := grpcSrv.Start(); err != nil {
+				logger.Error("gRPC server failed", "error", err)
+				stop()
+			}
+		}()
+	}
+
 	logger.Info("server starting", "port", cfg.ServerPort)
 
 	<-ctx.Done()
 	logger.Info("shutdown signal received")
 
+	// Fail /health and readiness first so the load balancer stops sending new requests
+	// while the server is still accepting them
+	checker.SetDraining()
+	if cfg.ShutdownDrainDelay > 0 {
+		logger.Info("draining", "delay", cfg.ShutdownDrainDelay)
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		return err
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+	}
+	if grpcSrv != nil {
+		if err := grpcSrv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+	}
+
+	stopDispatch()
+	select {
+	case <-dispatchDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("webhook dispatcher did not stop in time")
+	}
 
 	logger.Info("server stopped gracefully")
 	return nil
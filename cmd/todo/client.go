@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// refreshBefore is how long before expiry a token is refreshed.
+const refreshBefore = time.Minute
+
+// errNotLoggedIn is returned for API calls made without stored credentials.
+var errNotLoggedIn = errors.New("not logged in; run `todo login`")
+
+// apiError is an error response from the API.
+type apiError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Fields  []struct {
+		Field  string `json:"field"`
+		Reason string `json:"reason"`
+	} `json:"fields"`
+}
+
+func (e *apiError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)", msg, e.Code)
+	for _, f := range e.Fields {
+		fmt.Fprintf(&b, "\n  %s: %s", f.Field, f.Reason)
+	}
+	return b.String()
+}
+
+// mergePatch is a request body sent as a JSON Merge Patch, in which null
+// removes a field.
+type mergePatch map[string]any
+
+// client calls the API as the user in creds, refreshing the token when it
+// is about to expire or is rejected.
+type client struct {
+	http  *http.Client
+	store *credentialStore
+	creds *credentials
+	now   func() time.Time
+}
+
+func newClient(store *credentialStore) (*client, error) {
+	creds, err := store.load()
+	if err != nil {
+		return nil, err
+	}
+	return &client{http: &http.Client{Timeout: 30 * time.Second}, store: store, creds: creds, now: time.Now}, nil
+}
+
+// do sends a request with a JSON body and decodes a JSON response into out.
+// Either may be nil.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	if c.creds == nil {
+		return errNotLoggedIn
+	}
+	if c.creds.UserID == "" && c.now().Add(refreshBefore).After(c.creds.ExpiresAt) {
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.send(ctx, method, path, query, in, true)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.creds.UserID == "" {
+		resp.Body.Close()
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+		if resp, err = c.send(ctx, method, path, query, in, true); err != nil {
+			return err
+		}
+	}
+	return decodeResponse(resp, out)
+}
+
+// send makes one request. With auth, it carries the stored credentials.
+func (c *client) send(ctx context.Context, method, path string, query url.Values, in any, auth bool) (*http.Response, error) {
+	u := strings.TrimRight(c.creds.APIURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if _, ok := in.(mergePatch); ok {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	} else if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth {
+		if c.creds.UserID != "" {
+			req.Header.Set("X-User-ID", c.creds.UserID)
+		} else {
+			// The API checks the token's audience, which Cognito sets on
+			// ID tokens.
+			req.Header.Set("Authorization", "Bearer "+c.creds.IDToken)
+		}
+	}
+	return c.http.Do(req)
+}
+
+// refresh exchanges the refresh token for new tokens and stores them.
+func (c *client) refresh(ctx context.Context) error {
+	if c.creds.RefreshToken == "" {
+		return errNotLoggedIn
+	}
+	in := map[string]string{"email": c.creds.Email, "refresh_token": c.creds.RefreshToken}
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/auth/refresh", nil, in, false)
+	if err != nil {
+		return err
+	}
+
+	var out tokenResponse
+	if err := decodeResponse(resp, &out); err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized {
+			return fmt.Errorf("session expired; run `todo login`: %w", err)
+		}
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	c.creds.IDToken = out.IDToken
+	c.creds.AccessToken = out.AccessToken
+	c.creds.ExpiresAt = c.now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	return c.store.save(c.creds)
+}
+
+// tokenResponse is the body of /api/v1/auth/login and /api/v1/auth/refresh.
+// Refresh does not return a new refresh token.
+type tokenResponse struct {
+	IDToken      string `json:"id_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// login signs in with a password and returns the credentials to store.
+func login(ctx context.Context, httpClient *http.Client, apiURL, email, password string, now time.Time) (*credentials, error) {
+	c := &client{http: httpClient, creds: &credentials{APIURL: apiURL}}
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/auth/login", nil, map[string]string{"email": email, "password": password}, false)
+	if err != nil {
+		return nil, err
+	}
+	var out tokenResponse
+	if err := decodeResponse(resp, &out); err != nil {
+		return nil, err
+	}
+	return &credentials{
+		APIURL:       apiURL,
+		Email:        email,
+		IDToken:      out.IDToken,
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		ExpiresAt:    now.Add(time.Duration(out.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var body struct {
+			Error apiError `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		body.Error.Status = resp.StatusCode
+		return &body.Error
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
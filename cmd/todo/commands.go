@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newLoginCmd(a *app) *cobra.Command {
+	var email, devUser string
+	var passwordStdin bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Sign in and store the session",
+		Long: "Sign in with your email and password. The password is prompted for, or read from stdin " +
+			"with --password-stdin. With --dev-user, requests are sent as that user to a server in AUTH_DEV_MODE.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if devUser != "" {
+				if err := a.store.save(&credentials{APIURL: a.apiURL, UserID: devUser}); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Using %s as %s\n", a.apiURL, devUser)
+				return nil
+			}
+
+			if email == "" {
+				return fmt.Errorf("--email is required")
+			}
+			password, err := a.readPassword(cmd, passwordStdin)
+			if err != nil {
+				return err
+			}
+			creds, err := login(cmd.Context(), a.http, a.apiURL, email, password, a.now())
+			if err != nil {
+				return err
+			}
+			if err := a.store.save(creds); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s\n", a.apiURL, email)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "account email")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from stdin")
+	cmd.Flags().StringVar(&devUser, "dev-user", "", "send requests as this user ID (servers in AUTH_DEV_MODE only)")
+	return cmd
+}
+
+// readPassword prompts for the password without echo on a terminal, and
+// otherwise reads the first line of stdin.
+func (a *app) readPassword(cmd *cobra.Command, fromStdin bool) (string, error) {
+	if f, ok := a.stdin.(*os.File); ok && !fromStdin && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(cmd.ErrOrStderr(), "Password: ")
+		b, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(b), nil
+	}
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func newLogoutCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Sign out everywhere and forget the session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			if c.creds != nil && c.creds.UserID == "" {
+				in := map[string]string{"access_token": c.creds.AccessToken}
+				if err := c.do(cmd.Context(), http.MethodPost, "/api/v1/auth/logout", nil, in, nil); err != nil {
+					fmt.Fprintln(cmd.ErrOrStderr(), "warning: sign-out failed:", err)
+				}
+			}
+			if err := a.store.remove(); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Logged out")
+			return nil
+		},
+	}
+}
+
+// todoFlags are the editable fields of a todo.
+type todoFlags struct {
+	title       string
+	description string
+	due         string
+	priority    string
+	important   bool
+	urgent      bool
+	estimate    int
+}
+
+func (f *todoFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.description, "description", "d", "", "description")
+	cmd.Flags().StringVar(&f.due, "due", "", "due date: today, tomorrow, YYYY-MM-DD or RFC 3339")
+	cmd.Flags().StringVarP(&f.priority, "priority", "p", "", "priority: none, low, medium, high or urgent")
+	cmd.Flags().BoolVar(&f.important, "important", false, "mark as important")
+	cmd.Flags().BoolVar(&f.urgent, "urgent", false, "mark as urgent")
+	cmd.Flags().IntVar(&f.estimate, "estimate", 0, "estimate in minutes")
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletions("none", "low", "medium", "high", "urgent"))
+	_ = cmd.RegisterFlagCompletionFunc("due", fixedCompletions("today", "tomorrow"))
+}
+
+// fields returns the fields whose flags were given, as the API names them.
+func (f *todoFlags) fields(cmd *cobra.Command, a *app) (map[string]any, error) {
+	fields := make(map[string]any)
+	set := func(flag, field string, value any) {
+		if cmd.Flags().Changed(flag) {
+			fields[field] = value
+		}
+	}
+	set("title", "title", f.title)
+	set("description", "description", f.description)
+	set("priority", "priority", f.priority)
+	set("important", "important", f.important)
+	set("urgent", "urgent", f.urgent)
+	set("estimate", "estimate_minutes", f.estimate)
+	if cmd.Flags().Changed("due") {
+		due, err := parseDue(f.due, a.now())
+		if err != nil {
+			return nil, err
+		}
+		fields["due_at"] = due
+	}
+	return fields, nil
+}
+
+func newAddCmd(a *app) *cobra.Command {
+	var f todoFlags
+	cmd := &cobra.Command{
+		Use:   "add <title>...",
+		Short: "Create a todo",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := f.fields(cmd, a)
+			if err != nil {
+				return err
+			}
+			body["title"] = strings.Join(args, " ")
+
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			var todo json.RawMessage
+			if err := c.do(cmd.Context(), http.MethodPost, "/api/v1/todos", nil, body, &todo); err != nil {
+				return err
+			}
+			return a.printTodo(cmd, todo)
+		},
+	}
+	f.register(cmd)
+	return cmd
+}
+
+func newListCmd(a *app) *cobra.Command {
+	var status, priority, sort, cursor, blocked string
+	var limit int
+	var all bool
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List todos",
+		Long: "List todos a page at a time. When there are more, the cursor for the next page is printed; " +
+			"pass it with --cursor, or use --all to fetch every page.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			for key, value := range map[string]string{"status": status, "priority": priority, "sort": sort, "blocked": blocked} {
+				if value != "" {
+					query.Set(key, value)
+				}
+			}
+			if limit > 0 {
+				query.Set("limit", strconv.Itoa(limit))
+			}
+
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			var todos []json.RawMessage
+			next := cursor
+			for {
+				if next != "" {
+					query.Set("cursor", next)
+				}
+				var page struct {
+					Todos      []json.RawMessage `json:"todos"`
+					NextCursor string            `json:"next_cursor"`
+				}
+				if err := c.do(cmd.Context(), http.MethodGet, "/api/v1/todos", query, nil, &page); err != nil {
+					return err
+				}
+				todos = append(todos, page.Todos...)
+				next = page.NextCursor
+				if !all || next == "" {
+					break
+				}
+			}
+			return a.printList(cmd, todos, next)
+		},
+	}
+	cmd.Flags().StringVar(&status, "status", "", "only todos with this status: pending or completed")
+	cmd.Flags().StringVarP(&priority, "priority", "p", "", "only todos with this priority")
+	cmd.Flags().StringVar(&sort, "sort", "", "order: created_at or priority")
+	cmd.Flags().StringVar(&blocked, "blocked", "", "only todos that are (true) or are not (false) blocked")
+	cmd.Flags().StringVar(&cursor, "cursor", "", "start after this cursor, from a previous page")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "page size, up to 100 (default 20)")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "fetch every page")
+	_ = cmd.RegisterFlagCompletionFunc("status", fixedCompletions("pending", "completed"))
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletions("none", "low", "medium", "high", "urgent"))
+	_ = cmd.RegisterFlagCompletionFunc("sort", fixedCompletions("created_at", "priority"))
+	_ = cmd.RegisterFlagCompletionFunc("blocked", fixedCompletions("true", "false"))
+	return cmd
+}
+
+func newShowCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "show <id>",
+		Short:             "Show a todo",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeIDs(""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			var todo json.RawMessage
+			if err := c.do(cmd.Context(), http.MethodGet, todoPath(args[0]), nil, nil, &todo); err != nil {
+				return err
+			}
+			return a.printTodo(cmd, todo)
+		},
+	}
+}
+
+func newEditCmd(a *app) *cobra.Command {
+	var f todoFlags
+	var clearDue, clearEstimate bool
+	cmd := &cobra.Command{
+		Use:               "edit <id>",
+		Short:             "Change the given fields of a todo",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeIDs(""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			patch, err := f.fields(cmd, a)
+			if err != nil {
+				return err
+			}
+			if clearDue {
+				patch["due_at"] = nil
+			}
+			if clearEstimate {
+				patch["estimate_minutes"] = nil
+			}
+			if len(patch) == 0 {
+				return fmt.Errorf("nothing to change; see `todo edit --help`")
+			}
+
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			var todo json.RawMessage
+			if err := c.do(cmd.Context(), http.MethodPatch, todoPath(args[0]), nil, mergePatch(patch), &todo); err != nil {
+				return err
+			}
+			return a.printTodo(cmd, todo)
+		},
+	}
+	f.register(cmd)
+	cmd.Flags().StringVar(&f.title, "title", "", "title")
+	cmd.Flags().BoolVar(&clearDue, "clear-due", false, "remove the due date")
+	cmd.Flags().BoolVar(&clearEstimate, "clear-estimate", false, "remove the estimate")
+	cmd.MarkFlagsMutuallyExclusive("due", "clear-due")
+	cmd.MarkFlagsMutuallyExclusive("estimate", "clear-estimate")
+	return cmd
+}
+
+// newStatusCmd returns a command that sets a todo's status.
+func newStatusCmd(a *app, use, short, status string) *cobra.Command {
+	var force bool
+	other := "pending"
+	if status == "pending" {
+		other = "completed"
+	}
+	cmd := &cobra.Command{
+		Use:               use + " <id>",
+		Short:             short,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeIDs(other),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			body := map[string]any{"status": status}
+			if force {
+				body["force"] = true
+			}
+			var todo json.RawMessage
+			if err := c.do(cmd.Context(), http.MethodPatch, todoPath(args[0])+"/status", nil, body, &todo); err != nil {
+				return err
+			}
+			return a.printTodo(cmd, todo)
+		},
+	}
+	if status == "completed" {
+		cmd.Flags().BoolVar(&force, "force", false, "complete even if blocked by incomplete todos")
+	}
+	return cmd
+}
+
+func newRemoveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm <id>...",
+		Aliases:           []string{"delete"},
+		Short:             "Delete todos",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: a.completeIDs(""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client(cmd)
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if err := c.do(cmd.Context(), http.MethodDelete, todoPath(id), nil, nil, nil); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+				if a.output == outputTable {
+					fmt.Fprintln(cmd.OutOrStdout(), "Deleted", id)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func todoPath(id string) string {
+	return "/api/v1/todos/" + url.PathEscape(id)
+}
+
+// completeIDs completes todo IDs, described by their titles. With status,
+// only todos in that status are offered.
+func (a *app) completeIDs(status string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c, err := a.client(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		query := url.Values{"limit": {"100"}}
+		if status != "" {
+			query.Set("status", status)
+		}
+		var page struct {
+			Todos []todo `json:"todos"`
+		}
+		if err := c.do(cmd.Context(), http.MethodGet, "/api/v1/todos", query, nil, &page); err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var ids []string
+		for _, t := range page.Todos {
+			if strings.HasPrefix(t.ID, toComplete) {
+				ids = append(ids, t.ID+"\t"+t.Title)
+			}
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// credentials are what login stores between runs.
+type credentials struct {
+	APIURL string `json:"api_url"`
+	Email  string `json:"email,omitempty"`
+	// UserID is sent as X-User-ID instead of a token, for servers in
+	// AUTH_DEV_MODE.
+	UserID       string    `json:"user_id,omitempty"`
+	IDToken      string    `json:"id_token,omitempty"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// credentialStore keeps credentials in a file only the user can read.
+type credentialStore struct {
+	path string
+}
+
+// newCredentialStore stores credentials under TODO_CONFIG_DIR, or todo/ in
+// the user's config directory.
+func newCredentialStore() (*credentialStore, error) {
+	dir := os.Getenv("TODO_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find config directory: %w", err)
+		}
+		dir = filepath.Join(base, "todo")
+	}
+	return &credentialStore{path: filepath.Join(dir, "credentials.json")}, nil
+}
+
+// load returns the stored credentials, or nil when there are none.
+func (s *credentialStore) load() (*credentials, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to read credentials %s: %w", s.path, err)
+	}
+	return &creds, nil
+}
+
+// save replaces the stored credentials. The file is written in full before
+// it replaces the old one, and is readable only by the user.
+func (s *credentialStore) save(creds *credentials) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the stored credentials, if any.
+func (s *credentialStore) remove() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Command todo is a command-line client for the Todo API.
+//
+//	todo login --email me@example.com
+//	todo add "write report" --due tomorrow
+//	todo ls --status pending
+//	todo done <id>
+//
+// Credentials are kept in the user's config directory (TODO_CONFIG_DIR
+// overrides it) and tokens are refreshed as they expire. `todo completion`
+// prints shell completion scripts.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := newCredentialStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	a := &app{store: store, http: &http.Client{Timeout: 30 * time.Second}, now: time.Now, stdin: os.Stdin}
+	if err := newRootCmd(a).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// app is the state shared by the commands.
+type app struct {
+	store *credentialStore
+	http  *http.Client
+	now   func() time.Time
+	stdin io.Reader
+
+	// Set by the global flags.
+	apiURL string
+	output string
+}
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// defaultAPIURL is the server login uses unless --api-url or TODO_API_URL
+// says otherwise.
+const defaultAPIURL = "http://localhost:8080"
+
+func newRootCmd(a *app) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "todo",
+		Short:         "Manage your todos from the terminal",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if a.output != outputTable && a.output != outputJSON {
+				return fmt.Errorf("invalid --output %q: must be table or json", a.output)
+			}
+			return nil
+		},
+	}
+
+	apiURL := os.Getenv("TODO_API_URL")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	root.PersistentFlags().StringVar(&a.apiURL, "api-url", apiURL, "API base URL (env TODO_API_URL)")
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", outputTable, "output format: table or json")
+	_ = root.RegisterFlagCompletionFunc("output", fixedCompletions(outputTable, outputJSON))
+
+	root.AddCommand(
+		newLoginCmd(a),
+		newLogoutCmd(a),
+		newAddCmd(a),
+		newListCmd(a),
+		newShowCmd(a),
+		newEditCmd(a),
+		newStatusCmd(a, "done", "Complete a todo", "completed"),
+		newStatusCmd(a, "undo", "Reopen a completed todo", "pending"),
+		newRemoveCmd(a),
+	)
+	return root
+}
+
+// client returns an API client for the stored credentials, which are only
+// sent to the server that issued them. An --api-url naming another server
+// is an error rather than a way to send them elsewhere.
+func (a *app) client(cmd *cobra.Command) (*client, error) {
+	c, err := newClient(a.store)
+	if err != nil {
+		return nil, err
+	}
+	c.http = a.http
+	c.now = a.now
+	if c.creds != nil && cmd.Flags().Changed("api-url") && !sameAPIURL(a.apiURL, c.creds.APIURL) {
+		return nil, fmt.Errorf("logged in to %s, not %s; run `todo login --api-url %s` to switch servers",
+			c.creds.APIURL, a.apiURL, a.apiURL)
+	}
+	return c, nil
+}
+
+// sameAPIURL reports whether a and b name the same API base URL.
+func sameAPIURL(a, b string) bool {
+	return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+}
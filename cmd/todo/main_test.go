@@ -0,0 +1,536 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+// fakeAPI serves the auth and todo endpoints the CLI uses.
+type fakeAPI struct {
+	mu       sync.Mutex
+	token    string // the ID token todo requests must carry
+	refresh  int    // refresh calls
+	todos    []map[string]any
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	method, path, query, contentType string
+	body                             map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]any
+	b, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(b, &body)
+	f.requests = append(f.requests, recordedRequest{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("Content-Type"), body})
+
+	writeJSON := func(status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	writeError := func(status int, code, message string) {
+		writeJSON(status, map[string]any{"error": map[string]any{"code": code, "message": message}})
+	}
+
+	switch r.URL.Path {
+	case "/api/v1/auth/login":
+		if body["password"] != "secret" {
+			writeError(http.StatusUnauthorized, "NOT_AUTHORIZED", "incorrect email or password")
+			return
+		}
+		f.token = "id-1"
+		writeJSON(http.StatusOK, map[string]any{"id_token": "id-1", "access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600})
+		return
+	case "/api/v1/auth/refresh":
+		if body["refresh_token"] != "refresh-1" {
+			writeError(http.StatusUnauthorized, "NOT_AUTHORIZED", "invalid refresh token")
+			return
+		}
+		f.refresh++
+		f.token = fmt.Sprintf("id-refreshed-%d", f.refresh)
+		writeJSON(http.StatusOK, map[string]any{"id_token": f.token, "access_token": "access-2", "expires_in": 3600})
+		return
+	case "/api/v1/auth/logout":
+		writeJSON(http.StatusOK, map[string]any{"message": "signed out"})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+f.token {
+		writeError(http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired token")
+		return
+	}
+
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1/todos"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		// Pages of two, with the last ID as the cursor.
+		start := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			for i, t := range f.todos {
+				if t["id"] == cursor {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+2, len(f.todos))
+		page := map[string]any{"todos": f.todos[start:end]}
+		if end < len(f.todos) {
+			page["next_cursor"] = f.todos[end-1]["id"]
+		}
+		writeJSON(http.StatusOK, page)
+	case id == "" && r.Method == http.MethodPost:
+		if body["title"] == "" {
+			writeJSON(http.StatusBadRequest, map[string]any{"error": map[string]any{"code": "INVALID_INPUT", "message": "invalid input",
+				"fields": []map[string]string{{"field": "title", "reason": "required"}}}})
+			return
+		}
+		todo := map[string]any{"id": fmt.Sprintf("todo-%d", len(f.todos)+1), "status": "pending", "priority": "none"}
+		for k, v := range body {
+			todo[k] = v
+		}
+		f.todos = append(f.todos, todo)
+		writeJSON(http.StatusCreated, todo)
+	case strings.HasSuffix(id, "/status"):
+		writeJSON(http.StatusOK, map[string]any{"id": strings.TrimSuffix(id, "/status"), "title": "t", "status": body["status"]})
+	case r.Method == http.MethodPatch:
+		writeJSON(http.StatusOK, map[string]any{"id": id, "title": "t", "status": "pending"})
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	case id == "missing":
+		writeError(http.StatusNotFound, "NOT_FOUND", "resource not found")
+	default:
+		writeJSON(http.StatusOK, map[string]any{"id": id, "title": "Write report", "status": "pending", "priority": "high",
+			"due_at": "2026-05-05T23:59:59Z", "blocked_by": []string{"todo-9"}})
+	}
+}
+
+func (f *fakeAPI) last() recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+type harness struct {
+	t     *testing.T
+	api   *fakeAPI
+	url   string
+	store *credentialStore
+	now   time.Time
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	api := &fakeAPI{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return &harness{
+		t:     t,
+		api:   api,
+		url:   srv.URL,
+		store: &credentialStore{path: filepath.Join(t.TempDir(), "todo", "credentials.json")},
+		now:   now,
+	}
+}
+
+// run runs the CLI with args and returns its output.
+func (h *harness) run(stdin string, args ...string) (string, error) {
+	h.t.Helper()
+	a := &app{store: h.store, http: http.DefaultClient, now: func() time.Time { return h.now }, stdin: strings.NewReader(stdin)}
+	cmd := newRootCmd(a)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"--api-url", h.url}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func (h *harness) mustRun(args ...string) string {
+	h.t.Helper()
+	out, err := h.run("", args...)
+	if err != nil {
+		h.t.Fatalf("todo %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+func (h *harness) login() {
+	h.t.Helper()
+	if out, err := h.run("secret\n", "login", "--email", "me@example.com"); err != nil {
+		h.t.Fatalf("login: %v\n%s", err, out)
+	}
+}
+
+func TestLogin_StoresCredentialsPrivately(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	info, err := os.Stat(h.store.path)
+	if err != nil {
+		t.Fatalf("credentials not saved: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected credentials mode 0600, got %o", perm)
+	}
+	if dir, _ := os.Stat(filepath.Dir(h.store.path)); dir.Mode().Perm() != 0o700 {
+		t.Errorf("expected config dir mode 0700, got %o", dir.Mode().Perm())
+	}
+
+	creds, err := h.store.load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if creds.IDToken != "id-1" || creds.RefreshToken != "refresh-1" || creds.Email != "me@example.com" ||
+		creds.APIURL != h.url || !creds.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	h := newHarness(t)
+
+	_, err := h.run("wrong\n", "login", "--email", "me@example.com")
+	if err == nil || !strings.Contains(err.Error(), "incorrect email or password") {
+		t.Errorf("expected login error, got %v", err)
+	}
+	if creds, _ := h.store.load(); creds != nil {
+		t.Errorf("expected no stored credentials, got %+v", creds)
+	}
+}
+
+func TestNotLoggedIn(t *testing.T) {
+	h := newHarness(t)
+
+	if _, err := h.run("", "ls"); err != errNotLoggedIn {
+		t.Errorf("expected errNotLoggedIn, got %v", err)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	out := h.mustRun("add", "write", "report", "--due", "tomorrow", "-p", "high", "--important", "--estimate", "30")
+
+	req := h.api.last()
+	if req.method != http.MethodPost || req.path != "/api/v1/todos" {
+		t.Fatalf("unexpected request %s %s", req.method, req.path)
+	}
+	want := map[string]any{"title": "write report", "due_at": "2026-05-05T23:59:59Z", "priority": "high", "important": true, "estimate_minutes": float64(30)}
+	for k, v := range want {
+		if req.body[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, req.body[k])
+		}
+	}
+	if _, ok := req.body["urgent"]; ok {
+		t.Errorf("expected flags not given to be left out, got %v", req.body)
+	}
+	if !strings.Contains(out, "todo-1") || !strings.Contains(out, "important") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestAdd_ValidationErrors(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	_, err := h.run("", "add", "")
+	if err == nil || !strings.Contains(err.Error(), "INVALID_INPUT") || !strings.Contains(err.Error(), "title: required") {
+		t.Errorf("expected field errors, got %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+	for _, title := range []string{"one", "two", "three"} {
+		h.mustRun("add", title)
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		out := h.mustRun("ls", "--status", "pending")
+		if q := h.api.last().query; !strings.Contains(q, "status=pending") {
+			t.Errorf("expected status filter, got query %q", q)
+		}
+		if !strings.Contains(out, "one") || !strings.Contains(out, "two") || strings.Contains(out, "three") {
+			t.Errorf("expected the first page, got:\n%s", out)
+		}
+		if !strings.Contains(out, "todo ls --cursor todo-2") {
+			t.Errorf("expected the next cursor, got:\n%s", out)
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		out := h.mustRun("ls", "--cursor", "todo-2")
+		if !strings.Contains(out, "three") || strings.Contains(out, "--cursor") {
+			t.Errorf("expected the last page, got:\n%s", out)
+		}
+	})
+
+	t.Run("all pages as JSON", func(t *testing.T) {
+		out := h.mustRun("ls", "--all", "-o", "json")
+		var page struct {
+			Todos      []map[string]any `json:"todos"`
+			NextCursor string           `json:"next_cursor"`
+		}
+		if err := json.Unmarshal([]byte(out), &page); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if len(page.Todos) != 3 || page.NextCursor != "" {
+			t.Errorf("expected all 3 todos, got %d (next %q)", len(page.Todos), page.NextCursor)
+		}
+	})
+}
+
+func TestShow(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	out := h.mustRun("show", "todo-1")
+	for _, want := range []string{"Write report", "high", "todo-9"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	_, err := h.run("", "show", "missing")
+	if err == nil || !strings.Contains(err.Error(), "resource not found (NOT_FOUND)") {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestEdit_SendsMergePatch(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	h.mustRun("edit", "todo-1", "--title", "Send report", "--clear-due")
+
+	req := h.api.last()
+	if req.method != http.MethodPatch || req.contentType != "application/merge-patch+json" {
+		t.Errorf("expected a merge patch, got %s with %q", req.method, req.contentType)
+	}
+	if req.body["title"] != "Send report" {
+		t.Errorf("expected title, got %v", req.body)
+	}
+	if v, ok := req.body["due_at"]; !ok || v != nil {
+		t.Errorf("expected due_at null, got %v", req.body)
+	}
+
+	if _, err := h.run("", "edit", "todo-1"); err == nil {
+		t.Error("expected an error when nothing changes")
+	}
+}
+
+func TestStatusAndRemove(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	tests := []struct {
+		args       []string
+		wantMethod string
+		wantPath   string
+		wantBody   map[string]any
+	}{
+		{[]string{"done", "todo-1"}, http.MethodPatch, "/api/v1/todos/todo-1/status", map[string]any{"status": "completed"}},
+		{[]string{"done", "todo-1", "--force"}, http.MethodPatch, "/api/v1/todos/todo-1/status", map[string]any{"status": "completed", "force": true}},
+		{[]string{"undo", "todo-1"}, http.MethodPatch, "/api/v1/todos/todo-1/status", map[string]any{"status": "pending"}},
+		{[]string{"rm", "todo-1"}, http.MethodDelete, "/api/v1/todos/todo-1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			h.mustRun(tt.args...)
+
+			req := h.api.last()
+			if req.method != tt.wantMethod || req.path != tt.wantPath {
+				t.Errorf("expected %s %s, got %s %s", tt.wantMethod, tt.wantPath, req.method, req.path)
+			}
+			if len(req.body) != len(tt.wantBody) {
+				t.Errorf("expected body %v, got %v", tt.wantBody, req.body)
+			}
+			for k, v := range tt.wantBody {
+				if req.body[k] != v {
+					t.Errorf("expected %s=%v, got %v", k, v, req.body[k])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	t.Run("before expiry", func(t *testing.T) {
+		h := newHarness(t)
+		h.login()
+		h.now = now.Add(time.Hour - 30*time.Second)
+
+		h.mustRun("ls")
+
+		if h.api.refresh != 1 {
+			t.Errorf("expected 1 refresh, got %d", h.api.refresh)
+		}
+		creds, _ := h.store.load()
+		if creds.IDToken != "id-refreshed-1" || creds.RefreshToken != "refresh-1" || !creds.ExpiresAt.Equal(h.now.Add(time.Hour)) {
+			t.Errorf("expected refreshed credentials to be saved, got %+v", creds)
+		}
+	})
+
+	t.Run("after rejection", func(t *testing.T) {
+		h := newHarness(t)
+		h.login()
+		h.api.token = "revoked-elsewhere"
+
+		h.mustRun("ls")
+
+		if h.api.refresh != 1 {
+			t.Errorf("expected 1 refresh, got %d", h.api.refresh)
+		}
+	})
+
+	t.Run("refresh token rejected", func(t *testing.T) {
+		h := newHarness(t)
+		h.login()
+		creds, _ := h.store.load()
+		creds.RefreshToken = "revoked"
+		creds.ExpiresAt = now.Add(-time.Minute)
+		if err := h.store.save(creds); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+
+		_, err := h.run("", "ls")
+		if err == nil || !strings.Contains(err.Error(), "run `todo login`") {
+			t.Errorf("expected a prompt to log in again, got %v", err)
+		}
+	})
+}
+
+// TestOtherAPIURL runs a command against a server other than the one the
+// user logged in to, and expects the stored tokens to stay where they were.
+func TestOtherAPIURL(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+	other := &fakeAPI{}
+	srv := httptest.NewServer(other)
+	t.Cleanup(srv.Close)
+
+	_, err := h.run("", "--api-url", srv.URL, "ls")
+	if err == nil || !strings.Contains(err.Error(), "logged in to "+h.url) {
+		t.Fatalf("expected an error naming the stored server, got %v", err)
+	}
+	if len(other.requests) != 0 {
+		t.Errorf("expected no requests to %s, got %d", srv.URL, len(other.requests))
+	}
+	if creds, _ := h.store.load(); creds == nil || creds.APIURL != h.url {
+		t.Errorf("expected credentials for %s to be kept, got %+v", h.url, creds)
+	}
+
+	// The server the tokens were issued by may be written with a trailing slash.
+	h.mustRun("--api-url", h.url+"/", "ls")
+}
+
+func TestLogout(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	h.mustRun("logout")
+
+	if req := h.api.last(); req.path != "/api/v1/auth/logout" || req.body["access_token"] != "access-1" {
+		t.Errorf("expected sign-out with the access token, got %s %v", req.path, req.body)
+	}
+	if creds, _ := h.store.load(); creds != nil {
+		t.Errorf("expected credentials removed, got %+v", creds)
+	}
+}
+
+func TestDevUser(t *testing.T) {
+	h := newHarness(t)
+	var gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = r.Header.Get("X-User-ID")
+		_, _ = w.Write([]byte(`{"todos":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	h.url = srv.URL
+
+	h.mustRun("login", "--dev-user", "user-1")
+	out := h.mustRun("ls")
+
+	if gotUser != "user-1" {
+		t.Errorf("expected X-User-ID user-1, got %q", gotUser)
+	}
+	if !strings.Contains(out, "No todos") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+	h.mustRun("add", "write report")
+
+	t.Run("script", func(t *testing.T) {
+		out := h.mustRun("completion", "bash")
+		if !strings.Contains(out, "__start_todo") {
+			t.Errorf("expected a bash completion script, got:\n%.200s", out)
+		}
+	})
+
+	t.Run("todo IDs", func(t *testing.T) {
+		out := h.mustRun("__complete", "done", "")
+		if !strings.Contains(out, "todo-1\twrite report") {
+			t.Errorf("expected todo IDs with titles, got:\n%s", out)
+		}
+		if q := h.api.last().query; !strings.Contains(q, "status=pending") {
+			t.Errorf("expected only pending todos offered, got query %q", q)
+		}
+	})
+}
+
+func TestParseDue(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"today", "2026-05-04T23:59:59Z", false},
+		{"Tomorrow", "2026-05-05T23:59:59Z", false},
+		{"2026-06-01", "2026-06-01T23:59:59Z", false},
+		{"2026-06-01T09:30:00+09:00", "2026-06-01T09:30:00+09:00", false},
+		{"next week", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDue(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInvalidOutput(t *testing.T) {
+	h := newHarness(t)
+	h.login()
+
+	if _, err := h.run("", "ls", "-o", "yaml"); err == nil || !strings.Contains(err.Error(), "invalid --output") {
+		t.Errorf("expected an output error, got %v", err)
+	}
+}
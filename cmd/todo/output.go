@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// todo is the part of a todo the table shows.
+type todo struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	Status          string     `json:"status"`
+	Priority        string     `json:"priority"`
+	Important       bool       `json:"important"`
+	Urgent          bool       `json:"urgent"`
+	DueAt           *time.Time `json:"due_at"`
+	EstimateMinutes *int       `json:"estimate_minutes"`
+	BlockedBy       []string   `json:"blocked_by"`
+}
+
+// printTodo prints one todo: its fields in table mode, or the API's JSON.
+func (a *app) printTodo(cmd *cobra.Command, raw json.RawMessage) error {
+	out := cmd.OutOrStdout()
+	if a.output == outputJSON {
+		return writeJSON(cmd, raw)
+	}
+
+	var t todo
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	row := func(k, v string) { fmt.Fprintf(w, "%s:\t%s\n", k, v) }
+	row("ID", t.ID)
+	row("Title", t.Title)
+	if t.Description != "" {
+		row("Description", t.Description)
+	}
+	row("Status", t.Status)
+	row("Priority", t.Priority)
+	if flags := t.flags(); flags != "" {
+		row("Flags", flags)
+	}
+	if t.DueAt != nil {
+		row("Due", formatDue(*t.DueAt))
+	}
+	if t.EstimateMinutes != nil {
+		row("Estimate", strconv.Itoa(*t.EstimateMinutes)+"m")
+	}
+	if len(t.BlockedBy) > 0 {
+		row("Blocked by", strings.Join(t.BlockedBy, ", "))
+	}
+	return w.Flush()
+}
+
+// printList prints todos as a table, or as the API's JSON page. next is
+// the cursor of the following page, if there is one.
+func (a *app) printList(cmd *cobra.Command, raw []json.RawMessage, next string) error {
+	out := cmd.OutOrStdout()
+	if raw == nil {
+		raw = []json.RawMessage{}
+	}
+	if a.output == outputJSON {
+		page := struct {
+			Todos      []json.RawMessage `json:"todos"`
+			NextCursor string            `json:"next_cursor,omitempty"`
+		}{raw, next}
+		b, err := json.Marshal(page)
+		if err != nil {
+			return err
+		}
+		return writeJSON(cmd, b)
+	}
+
+	if len(raw) == 0 {
+		fmt.Fprintln(out, "No todos")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tPRIORITY\tDUE\tTITLE")
+	for _, r := range raw {
+		var t todo
+		if err := json.Unmarshal(r, &t); err != nil {
+			return err
+		}
+		due := "-"
+		if t.DueAt != nil {
+			due = formatDue(*t.DueAt)
+		}
+		title := t.Title
+		if flags := t.flags(); flags != "" {
+			title += " [" + flags + "]"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.Status, t.Priority, due, title)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if next != "" {
+		fmt.Fprintf(out, "\nMore: todo ls --cursor %s\n", next)
+	}
+	return nil
+}
+
+func (t todo) flags() string {
+	var flags []string
+	if t.Important {
+		flags = append(flags, "important")
+	}
+	if t.Urgent {
+		flags = append(flags, "urgent")
+	}
+	return strings.Join(flags, ", ")
+}
+
+func writeJSON(cmd *cobra.Command, raw []byte) error {
+	var b bytes.Buffer
+	if err := json.Indent(&b, raw, "", "  "); err != nil {
+		return err
+	}
+	b.WriteByte('\n')
+	_, err := cmd.OutOrStdout().Write(b.Bytes())
+	return err
+}
+
+func formatDue(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// parseDue reads a due date: "today", "tomorrow", a YYYY-MM-DD date or an
+// RFC 3339 time. A date without a time is due at the end of that day, in
+// local time. The result is RFC 3339, as the API takes it.
+func parseDue(s string, now time.Time) (string, error) {
+	endOfDay := func(t time.Time) string {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 23, 59, 59, 0, t.Location()).Format(time.RFC3339)
+	}
+	switch strings.ToLower(s) {
+	case "today":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return endOfDay(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("invalid --due %q: use today, tomorrow, YYYY-MM-DD or RFC 3339", s)
+}
@@ -247,6 +247,7 @@ module "ecs" {
   env_vars = {
     APP_ENV               = var.env
     SERVER_PORT           = "8080"
+    METRICS_PORT          = "9100"
     DB_HOST               = module.rds.cluster_endpoint
     DB_PORT               = tostring(module.rds.cluster_port)
     DB_USER               = var.db_user
@@ -257,6 +258,9 @@ module "ecs" {
     COGNITO_REGION        = var.aws_region
     COGNITO_USER_POOL_ID  = var.cognito_user_pool_id
     COGNITO_APP_CLIENT_ID = var.cognito_app_client_id
+    TRUSTED_PROXIES       = join(",", var.public_subnet_cidrs)
+    SHUTDOWN_DRAIN_DELAY  = "15s"
+    CORS_ALLOWED_ORIGINS  = join(",", var.cors_allowed_origins)
   }
 
   secret_vars = {
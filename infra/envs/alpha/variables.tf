@@ -93,6 +93,12 @@ variable "log_retention_days" {
   type        = number
 }
 
+# CORS
+variable "cors_allowed_origins" {
+  description = "Browser origins allowed to call the API, e.g. https://app.example.com; empty allows none"
+  type        = list(string)
+}
+
 # Cognito (passed via TF_VAR_*)
 variable "cognito_user_pool_id" {
   description = "Cognito User Pool ID"
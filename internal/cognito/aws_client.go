@@ -10,6 +10,7 @@ import (
 	cip "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
 	"github.com/aws/smithy-go"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 )
 
 // AWSClient implements Client using the AWS SDK v2.
@@ -25,6 +26,7 @@ func NewAWSClient(ctx context.Context, region, clientID, clientSecret string) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
 	return &AWSClient{
 		cip:          cip.NewFromConfig(cfg),
 		clientID:     clientID,
@@ -41,14 +43,18 @@ func (c *AWSClient) secretHash(username string) *string {
 }
 
 func (c *AWSClient) SignUp(ctx context.Context, input SignUpInput) (SignUpOutput, error) {
+	attrs := []types.AttributeType{
+		{Name: aws.String("email"), Value: &input.Email},
+	}
+	if input.Locale != "" {
+		attrs = append(attrs, types.AttributeType{Name: aws.String("locale"), Value: &input.Locale})
+	}
 	out, err := c.cip.SignUp(ctx, &cip.SignUpInput{
-		ClientId:   &c.clientID,
-		SecretHash: c.secretHash(input.Email),
-		Username:   &input.Email,
-		Password:   &input.Password,
-		UserAttributes: []types.AttributeType{
-			{Name: aws.String("email"), Value: &input.Email},
-		},
+		ClientId:       &c.clientID,
+		SecretHash:     c.secretHash(input.Email),
+		Username:       &input.Email,
+		Password:       &input.Password,
+		UserAttributes: attrs,
 	})
 	if err != nil {
 		return SignUpOutput{}, mapAWSError(err)
@@ -19,6 +19,7 @@ type Client interface {
 type SignUpInput struct {
 	Email    string
 	Password string
+	Locale   string // optional; stored as the standard locale attribute
 }
 
 // SignUpOutput contains the result of a successful sign-up.
@@ -48,3 +48,12 @@ func LookupError(err error) (ErrorInfo, bool) {
 	}
 	return ErrorInfo{}, false
 }
+
+// ErrorCodes returns the error code of every Cognito sentinel error.
+func ErrorCodes() []string {
+	codes := make([]string, 0, len(errorMap))
+	for _, info := range errorMap {
+		codes = append(codes, info.Code)
+	}
+	return codes
+}
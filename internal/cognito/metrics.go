@@ -0,0 +1,88 @@
+package cognito
+
+import (
+	"context"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
+)
+
+// outcomeInternal labels failures that do not map to a known ErrorInfo.
+const outcomeInternal = "INTERNAL_ERROR"
+
+// instrumentedClient counts the outcome of every call to the wrapped Client.
+type instrumentedClient struct {
+	next Client
+}
+
+// WithMetrics wraps c so that each call is counted by operation and outcome:
+// "success", or the ErrorInfo code of the error it returned.
+func WithMetrics(c Client) Client {
+	return &instrumentedClient{next: c}
+}
+
+func observe(operation string, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = outcomeInternal
+		if info, ok := LookupError(err); ok {
+			outcome = info.Code
+		}
+	}
+	metrics.CognitoCalls.WithLabelValues(operation, outcome).Inc()
+}
+
+func (c *instrumentedClient) SignUp(ctx context.Context, input SignUpInput) (SignUpOutput, error) {
+	out, err := c.next.SignUp(ctx, input)
+	observe("SignUp", err)
+	return out, err
+}
+
+func (c *instrumentedClient) ConfirmSignUp(ctx context.Context, input ConfirmSignUpInput) error {
+	err := c.next.ConfirmSignUp(ctx, input)
+	observe("ConfirmSignUp", err)
+	return err
+}
+
+func (c *instrumentedClient) ResendConfirmationCode(ctx context.Context, input ResendCodeInput) error {
+	err := c.next.ResendConfirmationCode(ctx, input)
+	observe("ResendConfirmationCode", err)
+	return err
+}
+
+func (c *instrumentedClient) Login(ctx context.Context, input LoginInput) (AuthOutput, error) {
+	out, err := c.next.Login(ctx, input)
+	observe("Login", err)
+	return out, err
+}
+
+func (c *instrumentedClient) RefreshTokens(ctx context.Context, input RefreshInput) (AuthOutput, error) {
+	out, err := c.next.RefreshTokens(ctx, input)
+	observe("RefreshTokens", err)
+	return out, err
+}
+
+func (c *instrumentedClient) ForgotPassword(ctx context.Context, input ForgotPasswordInput) error {
+	err := c.next.ForgotPassword(ctx, input)
+	observe("ForgotPassword", err)
+	return err
+}
+
+func (c *instrumentedClient) ConfirmForgotPassword(ctx context.Context, input ConfirmForgotPasswordInput) error {
+	err := c.next.ConfirmForgotPassword(ctx, input)
+	observe("ConfirmForgotPassword", err)
+	return err
+}
+
+func (c *instrumentedClient) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	err := c.next.ChangePassword(ctx, input)
+	observe("ChangePassword", err)
+	return err
+}
+
+func (c *instrumentedClient) GlobalSignOut(ctx context.Context, input GlobalSignOutInput) error {
+	err := c.next.GlobalSignOut(ctx, input)
+	observe("GlobalSignOut", err)
+	return err
+}
+
+var _ Client = (*instrumentedClient)(nil)
@@ -0,0 +1,51 @@
+package cognito_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/jaekwang-park/todo-api/internal/cognito"
+	"github.com/jaekwang-park/todo-api/internal/metrics"
+)
+
+// loginClient returns err from Login; other methods are not exercised.
+type loginClient struct {
+	cognito.Client
+	err error
+}
+
+func (c *loginClient) Login(ctx context.Context, input cognito.LoginInput) (cognito.AuthOutput, error) {
+	return cognito.AuthOutput{}, c.err
+}
+
+func TestWithMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+	}{
+		{"success", nil, "success"},
+		{"known error", fmt.Errorf("Incorrect username or password.: %w", cognito.ErrNotAuthorized), "NOT_AUTHORIZED"},
+		{"throttled", cognito.ErrTooManyRequests, "TOO_MANY_REQUESTS"},
+		{"unknown error", errors.New("connection reset"), "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.CognitoCalls.WithLabelValues("Login", tt.wantOutcome)
+			before := testutil.ToFloat64(counter)
+
+			client := cognito.WithMetrics(&loginClient{err: tt.err})
+			if _, err := client.Login(context.Background(), cognito.LoginInput{}); !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v passed through, got %v", tt.err, err)
+			}
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("expected 1 call counted as %s, got %v", tt.wantOutcome, got)
+			}
+		})
+	}
+}
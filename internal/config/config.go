@@ -4,12 +4,30 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+var validPubSubBackends = map[string]bool{
+	"memory":   true,
+	"postgres": true,
+}
+
+var validRateLimitBackends = map[string]bool{
+	"memory":   true,
+	"postgres": true,
+}
+
+var validTracingExporters = map[string]bool{
+	"none":   true,
+	"stdout": true,
+	"otlp":   true,
+}
+
 var validEnvs = map[string]bool{
 	"local": true,
 	"alpha": true,
@@ -17,6 +35,28 @@ var validEnvs = map[string]bool{
 	"prod":  true,
 }
 
+// defaultCORSOrigins lets the SPA dev server call a local API. Deployed
+// environments must list their origins in CORS_ALLOWED_ORIGINS.
+var defaultCORSOrigins = map[string]string{
+	"local": "http://localhost:3000,http://localhost:5173",
+}
+
+// defaultHSTSMaxAge is short outside prod so a certificate mistake there
+// does not lock browsers out for long. Local servers speak plain HTTP.
+var defaultHSTSMaxAge = map[string]time.Duration{
+	"alpha": 24 * time.Hour,
+	"beta":  24 * time.Hour,
+	"prod":  365 * 24 * time.Hour,
+}
+
+// defaultShutdownDrainDelay gives the load balancer time to see /health
+// fail before connections close. Local servers are stopped by hand.
+var defaultShutdownDrainDelay = map[string]time.Duration{
+	"alpha": 15 * time.Second,
+	"beta":  15 * time.Second,
+	"prod":  15 * time.Second,
+}
+
 type Config struct {
 	ServerPort  string
 	AppEnv      string
@@ -24,6 +64,94 @@ type Config struct {
 	LogLevel    string
 	DB          DBConfig
 	Cognito     CognitoConfig
+
+	// TimerMaxDuration is how long a time tracking timer may run before it
+	// is stopped automatically.
+	TimerMaxDuration time.Duration
+
+	// PubSubBackend carries domain events between subsystems: "postgres"
+	// fans them out to every instance, "memory" stays within this process.
+	PubSubBackend string
+
+	// IdempotencyTTL is how long responses to requests made with an
+	// Idempotency-Key are kept for replay.
+	IdempotencyTTL time.Duration
+
+	// SyncTombstoneRetention is how long deleted todos are remembered for
+	// delta sync. Clients that have not synced for longer must start over.
+	SyncTombstoneRetention time.Duration
+
+	// RateLimitBackend holds the buckets of the per-route auth limits:
+	// "postgres" shares them across instances, "memory" limits each instance
+	// separately. The default limit on other routes is always per instance.
+	RateLimitBackend string
+
+	// TrustedProxies are the addresses or CIDR ranges of proxies, such as
+	// the load balancer, whose X-Forwarded-For header is trusted. Required
+	// outside local, where every request arrives through the load balancer
+	// and would otherwise share its address's rate limit.
+	TrustedProxies []string
+
+	// WebhookAllowPrivateTargets lets webhooks be delivered to loopback,
+	// private and link-local addresses, such as a receiver on the
+	// developer's machine. It is refused outside local.
+	WebhookAllowPrivateTargets bool
+
+	// TracingExporter is where OpenTelemetry spans go: "none", "stdout" or
+	// "otlp" to the collector at TracingOTLPEndpoint.
+	TracingExporter     string
+	TracingOTLPEndpoint string
+
+	// MetricsPort serves /metrics on a separate internal port. When empty,
+	// /metrics is served on ServerPort, which is only allowed in local since
+	// /metrics is not authenticated.
+	MetricsPort string
+
+	// GRPCPort serves the gRPC API on its own port. When empty, gRPC is
+	// not served.
+	GRPCPort string
+
+	// HealthCheckTimeout bounds each readiness check.
+	HealthCheckTimeout time.Duration
+
+	// HealthDetails adds each check's result to /health/ready responses.
+	// Errors name internal hosts, so enable it only where the endpoint is
+	// not reachable from outside.
+	HealthDetails bool
+
+	// ShutdownDrainDelay is how long /health and readiness report draining
+	// before the server stops accepting connections, so the load balancer
+	// can take the instance out of rotation first. Together with the 10s
+	// shutdown it must fit in the container's stop timeout.
+	ShutdownDrainDelay time.Duration
+
+	// Compression compresses larger responses for clients that accept gzip,
+	// zstd or brotli.
+	Compression bool
+
+	// CORSAllowedOrigins are the browser origins allowed to call the API:
+	// exact origins, https://*.example.com for any subdomain, or "*".
+	CORSAllowedOrigins []string
+	// CORSAllowCredentials lets browsers send cookies with cross-origin
+	// requests. It cannot be combined with "*".
+	CORSAllowCredentials bool
+	// CORSMaxAge is how long browsers may cache a preflight response; zero
+	// asks them not to cache it.
+	CORSMaxAge time.Duration
+
+	// HSTSMaxAge is the Strict-Transport-Security max-age; zero turns the
+	// header off.
+	HSTSMaxAge time.Duration
+
+	// OpenAPIValidation rejects requests that do not match the OpenAPI
+	// document before they reach the handlers.
+	OpenAPIValidation bool
+
+	// GraphQLMaxDepth and GraphQLMaxComplexity bound the GraphQL operations
+	// /api/v1/graphql runs: how deeply fields may nest, and roughly how many
+	// fields may be resolved.
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
 }
 
 func (c Config) ParseLogLevel() slog.Level {
@@ -49,6 +177,85 @@ func (c Config) Validate() error {
 	if c.AuthDevMode && c.AppEnv != "local" {
 		return fmt.Errorf("AUTH_DEV_MODE must not be enabled in %s environment", c.AppEnv)
 	}
+	if c.WebhookAllowPrivateTargets && c.AppEnv != "local" {
+		return fmt.Errorf("WEBHOOK_ALLOW_PRIVATE_TARGETS must not be enabled in %s environment", c.AppEnv)
+	}
+	if c.TimerMaxDuration <= 0 {
+		return fmt.Errorf("invalid TIMER_MAX_DURATION: must be a positive duration such as 8h")
+	}
+	if c.IdempotencyTTL <= 0 {
+		return fmt.Errorf("invalid IDEMPOTENCY_TTL: must be a positive duration such as 24h")
+	}
+	if c.SyncTombstoneRetention <= 0 {
+		return fmt.Errorf("invalid SYNC_TOMBSTONE_RETENTION: must be a positive duration such as 720h")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		return fmt.Errorf("invalid HEALTH_CHECK_TIMEOUT: must be a positive duration such as 2s")
+	}
+	if c.ShutdownDrainDelay < 0 {
+		return fmt.Errorf("invalid SHUTDOWN_DRAIN_DELAY: must be a non-negative duration such as 15s")
+	}
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("invalid CORS_MAX_AGE: must be a non-negative duration such as 10m")
+	}
+	for _, origin := range c.CORSAllowedOrigins {
+		if origin == "*" {
+			if c.CORSAllowCredentials {
+				return fmt.Errorf("CORS_ALLOWED_ORIGINS must list origins when CORS_ALLOW_CREDENTIALS is enabled, not \"*\"")
+			}
+			continue
+		}
+		if !validOrigin(origin) {
+			return fmt.Errorf("invalid CORS_ALLOWED_ORIGINS entry %q: must be an origin such as https://app.example.com", origin)
+		}
+	}
+	if c.GraphQLMaxDepth <= 0 {
+		return fmt.Errorf("invalid GRAPHQL_MAX_DEPTH: must be a positive number")
+	}
+	if c.GraphQLMaxComplexity <= 0 {
+		return fmt.Errorf("invalid GRAPHQL_MAX_COMPLEXITY: must be a positive number")
+	}
+	if c.HSTSMaxAge < 0 {
+		return fmt.Errorf("invalid HSTS_MAX_AGE: must be a non-negative duration such as 8760h")
+	}
+	if !validPubSubBackends[c.PubSubBackend] {
+		return fmt.Errorf("invalid PUBSUB_BACKEND %q: must be one of memory, postgres", c.PubSubBackend)
+	}
+	if !validRateLimitBackends[c.RateLimitBackend] {
+		return fmt.Errorf("invalid RATE_LIMIT_BACKEND %q: must be one of memory, postgres", c.RateLimitBackend)
+	}
+	if c.MetricsPort == "" && c.AppEnv != "local" {
+		return fmt.Errorf("METRICS_PORT is required in %s environment so /metrics is not served publicly on SERVER_PORT", c.AppEnv)
+	}
+	if c.MetricsPort != "" {
+		if _, err := strconv.Atoi(c.MetricsPort); err != nil {
+			return fmt.Errorf("invalid METRICS_PORT %q: %w", c.MetricsPort, err)
+		}
+		if c.MetricsPort == c.ServerPort {
+			return fmt.Errorf("METRICS_PORT must differ from SERVER_PORT; leave it empty to serve metrics on SERVER_PORT")
+		}
+	}
+	if c.GRPCPort != "" {
+		if _, err := strconv.Atoi(c.GRPCPort); err != nil {
+			return fmt.Errorf("invalid GRPC_PORT %q: %w", c.GRPCPort, err)
+		}
+		if c.GRPCPort == c.ServerPort || c.GRPCPort == c.MetricsPort {
+			return fmt.Errorf("GRPC_PORT must differ from SERVER_PORT and METRICS_PORT")
+		}
+	}
+	if !validTracingExporters[c.TracingExporter] {
+		return fmt.Errorf("invalid TRACING_EXPORTER %q: must be one of none, stdout, otlp", c.TracingExporter)
+	}
+	if len(c.TrustedProxies) == 0 && c.AppEnv != "local" {
+		return fmt.Errorf("TRUSTED_PROXIES is required in %s environment so clients behind the load balancer are rate limited by their own address", c.AppEnv)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				return fmt.Errorf("invalid TRUSTED_PROXIES entry %q: must be an IP address or CIDR range", proxy)
+			}
+		}
+	}
 	if !c.AuthDevMode {
 		if c.Cognito.UserPoolID == "" {
 			return fmt.Errorf("COGNITO_USER_POOL_ID is required when AUTH_DEV_MODE is disabled")
@@ -87,10 +294,19 @@ type CognitoConfig struct {
 	AppClientSecret string
 }
 
+// validOrigin reports whether s is a scheme and host with no path, as
+// browsers send in the Origin header. The host may start with "*.".
+func validOrigin(s string) bool {
+	u, err := url.Parse(strings.Replace(s, "://*.", "://", 1))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
 func Load() Config {
+	appEnv := envOrDefault("APP_ENV", "local")
 	return Config{
 		ServerPort:  envOrDefault("SERVER_PORT", "8080"),
-		AppEnv:      envOrDefault("APP_ENV", "local"),
+		AppEnv:      appEnv,
 		AuthDevMode: strings.EqualFold(envOrDefault("AUTH_DEV_MODE", "false"), "true"),
 		LogLevel:    envOrDefault("LOG_LEVEL", "info"),
 		DB: DBConfig{
@@ -107,6 +323,28 @@ func Load() Config {
 			AppClientID:     os.Getenv("COGNITO_APP_CLIENT_ID"),
 			AppClientSecret: os.Getenv("COGNITO_APP_CLIENT_SECRET"),
 		},
+		TimerMaxDuration:           durationOrDefault("TIMER_MAX_DURATION", 8*time.Hour),
+		PubSubBackend:              envOrDefault("PUBSUB_BACKEND", "postgres"),
+		IdempotencyTTL:             durationOrDefault("IDEMPOTENCY_TTL", 24*time.Hour),
+		SyncTombstoneRetention:     durationOrDefault("SYNC_TOMBSTONE_RETENTION", 30*24*time.Hour),
+		RateLimitBackend:           envOrDefault("RATE_LIMIT_BACKEND", "postgres"),
+		TrustedProxies:             listOrEmpty("TRUSTED_PROXIES"),
+		WebhookAllowPrivateTargets: strings.EqualFold(envOrDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false"), "true"),
+		TracingExporter:            envOrDefault("TRACING_EXPORTER", "none"),
+		TracingOTLPEndpoint:        envOrDefault("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+		MetricsPort:                os.Getenv("METRICS_PORT"),
+		GRPCPort:                   os.Getenv("GRPC_PORT"),
+		HealthCheckTimeout:         durationOrDefault("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		HealthDetails:              strings.EqualFold(envOrDefault("HEALTH_DETAILS", "false"), "true"),
+		ShutdownDrainDelay:         durationOrInvalid("SHUTDOWN_DRAIN_DELAY", defaultShutdownDrainDelay[appEnv]),
+		CORSAllowedOrigins:         splitList(envOrDefault("CORS_ALLOWED_ORIGINS", defaultCORSOrigins[appEnv])),
+		CORSAllowCredentials:       strings.EqualFold(envOrDefault("CORS_ALLOW_CREDENTIALS", "false"), "true"),
+		CORSMaxAge:                 durationOrInvalid("CORS_MAX_AGE", 10*time.Minute),
+		HSTSMaxAge:                 durationOrInvalid("HSTS_MAX_AGE", defaultHSTSMaxAge[appEnv]),
+		Compression:                !strings.EqualFold(envOrDefault("COMPRESSION", "true"), "false"),
+		OpenAPIValidation:          strings.EqualFold(envOrDefault("OPENAPI_VALIDATION", "false"), "true"),
+		GraphQLMaxDepth:            intOrDefault("GRAPHQL_MAX_DEPTH", 10),
+		GraphQLMaxComplexity:       intOrDefault("GRAPHQL_MAX_COMPLEXITY", 2000),
 	}
 }
 
@@ -116,3 +354,60 @@ func envOrDefault(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// durationOrDefault parses a duration such as "8h". Unparseable values yield
+// zero so that Validate can reject them.
+func durationOrDefault(key string, defaultVal time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// durationOrInvalid is durationOrDefault for settings where zero is a
+// valid value. Unparseable values yield -1 so that Validate can reject them.
+func durationOrInvalid(key string, defaultVal time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return -1
+	}
+	return d
+}
+
+// intOrDefault parses an integer. Unparseable values yield zero so that
+// Validate can reject them.
+func intOrDefault(key string, defaultVal int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultVal
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// listOrEmpty splits a comma-separated value, dropping empty entries.
+func listOrEmpty(key string) []string {
+	return splitList(os.Getenv(key))
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"log/slog"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jaekwang-park/todo-api/internal/config"
 )
@@ -14,11 +16,29 @@ func clearEnv(t *testing.T) {
 		"SERVER_PORT", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD",
 		"DB_NAME", "DB_SSLMODE", "APP_ENV", "AUTH_DEV_MODE", "LOG_LEVEL",
 		"COGNITO_REGION", "COGNITO_USER_POOL_ID", "COGNITO_APP_CLIENT_ID", "COGNITO_APP_CLIENT_SECRET",
+		"TIMER_MAX_DURATION", "PUBSUB_BACKEND", "IDEMPOTENCY_TTL", "SYNC_TOMBSTONE_RETENTION",
+		"RATE_LIMIT_BACKEND", "TRUSTED_PROXIES", "TRACING_EXPORTER", "TRACING_OTLP_ENDPOINT",
+		"METRICS_PORT", "GRPC_PORT", "HEALTH_CHECK_TIMEOUT", "HEALTH_DETAILS", "SHUTDOWN_DRAIN_DELAY",
+		"OPENAPI_VALIDATION", "COMPRESSION", "GRAPHQL_MAX_DEPTH", "GRAPHQL_MAX_COMPLEXITY",
+		"CORS_ALLOWED_ORIGINS", "CORS_ALLOW_CREDENTIALS", "CORS_MAX_AGE", "HSTS_MAX_AGE",
+		"WEBHOOK_ALLOW_PRIVATE_TARGETS",
 	} {
 		t.Setenv(key, "")
 	}
 }
 
+// setDeployedEnv sets APP_ENV to env along with the settings required
+// outside local.
+func setDeployedEnv(t *testing.T, env string) {
+	t.Helper()
+	t.Setenv("APP_ENV", env)
+	t.Setenv("AUTH_DEV_MODE", "false")
+	t.Setenv("COGNITO_USER_POOL_ID", "pool-1")
+	t.Setenv("COGNITO_APP_CLIENT_ID", "client-1")
+	t.Setenv("TRUSTED_PROXIES", "10.0.1.0/24")
+	t.Setenv("METRICS_PORT", "9100")
+}
+
 func TestLoad_Defaults(t *testing.T) {
 	clearEnv(t)
 
@@ -58,6 +78,75 @@ func TestLoad_Defaults(t *testing.T) {
 			t.Errorf("got LogLevel=%s, want info", cfg.LogLevel)
 		}
 	})
+
+	t.Run("TimerMaxDuration", func(t *testing.T) {
+		if cfg.TimerMaxDuration != 8*time.Hour {
+			t.Errorf("got TimerMaxDuration=%s, want 8h", cfg.TimerMaxDuration)
+		}
+	})
+
+	t.Run("IdempotencyTTL", func(t *testing.T) {
+		if cfg.IdempotencyTTL != 24*time.Hour {
+			t.Errorf("got IdempotencyTTL=%s, want 24h", cfg.IdempotencyTTL)
+		}
+	})
+
+	t.Run("SyncTombstoneRetention", func(t *testing.T) {
+		if cfg.SyncTombstoneRetention != 30*24*time.Hour {
+			t.Errorf("got SyncTombstoneRetention=%s, want 720h", cfg.SyncTombstoneRetention)
+		}
+	})
+
+	t.Run("PubSubBackend", func(t *testing.T) {
+		if cfg.PubSubBackend != "postgres" {
+			t.Errorf("got PubSubBackend=%s, want postgres", cfg.PubSubBackend)
+		}
+	})
+
+	t.Run("RateLimitBackend", func(t *testing.T) {
+		if cfg.RateLimitBackend != "postgres" {
+			t.Errorf("got RateLimitBackend=%s, want postgres", cfg.RateLimitBackend)
+		}
+	})
+
+	t.Run("TrustedProxies", func(t *testing.T) {
+		if len(cfg.TrustedProxies) != 0 {
+			t.Errorf("got TrustedProxies=%v, want none", cfg.TrustedProxies)
+		}
+	})
+
+	t.Run("Tracing", func(t *testing.T) {
+		if cfg.TracingExporter != "none" || cfg.TracingOTLPEndpoint != "localhost:4318" {
+			t.Errorf("got TracingExporter=%s TracingOTLPEndpoint=%s, want none localhost:4318",
+				cfg.TracingExporter, cfg.TracingOTLPEndpoint)
+		}
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		if cfg.HealthCheckTimeout != 2*time.Second || cfg.HealthDetails || cfg.ShutdownDrainDelay != 0 {
+			t.Errorf("got HealthCheckTimeout=%s HealthDetails=%v ShutdownDrainDelay=%s, want 2s false 0s",
+				cfg.HealthCheckTimeout, cfg.HealthDetails, cfg.ShutdownDrainDelay)
+		}
+	})
+
+	t.Run("OpenAPIValidation", func(t *testing.T) {
+		if cfg.OpenAPIValidation {
+			t.Errorf("got OpenAPIValidation=true, want false")
+		}
+	})
+
+	t.Run("Compression", func(t *testing.T) {
+		if !cfg.Compression {
+			t.Errorf("got Compression=false, want true")
+		}
+	})
+
+	t.Run("GraphQL", func(t *testing.T) {
+		if cfg.GraphQLMaxDepth != 10 || cfg.GraphQLMaxComplexity != 2000 {
+			t.Errorf("got GraphQLMaxDepth=%d GraphQLMaxComplexity=%d, want 10 2000",
+				cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity)
+		}
+	})
 }
 
 func TestLoad_FromEnv(t *testing.T) {
@@ -229,6 +318,8 @@ func TestConfig_Validate(t *testing.T) {
 			t.Setenv("SERVER_PORT", tt.port)
 			t.Setenv("APP_ENV", tt.env)
 			t.Setenv("AUTH_DEV_MODE", tt.devMode)
+			t.Setenv("TRUSTED_PROXIES", "10.0.1.0/24")
+			t.Setenv("METRICS_PORT", "9100")
 			if tt.poolID != "" {
 				t.Setenv("COGNITO_USER_POOL_ID", tt.poolID)
 			}
@@ -253,3 +344,512 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_TimerMaxDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"hours", "4h", 4 * time.Hour, false},
+		{"minutes", "90m", 90 * time.Minute, false},
+		{"invalid", "eight hours", 0, true},
+		{"negative", "-1h", -time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv("TIMER_MAX_DURATION", tt.value)
+
+			cfg := config.Load()
+			if cfg.TimerMaxDuration != tt.want {
+				t.Errorf("TIMER_MAX_DURATION=%q: got %v, want %v", tt.value, cfg.TimerMaxDuration, tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "TIMER_MAX_DURATION")) {
+				t.Errorf("expected TIMER_MAX_DURATION error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_PubSubBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"postgres", "postgres", false},
+		{"memory", "memory", false},
+		{"unknown", "redis", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv("PUBSUB_BACKEND", tt.value)
+
+			err := config.Load().Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "PUBSUB_BACKEND")) {
+				t.Errorf("expected PUBSUB_BACKEND error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_IdempotencyTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"hours", "48h", 48 * time.Hour, false},
+		{"invalid", "a day", 0, true},
+		{"zero", "0s", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv("IDEMPOTENCY_TTL", tt.value)
+
+			cfg := config.Load()
+			if cfg.IdempotencyTTL != tt.want {
+				t.Errorf("IDEMPOTENCY_TTL=%q: got %v, want %v", tt.value, cfg.IdempotencyTTL, tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "IDEMPOTENCY_TTL")) {
+				t.Errorf("expected IDEMPOTENCY_TTL error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_RateLimitBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"postgres", "postgres", false},
+		{"memory", "memory", false},
+		{"unknown", "redis", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv("RATE_LIMIT_BACKEND", tt.value)
+
+			err := config.Load().Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_BACKEND")) {
+				t.Errorf("expected RATE_LIMIT_BACKEND error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "local", "", nil, false},
+		{"empty outside local", "prod", "", nil, true},
+		{"addresses and ranges", "local", "10.0.0.0/16, 192.168.1.1,,2001:db8::/32", []string{"10.0.0.0/16", "192.168.1.1", "2001:db8::/32"}, false},
+		{"ranges outside local", "prod", "10.0.1.0/24,10.0.2.0/24", []string{"10.0.1.0/24", "10.0.2.0/24"}, false},
+		{"invalid", "local", "10.0.0.0/16,alb", []string{"10.0.0.0/16", "alb"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			setDeployedEnv(t, tt.env)
+			t.Setenv("TRUSTED_PROXIES", tt.value)
+
+			cfg := config.Load()
+			if !slices.Equal(cfg.TrustedProxies, tt.want) {
+				t.Errorf("TRUSTED_PROXIES=%q: got %v, want %v", tt.value, cfg.TrustedProxies, tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "TRUSTED_PROXIES")) {
+				t.Errorf("expected TRUSTED_PROXIES error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_TracingExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"none", "none", false},
+		{"stdout", "stdout", false},
+		{"otlp", "otlp", false},
+		{"unknown", "jaeger", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv("TRACING_EXPORTER", tt.value)
+
+			err := config.Load().Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "TRACING_EXPORTER")) {
+				t.Errorf("expected TRACING_EXPORTER error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_MetricsPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		value   string
+		wantErr string
+	}{
+		{"empty serves on server port", "local", "", ""},
+		{"empty outside local", "prod", "", "METRICS_PORT is required"},
+		{"separate port", "local", "9100", ""},
+		{"separate port outside local", "prod", "9100", ""},
+		{"invalid", "local", "metrics", "invalid METRICS_PORT"},
+		{"same as server port", "local", "8080", "METRICS_PORT must differ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			setDeployedEnv(t, tt.env)
+			t.Setenv("METRICS_PORT", tt.value)
+
+			err := config.Load().Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_GRPCPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"empty disables gRPC", map[string]string{"GRPC_PORT": ""}, ""},
+		{"separate port", map[string]string{"GRPC_PORT": "9090"}, ""},
+		{"invalid", map[string]string{"GRPC_PORT": "grpc"}, "invalid GRPC_PORT"},
+		{"same as server port", map[string]string{"GRPC_PORT": "8080"}, "GRPC_PORT must differ"},
+		{"same as metrics port", map[string]string{"GRPC_PORT": "9100", "METRICS_PORT": "9100"}, "GRPC_PORT must differ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			err := config.Load().Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Health(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantTimeout time.Duration
+		wantDetails bool
+		wantDrain   time.Duration
+		wantErr     string
+	}{
+		{
+			"configured",
+			map[string]string{"HEALTH_CHECK_TIMEOUT": "500ms", "HEALTH_DETAILS": "TRUE", "SHUTDOWN_DRAIN_DELAY": "15s"},
+			500 * time.Millisecond, true, 15 * time.Second, "",
+		},
+		{
+			"deployed default drain delay",
+			map[string]string{"APP_ENV": "prod", "AUTH_DEV_MODE": "false", "COGNITO_USER_POOL_ID": "pool-1",
+				"COGNITO_APP_CLIENT_ID": "client-1", "TRUSTED_PROXIES": "10.0.1.0/24", "METRICS_PORT": "9100"},
+			2 * time.Second, false, 15 * time.Second, "",
+		},
+		{
+			"invalid timeout",
+			map[string]string{"HEALTH_CHECK_TIMEOUT": "soon"},
+			0, false, 0, "HEALTH_CHECK_TIMEOUT",
+		},
+		{
+			"negative drain delay",
+			map[string]string{"SHUTDOWN_DRAIN_DELAY": "-5s"},
+			2 * time.Second, false, -5 * time.Second, "SHUTDOWN_DRAIN_DELAY",
+		},
+		{
+			"invalid drain delay",
+			map[string]string{"SHUTDOWN_DRAIN_DELAY": "soon"},
+			2 * time.Second, false, -1, "SHUTDOWN_DRAIN_DELAY",
+		},
+		{
+			"zero drain delay",
+			map[string]string{"SHUTDOWN_DRAIN_DELAY": "0s"},
+			2 * time.Second, false, 0, "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg := config.Load()
+			if cfg.HealthCheckTimeout != tt.wantTimeout || cfg.HealthDetails != tt.wantDetails || cfg.ShutdownDrainDelay != tt.wantDrain {
+				t.Errorf("got HealthCheckTimeout=%s HealthDetails=%v ShutdownDrainDelay=%s, want %s %v %s",
+					cfg.HealthCheckTimeout, cfg.HealthDetails, cfg.ShutdownDrainDelay,
+					tt.wantTimeout, tt.wantDetails, tt.wantDrain)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_OpenAPIValidation(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"true", true},
+		{"TRUE", true},
+		{"false", false},
+		{"yes", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("OPENAPI_VALIDATION", tt.value)
+
+			if got := config.Load().OpenAPIValidation; got != tt.want {
+				t.Errorf("OPENAPI_VALIDATION=%q: got %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_WebhookAllowPrivateTargets(t *testing.T) {
+	tests := []struct {
+		env     string
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"local", "", false, false},
+		{"local", "true", true, false},
+		{"alpha", "true", true, true},
+		{"prod", "false", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"/"+tt.value, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("APP_ENV", tt.env)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			if tt.env != "local" {
+				setDeployedEnv(t, tt.env)
+			}
+			t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", tt.value)
+
+			cfg := config.Load()
+			if cfg.WebhookAllowPrivateTargets != tt.want {
+				t.Errorf("WEBHOOK_ALLOW_PRIVATE_TARGETS=%q: got %v, want %v", tt.value, cfg.WebhookAllowPrivateTargets, tt.want)
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Compression(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", true},
+		{"true", true},
+		{"false", false},
+		{"FALSE", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("COMPRESSION", tt.value)
+
+			if got := config.Load().Compression; got != tt.want {
+				t.Errorf("COMPRESSION=%q: got %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		origins     string
+		credentials string
+		maxAge      string
+		want        []string
+		wantErr     string
+	}{
+		{"local default", "local", "", "", "", []string{"http://localhost:3000", "http://localhost:5173"}, ""},
+		{"prod default", "prod", "", "", "", nil, ""},
+		{"listed", "prod", "https://app.example.com, https://*.preview.example.com", "true", "1h",
+			[]string{"https://app.example.com", "https://*.preview.example.com"}, ""},
+		{"any origin", "prod", "*", "", "", []string{"*"}, ""},
+		{"any origin with credentials", "prod", "*", "true", "", []string{"*"}, "CORS_ALLOWED_ORIGINS"},
+		{"path", "prod", "https://app.example.com/", "", "", []string{"https://app.example.com/"}, "CORS_ALLOWED_ORIGINS"},
+		{"no scheme", "prod", "app.example.com", "", "", []string{"app.example.com"}, "CORS_ALLOWED_ORIGINS"},
+		{"invalid max age", "prod", "", "", "soon", nil, "CORS_MAX_AGE"},
+		{"zero max age", "prod", "", "", "0s", nil, ""},
+		{"negative max age", "prod", "", "", "-1m", nil, "CORS_MAX_AGE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			setDeployedEnv(t, tt.env)
+			t.Setenv("CORS_ALLOWED_ORIGINS", tt.origins)
+			t.Setenv("CORS_ALLOW_CREDENTIALS", tt.credentials)
+			t.Setenv("CORS_MAX_AGE", tt.maxAge)
+
+			cfg := config.Load()
+			if !slices.Equal(cfg.CORSAllowedOrigins, tt.want) {
+				t.Errorf("CORS_ALLOWED_ORIGINS=%q: got %v, want %v", tt.origins, cfg.CORSAllowedOrigins, tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected %s error, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_HSTSMaxAge(t *testing.T) {
+	tests := []struct {
+		env     string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"local", "", 0, false},
+		{"alpha", "", 24 * time.Hour, false},
+		{"beta", "", 24 * time.Hour, false},
+		{"prod", "", 365 * 24 * time.Hour, false},
+		{"prod", "0s", 0, false},
+		{"local", "1h", time.Hour, false},
+		{"prod", "1 year", -1, true},
+		{"local", "-1h", -time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"/"+tt.value, func(t *testing.T) {
+			clearEnv(t)
+			if tt.env == "local" {
+				t.Setenv("AUTH_DEV_MODE", "true")
+			} else {
+				setDeployedEnv(t, tt.env)
+			}
+			t.Setenv("HSTS_MAX_AGE", tt.value)
+
+			cfg := config.Load()
+			if cfg.HSTSMaxAge != tt.want {
+				t.Errorf("APP_ENV=%s HSTS_MAX_AGE=%q: got %s, want %s", tt.env, tt.value, cfg.HSTSMaxAge, tt.want)
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "HSTS_MAX_AGE")) {
+				t.Errorf("expected HSTS_MAX_AGE error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_GraphQLLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"depth", "GRAPHQL_MAX_DEPTH", "15", false},
+		{"complexity", "GRAPHQL_MAX_COMPLEXITY", "5000", false},
+		{"depth not a number", "GRAPHQL_MAX_DEPTH", "deep", true},
+		{"zero complexity", "GRAPHQL_MAX_COMPLEXITY", "0", true},
+		{"negative depth", "GRAPHQL_MAX_DEPTH", "-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("AUTH_DEV_MODE", "true")
+			t.Setenv(tt.key, tt.value)
+
+			err := config.Load().Validate()
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected %s error, got %v", tt.key, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// ErrReadOnly is returned by Execute for a mutation in a read-only request.
+var ErrReadOnly = errors.New("graphql: mutations are not allowed in read-only requests")
+
+// invalidArgs reports malformed arguments the way the services report
+// invalid input.
+func invalidArgs(rules ...*validate.FieldError) error {
+	if err := validate.Check(rules...); err != nil {
+		return fmt.Errorf("%w: %w", service.ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// mapErrors replaces the messages of resolver errors with the ones the REST
+// API uses and adds an extensions.code, mirroring handleServiceError.
+// Errors the services do not know are logged and reported as internal.
+// Parse and validation errors, which have no resolver error behind them,
+// are left as they are.
+func mapErrors(ctx context.Context, errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, fe := range errs {
+		err := resolverError(fe)
+		if err == nil {
+			continue
+		}
+
+		var fields validate.Errors
+		var limit *limitError
+		switch {
+		case errors.As(err, &limit):
+			fe.Message, fe.Extensions = limit.message, map[string]any{"code": limit.code}
+		case errors.As(err, &fields):
+			fields = fieldErrors(fields)
+			fe.Message, fe.Extensions = fields.Error(), map[string]any{"code": "INVALID_INPUT", "fields": fields}
+		case errors.Is(err, service.ErrNotFound):
+			fe.Message, fe.Extensions = "resource not found", map[string]any{"code": "NOT_FOUND"}
+		case errors.Is(err, service.ErrInvalidInput):
+			fe.Message, fe.Extensions = err.Error(), map[string]any{"code": "INVALID_INPUT"}
+		case errors.Is(err, service.ErrForbidden):
+			fe.Message, fe.Extensions = "access denied", map[string]any{"code": "FORBIDDEN"}
+		case errors.Is(err, service.ErrConflict):
+			fe.Message, fe.Extensions = err.Error(), map[string]any{"code": "CONFLICT"}
+		default:
+			slog.ErrorContext(ctx, "internal error", "error", err)
+			trace.SpanFromContext(ctx).RecordError(err)
+			fe.Message, fe.Extensions = "internal server error", map[string]any{"code": "INTERNAL_ERROR"}
+		}
+		errs[i] = fe
+	}
+	return errs
+}
+
+// resolverError digs the error a resolver returned out of the wrappers the
+// executor puts around it, or returns nil if there is none.
+func resolverError(err error) error {
+	for {
+		var next error
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			next = e.OriginalError()
+		case *gqlerrors.Error:
+			next = e.OriginalError
+		default:
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		err = next
+	}
+}
+
+// fieldErrors names fields as the schema does: due_at becomes dueAt.
+func fieldErrors(errs validate.Errors) validate.Errors {
+	out := make(validate.Errors, len(errs))
+	for i, fe := range errs {
+		parts := strings.Split(fe.Field, "_")
+		for j := 1; j < len(parts); j++ {
+			if parts[j] != "" {
+				parts[j] = strings.ToUpper(parts[j][:1]) + parts[j][1:]
+			}
+		}
+		out[i] = validate.FieldError{Field: strings.Join(parts, ""), Reason: fe.Reason}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+// Package graphql serves users and todos as a GraphQL schema. Resolvers
+// call the same services as the REST handlers, as the user middleware.Auth
+// put in the request context, and batch their reads per request so that a
+// list of todos with their owners and blockers costs a fixed number of
+// queries.
+package graphql
+
+import (
+	"context"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]any
+	// ReadOnly refuses mutations, for requests made with GET.
+	ReadOnly bool
+}
+
+// Executor runs GraphQL requests against the todo and user services.
+type Executor struct {
+	schema gql.Schema
+	todos  *service.TodoService
+	users  *service.UserService
+	limits Limits
+}
+
+// NewExecutor builds the schema. Without a user service, users resolve to
+// null.
+func NewExecutor(todos *service.TodoService, users *service.UserService, limits Limits) (*Executor, error) {
+	schema, err := newSchema(&resolver{todos: todos, users: users})
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, todos: todos, users: users, limits: limits}, nil
+}
+
+// Execute runs req as the user in ctx. Errors in the request, limit
+// violations and resolver errors are reported in the result. The error is
+// ErrReadOnly for a mutation in a read-only request.
+//
+// Limits are checked before the document is validated, as validation is
+// the costlier of the two.
+func (e *Executor) Execute(ctx context.Context, req Request) (*gql.Result, error) {
+	src := source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})
+	if err := checkSource(src); err != nil {
+		return &gql.Result{Errors: mapErrors(ctx, gqlerrors.FormatErrors(err))}, nil
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}, nil
+	}
+	if err := e.limits.checkDocument(doc); err != nil {
+		return &gql.Result{Errors: mapErrors(ctx, gqlerrors.FormatErrors(err))}, nil
+	}
+
+	// Without a matching operation, Execute reports the error.
+	if op := operation(doc, req.OperationName); op != nil {
+		if req.ReadOnly && op.Operation != ast.OperationTypeQuery {
+			return nil, ErrReadOnly
+		}
+		if err := e.limits.check(doc, op, req.Variables); err != nil {
+			return &gql.Result{Errors: mapErrors(ctx, gqlerrors.FormatErrors(err))}, nil
+		}
+	}
+	if v := gql.ValidateDocument(&e.schema, doc, nil); !v.IsValid {
+		return &gql.Result{Errors: v.Errors}, nil
+	}
+
+	ctx = withLoaders(ctx, newLoaders(e.todos, e.users, middleware.UserIDFromContext(ctx)))
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	result.Errors = mapErrors(ctx, result.Errors)
+	return result, nil
+}
+
+// operation picks the operation Execute runs: the named one, or the only
+// one if no name is given.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
@@ -0,0 +1,495 @@
+package graphql_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+const testUserID = "6a0b7c8d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+
+var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func todoID(i int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+}
+
+// memStore backs the todo, dependency and user repositories and counts the
+// queries made against it.
+type memStore struct {
+	todos []model.Todo
+	edges []model.TodoDependency
+	calls map[string]int
+}
+
+func newMemStore(n int) *memStore {
+	s := &memStore{calls: make(map[string]int)}
+	for i := 1; i <= n; i++ {
+		s.todos = append(s.todos, model.Todo{
+			ID: todoID(i), UserID: testUserID, Title: fmt.Sprintf("todo %d", i),
+			Status: model.TodoStatusPending, Priority: model.TodoPriorityNone, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+	return s
+}
+
+func (s *memStore) find(id string) (model.Todo, bool) {
+	for _, t := range s.todos {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return model.Todo{}, false
+}
+
+type memTodoRepo struct{ *memStore }
+
+func (r memTodoRepo) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	r.calls["Create"]++
+	todo.ID, todo.CreatedAt, todo.UpdatedAt = todoID(len(r.todos)+1), now, now
+	r.todos = append(r.todos, todo)
+	return todo, nil
+}
+func (r memTodoRepo) GetByID(ctx context.Context, userID, id string) (model.Todo, error) {
+	r.calls["GetByID"]++
+	if t, ok := r.find(id); ok {
+		return t, nil
+	}
+	return model.Todo{}, sql.ErrNoRows
+}
+func (r memTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	r.calls["Update"]++
+	return todo, nil
+}
+func (r memTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	return r.Update(ctx, todo)
+}
+func (r memTodoRepo) Delete(ctx context.Context, userID, id string) error {
+	r.calls["Delete"]++
+	return nil
+}
+func (r memTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, id string, updatedAt time.Time) error {
+	return r.Delete(ctx, userID, id)
+}
+func (r memTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+	r.calls["List"]++
+	start := 0
+	if params.Cursor != "" {
+		start = slices.IndexFunc(r.todos, func(t model.Todo) bool { return t.ID == params.Cursor }) + 1
+	}
+	end := min(start+params.Limit, len(r.todos))
+	result := model.TodoListResult{Todos: r.todos[start:end]}
+	if end < len(r.todos) {
+		result.NextCursor = r.todos[end-1].ID
+	}
+	return result, nil
+}
+func (r memTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	r.calls["ListByIDs"]++
+	var todos []model.Todo
+	for _, id := range ids {
+		if t, ok := r.find(id); ok {
+			todos = append(todos, t)
+		}
+	}
+	return todos, nil
+}
+
+type memDependencyRepo struct{ *memStore }
+
+func (r memDependencyRepo) Add(ctx context.Context, userID string, dep model.TodoDependency, check func([]model.TodoDependency) error) error {
+	if err := check(r.edges); err != nil {
+		return err
+	}
+	r.edges = append(r.edges, dep)
+	return nil
+}
+func (r memDependencyRepo) Remove(ctx context.Context, userID string, dep model.TodoDependency) error {
+	return nil
+}
+func (r memDependencyRepo) ListBlockers(ctx context.Context, userID, id string) ([]model.Todo, error) {
+	var blockers []model.Todo
+	for _, e := range r.edges {
+		if t, ok := r.find(e.BlockerID); ok && e.TodoID == id {
+			blockers = append(blockers, t)
+		}
+	}
+	return blockers, nil
+}
+func (r memDependencyRepo) ListBlocking(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	return nil, nil
+}
+func (r memDependencyRepo) ListEdges(ctx context.Context, userID string) ([]model.TodoDependency, error) {
+	r.calls["ListEdges"]++
+	return r.edges, nil
+}
+func (r memDependencyRepo) ListNodes(ctx context.Context, userID string) ([]model.Todo, error) {
+	return nil, nil
+}
+
+type memUserRepo struct{ *memStore }
+
+func (r memUserRepo) GetOrCreate(ctx context.Context, cognitoSub, email string) (model.User, error) {
+	return model.User{}, nil
+}
+func (r memUserRepo) GetByCognitoSub(ctx context.Context, cognitoSub string) (model.User, error) {
+	return model.User{}, nil
+}
+func (r memUserRepo) Update(ctx context.Context, user model.User) (model.User, error) {
+	return user, nil
+}
+func (r memUserRepo) ListByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	r.calls["Users.ListByIDs"]++
+	if slices.Contains(ids, testUserID) {
+		return []model.User{{ID: testUserID, Email: "kim@example.com", Nickname: "kim", CreatedAt: now}}, nil
+	}
+	return nil, nil
+}
+
+func newExecutor(t *testing.T, store *memStore, limits graphql.Limits) *graphql.Executor {
+	t.Helper()
+	todos := service.NewTodoService(memTodoRepo{store}, service.WithDependencies(memDependencyRepo{store}))
+	users := service.NewUserService(memUserRepo{store})
+	exec, err := graphql.NewExecutor(todos, users, limits)
+	if err != nil {
+		t.Fatalf("NewExecutor: %v", err)
+	}
+	return exec
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, exec *graphql.Executor, req graphql.Request) response {
+	t.Helper()
+	ctx := middleware.SetUserID(context.Background(), testUserID)
+	result, err := exec.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestExecute_Query(t *testing.T) {
+	store := newMemStore(3)
+	store.edges = []model.TodoDependency{{TodoID: todoID(2), BlockerID: todoID(1)}}
+	exec := newExecutor(t, store, graphql.DefaultLimits)
+
+	resp := execute(t, exec, graphql.Request{Query: `{
+		me { nickname todos(first: 2) {
+			edges { cursor node { title owner { nickname } blockedBy { title } } }
+			pageInfo { hasNextPage endCursor }
+		} }
+	}`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+
+	want := `{"me":{"nickname":"kim","todos":{
+		"edges":[
+			{"cursor":"` + todoID(1) + `","node":{"title":"todo 1","owner":{"nickname":"kim"},"blockedBy":[]}},
+			{"cursor":"` + todoID(2) + `","node":{"title":"todo 2","owner":{"nickname":"kim"},"blockedBy":[{"title":"todo 1"}]}}
+		],
+		"pageInfo":{"hasNextPage":true,"endCursor":"` + todoID(2) + `"}
+	}}}`
+	assertJSON(t, resp.Data, want)
+
+	// The blocker was on the page already, so no todo needs to be read.
+	if store.calls["ListByIDs"] != 0 {
+		t.Errorf("expected no lookups by ID, got %d", store.calls["ListByIDs"])
+	}
+}
+
+func TestExecute_NextPage(t *testing.T) {
+	store := newMemStore(3)
+	exec := newExecutor(t, store, graphql.DefaultLimits)
+
+	resp := execute(t, exec, graphql.Request{
+		Query:     `query($after: String) { todos(first: 2, after: $after) { nodes { title } pageInfo { hasNextPage } } }`,
+		Variables: map[string]any{"after": todoID(2)},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+	assertJSON(t, resp.Data, `{"todos":{"nodes":[{"title":"todo 3"}],"pageInfo":{"hasNextPage":false}}}`)
+}
+
+// TestExecute_Batching reads owners and both directions of dependencies for
+// a page of todos and expects one query per kind, however long the page.
+func TestExecute_Batching(t *testing.T) {
+	store := newMemStore(60)
+	for i := 2; i <= 50; i++ {
+		store.edges = append(store.edges, model.TodoDependency{TodoID: todoID(i), BlockerID: todoID(i + 10)})
+	}
+	exec := newExecutor(t, store, graphql.Limits{MaxDepth: 10, MaxComplexity: 10000})
+
+	resp := execute(t, exec, graphql.Request{Query: `{
+		todos(first: 50) { nodes { id owner { id } blockedBy { id blocking { id } } } }
+		a: todo(id: "` + todoID(55) + `") { id }
+		b: todo(id: "` + todoID(56) + `") { id }
+	}`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+
+	want := map[string]int{"List": 1, "ListEdges": 1, "ListByIDs": 2, "Users.ListByIDs": 1}
+	for name, n := range want {
+		if store.calls[name] != n {
+			t.Errorf("%s: got %d calls, want %d", name, store.calls[name], n)
+		}
+	}
+}
+
+func TestExecute_Limits(t *testing.T) {
+	limits := graphql.Limits{MaxDepth: 4, MaxComplexity: 100}
+
+	tests := []struct {
+		name     string
+		query    string
+		vars     map[string]any
+		wantCode string
+	}{
+		{"within limits", `{ todos(first: 10) { nodes { id title } } }`, nil, ""},
+		{"too deep", `{ todos { nodes { blockedBy { blockedBy { id } } } } }`, nil, "QUERY_TOO_DEEP"},
+		{"too deep through a fragment", `{ todos { nodes { ...f } } } fragment f on Todo { blockedBy { blocking { id } } }`, nil, "QUERY_TOO_DEEP"},
+		{"too complex", `{ todos(first: 50) { nodes { id title } } }`, nil, "QUERY_TOO_COMPLEX"},
+		{"too complex through a variable", `query($n: Int) { todos(first: $n) { nodes { id title } } }`, map[string]any{"n": 50}, "QUERY_TOO_COMPLEX"},
+		{"too complex through a default", `query($n: Int = 50) { todos(first: $n) { nodes { id title } } }`, nil, "QUERY_TOO_COMPLEX"},
+		{"introspection is not counted", `{ __schema { types { name fields { name type { name ofType { name } } } } } }`, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore(3)
+			exec := newExecutor(t, store, limits)
+
+			resp := execute(t, exec, graphql.Request{Query: tt.query, Variables: tt.vars})
+
+			if tt.wantCode == "" {
+				if len(resp.Errors) > 0 {
+					t.Fatalf("unexpected errors: %+v", resp.Errors)
+				}
+				return
+			}
+			if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("expected %s, got %+v", tt.wantCode, resp.Errors)
+			}
+			if store.calls["List"] != 0 {
+				t.Error("expected the query not to run")
+			}
+		})
+	}
+}
+
+// TestExecute_DocumentLimits sends documents that are cheap to write and
+// costly to validate, and expects them to be rejected before validation.
+func TestExecute_DocumentLimits(t *testing.T) {
+	// Fragments that each spread the next twice, for 2^40 fields.
+	var nested strings.Builder
+	nested.WriteString(`{ todos { nodes { ...f0 } } }`)
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&nested, " fragment f%d on Todo { ...f%d ...f%d }", i, i+1, i+1)
+	}
+	nested.WriteString(" fragment f40 on Todo { id }")
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{"repeated fields", `{ todos { nodes { ` + strings.Repeat("id ", 5000) + `} } }`, "QUERY_TOO_LARGE"},
+		{"repeated aliases", `{ todos { nodes { ` + strings.Repeat("a: title b: id ", 300) + `} } }`, "QUERY_TOO_LARGE"},
+		{"too long", `{ me { id } } # ` + strings.Repeat("x", 20000), "QUERY_TOO_LARGE"},
+		{"too many tokens", `{ todos(first: [` + strings.Repeat("1 ", 5000) + `]) { nodes { id } } }`, "QUERY_TOO_LARGE"},
+		{"too many definitions", strings.Repeat(`query { me { id } } `, 60), "QUERY_TOO_LARGE"},
+		{"too deep as written", `{ todos { nodes { ` + strings.Repeat("blockedBy { ", 20) + `id` + strings.Repeat(" }", 20) + ` } } }`, "QUERY_TOO_DEEP"},
+		{"fragments spread many times", nested.String(), "QUERY_TOO_COMPLEX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore(3)
+			exec := newExecutor(t, store, graphql.DefaultLimits)
+
+			start := time.Now()
+			resp := execute(t, exec, graphql.Request{Query: tt.query})
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %v", elapsed)
+			}
+
+			if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("expected %s, got %+v", tt.wantCode, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestExecute_Mutations(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantData   string
+		wantCode   string
+		wantFields []any
+	}{
+		{
+			name:     "create",
+			query:    `mutation { createTodo(input: {title: "Buy milk", priority: HIGH, dueAt: "2025-12-31T09:00:00Z"}) { title priority dueAt status } }`,
+			wantData: `{"createTodo":{"title":"Buy milk","priority":"HIGH","dueAt":"2025-12-31T09:00:00Z","status":"PENDING"}}`,
+		},
+		{
+			name:       "create with invalid fields",
+			query:      `mutation { createTodo(input: {title: "", estimateMinutes: -1}) { id } }`,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []any{map[string]any{"field": "title", "reason": "required"}, map[string]any{"field": "estimateMinutes", "reason": "negative"}},
+		},
+		{
+			name:     "update clears the due date",
+			query:    `mutation { updateTodo(id: "` + todoID(1) + `", input: {title: "Call mom", clearDueAt: true}) { title dueAt } }`,
+			wantData: `{"updateTodo":{"title":"Call mom","dueAt":null}}`,
+		},
+		{
+			name:     "complete a blocked todo",
+			query:    `mutation { setTodoStatus(id: "` + todoID(2) + `", status: COMPLETED) { id } }`,
+			wantCode: "CONFLICT",
+		},
+		{
+			name:     "force completes a blocked todo",
+			query:    `mutation { setTodoStatus(id: "` + todoID(2) + `", status: COMPLETED, force: true) { status } }`,
+			wantData: `{"setTodoStatus":{"status":"COMPLETED"}}`,
+		},
+		{
+			name:     "add a blocker",
+			query:    `mutation { addBlocker(id: "` + todoID(3) + `", blockerId: "` + todoID(1) + `") { blockedBy { id } } }`,
+			wantData: `{"addBlocker":{"blockedBy":[{"id":"` + todoID(1) + `"}]}}`,
+		},
+		{
+			name:     "a todo cannot block itself",
+			query:    `mutation { addBlocker(id: "` + todoID(1) + `", blockerId: "` + todoID(1) + `") { id } }`,
+			wantCode: "INVALID_INPUT",
+		},
+		{
+			name:     "delete",
+			query:    `mutation { deleteTodo(id: "` + todoID(1) + `") }`,
+			wantData: `{"deleteTodo":"` + todoID(1) + `"}`,
+		},
+		{
+			name:       "malformed id",
+			query:      `mutation { deleteTodo(id: "todo-1") }`,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []any{map[string]any{"field": "id", "reason": "invalid_uuid"}},
+		},
+		{
+			name:     "missing todo",
+			query:    `mutation { updateTodo(id: "` + todoID(9) + `", input: {title: "Call mom"}) { id } }`,
+			wantCode: "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore(3)
+			due := now.Add(24 * time.Hour)
+			store.todos[0].DueAt = &due
+			store.edges = []model.TodoDependency{{TodoID: todoID(2), BlockerID: todoID(1)}}
+			exec := newExecutor(t, store, graphql.DefaultLimits)
+
+			resp := execute(t, exec, graphql.Request{Query: tt.query})
+
+			if tt.wantCode != "" {
+				if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tt.wantCode {
+					t.Fatalf("expected %s, got %+v", tt.wantCode, resp.Errors)
+				}
+				if tt.wantFields != nil && !reflect.DeepEqual(resp.Errors[0].Extensions["fields"], tt.wantFields) {
+					t.Errorf("fields: got %v, want %v", resp.Errors[0].Extensions["fields"], tt.wantFields)
+				}
+				return
+			}
+			if len(resp.Errors) > 0 {
+				t.Fatalf("unexpected errors: %+v", resp.Errors)
+			}
+			assertJSON(t, resp.Data, tt.wantData)
+		})
+	}
+}
+
+// failingTodoRepo fails deletes, as a broken database would.
+type failingTodoRepo struct{ memTodoRepo }
+
+func (failingTodoRepo) Delete(ctx context.Context, userID, id string) error {
+	return errors.New("connection refused")
+}
+
+func TestExecute_InternalErrorsAreHidden(t *testing.T) {
+	todos := service.NewTodoService(failingTodoRepo{memTodoRepo{newMemStore(1)}})
+	exec, err := graphql.NewExecutor(todos, nil, graphql.DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := execute(t, exec, graphql.Request{
+		Query: `mutation { deleteTodo(id: "` + todoID(1) + `") }`,
+	})
+
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "INTERNAL_ERROR" || resp.Errors[0].Message != "internal server error" {
+		t.Errorf("expected an internal error, got %+v", resp.Errors)
+	}
+}
+
+func TestExecute_ReadOnly(t *testing.T) {
+	exec := newExecutor(t, newMemStore(1), graphql.DefaultLimits)
+	ctx := middleware.SetUserID(context.Background(), testUserID)
+
+	_, err := exec.Execute(ctx, graphql.Request{
+		Query:         `query q { todos { nodes { id } } } mutation m { deleteTodo(id: "` + todoID(1) + `") }`,
+		OperationName: "m",
+		ReadOnly:      true,
+	})
+	if !errors.Is(err, graphql.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+
+	result, err := exec.Execute(ctx, graphql.Request{Query: `{ todos { nodes { id } } }`, ReadOnly: true})
+	if err != nil || len(result.Errors) > 0 {
+		t.Errorf("expected queries to run, got %v, %v", err, result.Errors)
+	}
+}
+
+func assertJSON(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("decode expectation: %v", err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
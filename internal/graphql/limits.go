@@ -0,0 +1,273 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Limits bound how much work one operation may ask for. They are checked
+// before the operation runs.
+type Limits struct {
+	// MaxDepth is the deepest a field may be nested; root fields are at
+	// depth 1.
+	MaxDepth int
+	// MaxComplexity caps the estimated number of fields resolved. Each field
+	// counts once per object it may be resolved on.
+	MaxComplexity int
+}
+
+// DefaultLimits allow a page of 100 todos with their blockers and owner.
+var DefaultLimits = Limits{MaxDepth: 10, MaxComplexity: 2000}
+
+// assumedListSize is the cost multiplier for lists without a page size,
+// such as blockedBy.
+const assumedListSize = 10
+
+// Documents are also bounded as written, before they are parsed and
+// validated: validation compares fields pairwise, so its cost grows faster
+// than the document.
+const (
+	// maxQueryLength caps the size of a query in bytes.
+	maxQueryLength = 16 << 10
+	// maxTokens caps the tokens in a query.
+	maxTokens = 4000
+	// maxDefinitions caps the operations and fragments in a document.
+	maxDefinitions = 50
+	// maxSelections caps the fields, fragment spreads and inline fragments
+	// written in a document, before fragments are expanded.
+	maxSelections = 500
+)
+
+// maxCost saturates measured costs, which grow exponentially with nested
+// lists and fragments.
+const maxCost = 1 << 30
+
+// listFields are the fields whose selections are resolved once per item.
+var listFields = map[string]bool{"todos": true, "blockedBy": true, "blocking": true}
+
+// limitError is an operation rejected by Limits.
+type limitError struct {
+	code    string
+	message string
+}
+
+func (e *limitError) Error() string { return e.message }
+
+// tooLarge reports a query rejected before it is parsed or validated.
+func tooLarge(format string, args ...any) error {
+	return &limitError{code: "QUERY_TOO_LARGE", message: fmt.Sprintf(format, args...)}
+}
+
+// checkSource bounds the query text. Lexing errors are left to the parser.
+func checkSource(src *source.Source) error {
+	if len(src.Body) > maxQueryLength {
+		return tooLarge("query length %d exceeds the limit of %d bytes", len(src.Body), maxQueryLength)
+	}
+	lex := lexer.Lex(src)
+	for n := 0; ; n++ {
+		tok, err := lex(0)
+		if err != nil || tok.Kind == lexer.EOF {
+			return nil
+		}
+		if n == maxTokens {
+			return tooLarge("query exceeds the limit of %d tokens", maxTokens)
+		}
+	}
+}
+
+// checkDocument bounds doc as written: its definitions, its selections and
+// the depth of each definition without expanding fragments.
+func (l Limits) checkDocument(doc *ast.Document) error {
+	if len(doc.Definitions) > maxDefinitions {
+		return tooLarge("query has %d definitions, more than the limit of %d", len(doc.Definitions), maxDefinitions)
+	}
+	var selections int
+	for _, def := range doc.Definitions {
+		var set *ast.SelectionSet
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			set = def.SelectionSet
+		case *ast.FragmentDefinition:
+			set = def.SelectionSet
+		}
+		depth, n := writtenSize(set, 1)
+		if depth > l.MaxDepth {
+			return tooDeep(depth, l.MaxDepth)
+		}
+		if selections += n; selections > maxSelections {
+			return tooLarge("query has more than %d selections", maxSelections)
+		}
+	}
+	return nil
+}
+
+// writtenSize returns the deepest field under set, which is at depth, and
+// the number of selections in it. Fragment spreads count once and are not
+// followed; introspection fields count but are not nested, as in check.
+func writtenSize(set *ast.SelectionSet, depth int) (maxDepth, n int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		n++
+		var d, c int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				_, c = writtenSize(sel.SelectionSet, depth+1)
+				n += c
+				continue
+			}
+			d, c = writtenSize(sel.SelectionSet, depth+1)
+			d = max(d, depth)
+		case *ast.InlineFragment:
+			d, c = writtenSize(sel.SelectionSet, depth)
+		}
+		maxDepth = max(maxDepth, d)
+		n += c
+	}
+	return maxDepth, n
+}
+
+func tooDeep(depth, limit int) error {
+	return &limitError{code: "QUERY_TOO_DEEP",
+		message: fmt.Sprintf("query depth %d exceeds the limit of %d", depth, limit)}
+}
+
+// check measures op against the limits. Introspection fields are not
+// counted, as they read the fixed schema.
+func (l Limits) check(doc *ast.Document, op *ast.OperationDefinition, vars map[string]any) error {
+	m := measurer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		vars:      make(map[string]any),
+		visiting:  make(map[string]bool),
+		measured:  make(map[string]fragmentSize),
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			m.vars[def.Variable.Name.Value] = def.DefaultValue
+		}
+	}
+	for name, v := range vars {
+		m.vars[name] = v
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && frag.Name != nil {
+			m.fragments[frag.Name.Value] = frag
+		}
+	}
+
+	depth, cost := m.measure(op.SelectionSet, 1)
+	if depth > l.MaxDepth {
+		return tooDeep(depth, l.MaxDepth)
+	}
+	if cost > l.MaxComplexity {
+		return &limitError{code: "QUERY_TOO_COMPLEX",
+			message: fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, l.MaxComplexity)}
+	}
+	return nil
+}
+
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]any
+	// visiting guards against fragment cycles, which validation reports.
+	visiting map[string]bool
+	// measured holds each fragment's size, so that a fragment spread many
+	// times is measured once.
+	measured map[string]fragmentSize
+}
+
+// fragmentSize is a fragment's depth below the field it is spread in, and
+// its cost.
+type fragmentSize struct {
+	depth, cost int
+}
+
+// measure returns the deepest field under set, which is at depth, and the
+// cost of resolving set once.
+func (m *measurer) measure(set *ast.SelectionSet, depth int) (maxDepth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, c int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			d, c = depth, 1
+			if sel.SelectionSet != nil {
+				childDepth, childCost := m.measure(sel.SelectionSet, depth+1)
+				d = max(d, childDepth)
+				c = min(c+m.listSize(sel)*childCost, maxCost)
+			}
+		case *ast.InlineFragment:
+			d, c = m.measure(sel.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			size, ok := m.measured[sel.Name.Value]
+			if !ok {
+				frag := m.fragments[sel.Name.Value]
+				if frag == nil || m.visiting[sel.Name.Value] {
+					continue
+				}
+				m.visiting[sel.Name.Value] = true
+				fd, fc := m.measure(frag.SelectionSet, 1)
+				delete(m.visiting, sel.Name.Value)
+				size = fragmentSize{depth: fd, cost: fc}
+				m.measured[sel.Name.Value] = size
+			}
+			if size.depth > 0 {
+				d = depth - 1 + size.depth
+			}
+			c = size.cost
+		}
+		maxDepth = max(maxDepth, d)
+		cost = min(cost+c, maxCost)
+	}
+	return maxDepth, cost
+}
+
+// listSize is the number of items a field may resolve its selections on:
+// the page size for connections and assumedListSize for other lists.
+func (m *measurer) listSize(f *ast.Field) int {
+	if !listFields[f.Name.Value] {
+		return 1
+	}
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		if n, ok := m.intValue(arg.Value); ok {
+			return min(max(n, 1), maxPageSize)
+		}
+	}
+	if f.Name.Value == "todos" {
+		return defaultPageSize
+	}
+	return assumedListSize
+}
+
+func (m *measurer) intValue(v ast.Value) (int, bool) {
+	switch v := v.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := m.vars[v.Name.Value].(type) {
+		case ast.Value:
+			return m.intValue(n)
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,200 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+// loader batches lookups by key, DataLoader style. The executor runs every
+// resolver of a query level before calling any of their thunks, so keys
+// asked for on one level are all pending when the first thunk runs; that
+// thunk fetches them in one call. Results are kept for the rest of the
+// request.
+type loader[V any] struct {
+	// fetch returns the values found for keys. Keys it leaves out have no
+	// value.
+	fetch func(ctx context.Context, keys []string) (map[string]V, error)
+
+	mu      sync.Mutex
+	pending []string
+	queued  map[string]bool
+	results map[string]loadResult[V]
+}
+
+type loadResult[V any] struct {
+	value V
+	ok    bool
+	err   error
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, queued: make(map[string]bool), results: make(map[string]loadResult[V])}
+}
+
+// load queues key and returns a thunk for its value. ok is false if there is
+// no value for key.
+func (l *loader[V]) load(ctx context.Context, key string) func() (value V, ok bool, err error) {
+	l.mu.Lock()
+	if _, done := l.results[key]; !done && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.queued[key] {
+			l.flush(ctx)
+		}
+		r := l.results[key]
+		return r.value, r.ok, r.err
+	}
+}
+
+// prime stores a value read some other way, unless key was loaded already.
+func (l *loader[V]) prime(key string, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, done := l.results[key]; !done && !l.queued[key] {
+		l.results[key] = loadResult[V]{value: value, ok: true}
+	}
+}
+
+// flush fetches every pending key. The caller holds l.mu.
+func (l *loader[V]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	for _, k := range keys {
+		delete(l.queued, k)
+	}
+
+	values, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		v, ok := values[k]
+		l.results[k] = loadResult[V]{value: v, ok: ok, err: err}
+	}
+}
+
+// loaders are the per-request loaders behind the schema's resolvers.
+type loaders struct {
+	todos    *loader[model.Todo]
+	users    *loader[model.User]
+	edges    *loader[[]model.TodoDependency]
+	blockers *loader[[]model.Todo]
+	blocking *loader[[]model.Todo]
+}
+
+func newLoaders(todoSvc *service.TodoService, userSvc *service.UserService, userID string) *loaders {
+	l := &loaders{}
+
+	l.todos = newLoader(func(ctx context.Context, ids []string) (map[string]model.Todo, error) {
+		todos, err := todoSvc.GetByIDs(ctx, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]model.Todo, len(todos))
+		for _, t := range todos {
+			byID[t.ID] = t
+		}
+		return byID, nil
+	})
+
+	l.users = newLoader(func(ctx context.Context, ids []string) (map[string]model.User, error) {
+		if userSvc == nil {
+			return nil, nil
+		}
+		users, err := userSvc.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]model.User, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		return byID, nil
+	})
+
+	// The user's edges are read once and shared by both directions.
+	l.edges = newLoader(func(ctx context.Context, keys []string) (map[string][]model.TodoDependency, error) {
+		edges, err := todoSvc.DependencyEdges(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]model.TodoDependency{userID: edges}, nil
+	})
+
+	l.blockers = newLoader(func(ctx context.Context, ids []string) (map[string][]model.Todo, error) {
+		return l.neighbours(ctx, userID, ids, func(e model.TodoDependency) (string, string) {
+			return e.TodoID, e.BlockerID
+		})
+	})
+	l.blocking = newLoader(func(ctx context.Context, ids []string) (map[string][]model.Todo, error) {
+		return l.neighbours(ctx, userID, ids, func(e model.TodoDependency) (string, string) {
+			return e.BlockerID, e.TodoID
+		})
+	})
+
+	return l
+}
+
+// neighbours returns, for each of ids, the todos at the other end of its
+// edges in one direction. end maps an edge to the todo it starts from and
+// the todo it leads to. All neighbours are read through the todo loader in
+// one batch.
+func (l *loaders) neighbours(ctx context.Context, userID string, ids []string, end func(model.TodoDependency) (from, to string)) (map[string][]model.Todo, error) {
+	edges, _, err := l.edges.load(ctx, userID)()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	targets := make(map[string][]string)
+	var thunks []func() (model.Todo, bool, error)
+	for _, e := range edges {
+		from, to := end(e)
+		if wanted[from] {
+			targets[from] = append(targets[from], to)
+			thunks = append(thunks, l.todos.load(ctx, to))
+		}
+	}
+
+	found := make(map[string]model.Todo)
+	for _, thunk := range thunks {
+		todo, ok, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found[todo.ID] = todo
+		}
+	}
+
+	result := make(map[string][]model.Todo, len(ids))
+	for _, id := range ids {
+		todos := []model.Todo{}
+		for _, to := range targets[id] {
+			if todo, ok := found[to]; ok {
+				todos = append(todos, todo)
+			}
+		}
+		result[id] = todos
+	}
+	return result, nil
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,433 @@
+package graphql
+
+import (
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// Page sizes of todo connections, as for GET /api/v1/todos.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var todoStatusEnum = gql.NewEnum(gql.EnumConfig{
+	Name: "TodoStatus",
+	Values: gql.EnumValueConfigMap{
+		"PENDING":   {Value: model.TodoStatusPending},
+		"COMPLETED": {Value: model.TodoStatusCompleted},
+	},
+})
+
+var todoPriorityEnum = gql.NewEnum(gql.EnumConfig{
+	Name: "TodoPriority",
+	Values: gql.EnumValueConfigMap{
+		"NONE":   {Value: model.TodoPriorityNone},
+		"LOW":    {Value: model.TodoPriorityLow},
+		"MEDIUM": {Value: model.TodoPriorityMedium},
+		"HIGH":   {Value: model.TodoPriorityHigh},
+		"URGENT": {Value: model.TodoPriorityUrgent},
+	},
+})
+
+var todoSortEnum = gql.NewEnum(gql.EnumConfig{
+	Name: "TodoSort",
+	Values: gql.EnumValueConfigMap{
+		"CREATED_AT": {Value: model.TodoSortCreatedAt, Description: "Newest first."},
+		"PRIORITY":   {Value: model.TodoSortPriority, Description: "Most pressing priority first, then newest."},
+	},
+})
+
+// todoConnection is a page of todos in the Relay connection shape. Cursors
+// are todo IDs, the same cursors GET /api/v1/todos pages with.
+type todoConnection struct {
+	Edges    []todoEdge
+	Nodes    []model.Todo
+	PageInfo pageInfo
+}
+
+type todoEdge struct {
+	Cursor string
+	Node   model.Todo
+}
+
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   *string
+}
+
+// resolver holds the services the schema resolves against.
+type resolver struct {
+	todos *service.TodoService
+	users *service.UserService
+}
+
+func newSchema(r *resolver) (gql.Schema, error) {
+	userType := gql.NewObject(gql.ObjectConfig{
+		Name:        "User",
+		Description: "A user's public profile.",
+		Fields: gql.Fields{
+			"id":              {Type: gql.NewNonNull(gql.ID)},
+			"email":           {Type: gql.NewNonNull(gql.String)},
+			"nickname":        {Type: gql.NewNonNull(gql.String)},
+			"profileImageUrl": {Type: gql.NewNonNull(gql.String)},
+			"createdAt":       {Type: gql.NewNonNull(gql.DateTime)},
+		},
+	})
+
+	todoType := gql.NewObject(gql.ObjectConfig{
+		Name: "Todo",
+		Fields: gql.Fields{
+			"id":              {Type: gql.NewNonNull(gql.ID)},
+			"title":           {Type: gql.NewNonNull(gql.String)},
+			"description":     {Type: gql.NewNonNull(gql.String)},
+			"status":          {Type: gql.NewNonNull(todoStatusEnum)},
+			"priority":        {Type: gql.NewNonNull(todoPriorityEnum)},
+			"important":       {Type: gql.NewNonNull(gql.Boolean)},
+			"urgent":          {Type: gql.NewNonNull(gql.Boolean)},
+			"dueAt":           {Type: gql.DateTime},
+			"estimateMinutes": {Type: gql.Int},
+			"createdAt":       {Type: gql.NewNonNull(gql.DateTime)},
+			"updatedAt":       {Type: gql.NewNonNull(gql.DateTime)},
+			"owner": {
+				Type: userType,
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					todo := p.Source.(model.Todo)
+					return thunk(loadersFrom(p.Context).users.load(p.Context, todo.UserID), nil), nil
+				},
+			},
+		},
+	})
+	todoType.AddFieldConfig("blockedBy", &gql.Field{
+		Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(todoType))),
+		Description: "Todos that must be completed before this one.",
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			todo := p.Source.(model.Todo)
+			return thunk(loadersFrom(p.Context).blockers.load(p.Context, todo.ID), []model.Todo{}), nil
+		},
+	})
+	todoType.AddFieldConfig("blocking", &gql.Field{
+		Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(todoType))),
+		Description: "Todos waiting for this one.",
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			todo := p.Source.(model.Todo)
+			return thunk(loadersFrom(p.Context).blocking.load(p.Context, todo.ID), []model.Todo{}), nil
+		},
+	})
+
+	pageInfoType := gql.NewObject(gql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: gql.Fields{
+			"hasNextPage": {Type: gql.NewNonNull(gql.Boolean)},
+			"endCursor":   {Type: gql.String, Description: "Pass as after to get the next page."},
+		},
+	})
+	todoEdgeType := gql.NewObject(gql.ObjectConfig{
+		Name: "TodoEdge",
+		Fields: gql.Fields{
+			"cursor": {Type: gql.NewNonNull(gql.String)},
+			"node":   {Type: gql.NewNonNull(todoType)},
+		},
+	})
+	todoConnectionType := gql.NewObject(gql.ObjectConfig{
+		Name: "TodoConnection",
+		Fields: gql.Fields{
+			"edges":    {Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(todoEdgeType)))},
+			"nodes":    {Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(todoType)))},
+			"pageInfo": {Type: gql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	todosField := func() *gql.Field {
+		return &gql.Field{
+			Type:        gql.NewNonNull(todoConnectionType),
+			Description: "The signed-in user's todos, a page at a time.",
+			Args: gql.FieldConfigArgument{
+				"first":     {Type: gql.Int, DefaultValue: defaultPageSize, Description: "Page size, at most 100."},
+				"after":     {Type: gql.String, Description: "The endCursor of the previous page."},
+				"status":    {Type: todoStatusEnum},
+				"priority":  {Type: todoPriorityEnum},
+				"important": {Type: gql.Boolean},
+				"urgent":    {Type: gql.Boolean},
+				"blocked":   {Type: gql.Boolean, Description: "Whether the todo has an incomplete blocker."},
+				"sort":      {Type: todoSortEnum, DefaultValue: model.TodoSortCreatedAt},
+			},
+			Resolve: r.listTodos,
+		}
+	}
+	userType.AddFieldConfig("todos", todosField())
+
+	queryType := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"me": {
+				Type:        userType,
+				Description: "The signed-in user.",
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					return thunk(loadersFrom(p.Context).users.load(p.Context, middleware.UserIDFromContext(p.Context)), nil), nil
+				},
+			},
+			"todo": {
+				Type: todoType,
+				Args: gql.FieldConfigArgument{"id": {Type: gql.NewNonNull(gql.ID)}},
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					id := p.Args["id"].(string)
+					if err := invalidArgs(idRule("id", id)); err != nil {
+						return nil, err
+					}
+					return thunk(loadersFrom(p.Context).todos.load(p.Context, id), nil), nil
+				},
+			},
+			"todos": todosField(),
+		},
+	})
+
+	createInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "CreateTodoInput",
+		Fields: gql.InputObjectConfigFieldMap{
+			"title":           {Type: gql.NewNonNull(gql.String)},
+			"description":     {Type: gql.String},
+			"dueAt":           {Type: gql.DateTime},
+			"priority":        {Type: todoPriorityEnum},
+			"important":       {Type: gql.Boolean},
+			"urgent":          {Type: gql.Boolean},
+			"estimateMinutes": {Type: gql.Int},
+		},
+	})
+	updateInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name:        "UpdateTodoInput",
+		Description: "Fields left out are kept.",
+		Fields: gql.InputObjectConfigFieldMap{
+			"title":           {Type: gql.String},
+			"description":     {Type: gql.String},
+			"dueAt":           {Type: gql.DateTime},
+			"priority":        {Type: todoPriorityEnum},
+			"important":       {Type: gql.Boolean},
+			"urgent":          {Type: gql.Boolean},
+			"estimateMinutes": {Type: gql.Int},
+			"clearDueAt":      {Type: gql.Boolean, Description: "Remove the due date. Takes precedence over dueAt."},
+			"clearEstimate":   {Type: gql.Boolean, Description: "Remove the estimate. Takes precedence over estimateMinutes."},
+		},
+	})
+	idArg := &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}
+
+	mutationType := gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"createTodo": {
+				Type:    gql.NewNonNull(todoType),
+				Args:    gql.FieldConfigArgument{"input": {Type: gql.NewNonNull(createInput)}},
+				Resolve: r.createTodo,
+			},
+			"updateTodo": {
+				Type:    gql.NewNonNull(todoType),
+				Args:    gql.FieldConfigArgument{"id": idArg, "input": {Type: gql.NewNonNull(updateInput)}},
+				Resolve: r.updateTodo,
+			},
+			"setTodoStatus": {
+				Type: gql.NewNonNull(todoType),
+				Args: gql.FieldConfigArgument{
+					"id":     idArg,
+					"status": {Type: gql.NewNonNull(todoStatusEnum)},
+					"force":  {Type: gql.Boolean, DefaultValue: false, Description: "Complete the todo even if it has incomplete blockers."},
+				},
+				Resolve: r.setTodoStatus,
+			},
+			"deleteTodo": {
+				Type:        gql.NewNonNull(gql.ID),
+				Description: "Deletes a todo and returns its ID.",
+				Args:        gql.FieldConfigArgument{"id": idArg},
+				Resolve:     r.deleteTodo,
+			},
+			"addBlocker": {
+				Type:        gql.NewNonNull(todoType),
+				Description: "Records that the todo cannot be completed before the blocker.",
+				Args:        gql.FieldConfigArgument{"id": idArg, "blockerId": idArg},
+				Resolve:     r.addBlocker,
+			},
+			"removeBlocker": {
+				Type:    gql.NewNonNull(todoType),
+				Args:    gql.FieldConfigArgument{"id": idArg, "blockerId": idArg},
+				Resolve: r.removeBlocker,
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: queryType, Mutation: mutationType})
+}
+
+// thunk defers a loader lookup so that the executor can batch it with the
+// others on the same level. missing is returned when there is no value.
+func thunk[V any](load func() (V, bool, error), missing any) func() (any, error) {
+	return func() (any, error) {
+		v, ok, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return missing, nil
+		}
+		return v, nil
+	}
+}
+
+func (r *resolver) listTodos(p gql.ResolveParams) (any, error) {
+	params := model.TodoListParams{
+		UserID:    middleware.UserIDFromContext(p.Context),
+		Limit:     p.Args["first"].(int),
+		Sort:      p.Args["sort"].(model.TodoSort),
+		Status:    arg[model.TodoStatus](p.Args, "status"),
+		Priority:  arg[model.TodoPriority](p.Args, "priority"),
+		Important: arg[bool](p.Args, "important"),
+		Urgent:    arg[bool](p.Args, "urgent"),
+		Blocked:   arg[bool](p.Args, "blocked"),
+	}
+	if after := arg[string](p.Args, "after"); after != nil {
+		params.Cursor = *after
+	}
+	if err := invalidArgs(
+		validate.UUID("after", params.Cursor),
+		validate.When(params.Limit < 1 || params.Limit > maxPageSize, func() *validate.FieldError {
+			return validate.Fail("first", validate.ReasonInvalidValue)
+		}),
+	); err != nil {
+		return nil, err
+	}
+
+	result, err := r.todos.List(p.Context, params)
+	if err != nil {
+		return nil, err
+	}
+
+	l := loadersFrom(p.Context)
+	conn := todoConnection{Edges: make([]todoEdge, len(result.Todos)), Nodes: result.Todos}
+	for i, t := range result.Todos {
+		conn.Edges[i] = todoEdge{Cursor: t.ID, Node: t}
+		l.todos.prime(t.ID, t)
+	}
+	if n := len(result.Todos); n > 0 {
+		conn.PageInfo.EndCursor = &result.Todos[n-1].ID
+	}
+	conn.PageInfo.HasNextPage = result.NextCursor != ""
+	return conn, nil
+}
+
+func (r *resolver) createTodo(p gql.ResolveParams) (any, error) {
+	in := p.Args["input"].(map[string]any)
+	input := service.CreateTodoInput{
+		Title:           in["title"].(string),
+		Description:     deref(arg[string](in, "description")),
+		DueAt:           formatTime(arg[time.Time](in, "dueAt")),
+		Priority:        string(deref(arg[model.TodoPriority](in, "priority"))),
+		Important:       deref(arg[bool](in, "important")),
+		Urgent:          deref(arg[bool](in, "urgent")),
+		EstimateMinutes: arg[int](in, "estimateMinutes"),
+	}
+	return r.todos.Create(p.Context, middleware.UserIDFromContext(p.Context), input)
+}
+
+func (r *resolver) updateTodo(p gql.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	if err := invalidArgs(idRule("id", id)); err != nil {
+		return nil, err
+	}
+	in := p.Args["input"].(map[string]any)
+	input := service.UpdateTodoInput{
+		Title:           arg[string](in, "title"),
+		Description:     arg[string](in, "description"),
+		DueAt:           formatTime(arg[time.Time](in, "dueAt")),
+		Important:       arg[bool](in, "important"),
+		Urgent:          arg[bool](in, "urgent"),
+		EstimateMinutes: arg[int](in, "estimateMinutes"),
+		ClearDueAt:      deref(arg[bool](in, "clearDueAt")),
+		ClearEstimate:   deref(arg[bool](in, "clearEstimate")),
+	}
+	if priority := arg[model.TodoPriority](in, "priority"); priority != nil {
+		s := string(*priority)
+		input.Priority = &s
+	}
+	return r.todos.Update(p.Context, middleware.UserIDFromContext(p.Context), id, input)
+}
+
+func (r *resolver) setTodoStatus(p gql.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	if err := invalidArgs(idRule("id", id)); err != nil {
+		return nil, err
+	}
+	status := p.Args["status"].(model.TodoStatus)
+	force := p.Args["force"].(bool)
+	return r.todos.UpdateStatus(p.Context, middleware.UserIDFromContext(p.Context), id, status, force)
+}
+
+func (r *resolver) deleteTodo(p gql.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	if err := invalidArgs(idRule("id", id)); err != nil {
+		return nil, err
+	}
+	if err := r.todos.Delete(p.Context, middleware.UserIDFromContext(p.Context), id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+func (r *resolver) addBlocker(p gql.ResolveParams) (any, error) {
+	id, blockerID := p.Args["id"].(string), p.Args["blockerId"].(string)
+	if err := invalidArgs(idRule("id", id), idRule("blockerId", blockerID)); err != nil {
+		return nil, err
+	}
+	return r.todos.AddBlocker(p.Context, middleware.UserIDFromContext(p.Context), id, blockerID)
+}
+
+func (r *resolver) removeBlocker(p gql.ResolveParams) (any, error) {
+	id, blockerID := p.Args["id"].(string), p.Args["blockerId"].(string)
+	if err := invalidArgs(idRule("id", id), idRule("blockerId", blockerID)); err != nil {
+		return nil, err
+	}
+	userID := middleware.UserIDFromContext(p.Context)
+	if err := r.todos.RemoveBlocker(p.Context, userID, id, blockerID); err != nil {
+		return nil, err
+	}
+	return r.todos.GetByID(p.Context, userID, id)
+}
+
+// idRule requires a todo ID. IDs are checked before the service call to
+// keep malformed ones away from the database.
+func idRule(field, value string) *validate.FieldError {
+	if value == "" {
+		return validate.Fail(field, validate.ReasonRequired)
+	}
+	return validate.UUID(field, value)
+}
+
+// arg returns the named argument or input field, or nil if it was not given.
+func arg[T any](args map[string]any, name string) *T {
+	v, ok := args[name].(T)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// formatTime turns a DateTime argument into the RFC 3339 string the
+// services take.
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339Nano)
+	return &s
+}
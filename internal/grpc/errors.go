@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// statusError maps a service error to a gRPC status, as handleServiceError
+// maps it to an HTTP status. Field errors are attached as a BadRequest
+// detail.
+func statusError(ctx context.Context, err error) error {
+	var fields validate.Errors
+	switch {
+	case errors.As(err, &fields):
+		st := status.New(codes.InvalidArgument, fields.Error())
+		br := &errdetails.BadRequest{}
+		for _, fe := range fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: fe.Field, Description: fe.Reason})
+		}
+		if withDetails, err := st.WithDetails(br); err == nil {
+			st = withDetails
+		}
+		return st.Err()
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, "resource not found")
+	case errors.Is(err, service.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "access denied")
+	case errors.Is(err, service.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		slog.ErrorContext(ctx, "internal error", "error", err)
+		trace.SpanFromContext(ctx).RecordError(err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+// authenticate checks the call's credentials as middleware.Auth checks a
+// request's headers: "authorization" metadata carries the bearer token, or
+// "x-user-id" the user in dev mode.
+func authenticate(ctx context.Context, auth *middleware.Auth) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	var err error
+	if auth.DevMode() {
+		ctx, err = auth.AuthenticateDevMode(ctx, first("x-user-id"))
+	} else {
+		ctx, err = auth.AuthenticateToken(ctx, first("authorization"))
+	}
+	if err != nil {
+		var authErr *middleware.AuthError
+		if errors.As(err, &authErr) {
+			return nil, status.Error(codes.Unauthenticated, authErr.Message)
+		}
+		slog.ErrorContext(ctx, "user resolution failed", "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return ctx, nil
+}
+
+func authUnary(auth *middleware.Auth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(auth *middleware.Auth) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream is a ServerStream with the authenticated context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// recovered turns a panic into an Internal error, as middleware.Recovery
+// does for HTTP requests.
+func recovered(ctx context.Context, logger *slog.Logger, method string, err *error) {
+	if r := recover(); r != nil {
+		metrics.Panics.Inc()
+		logger.ErrorContext(ctx, "panic recovered",
+			"error", r,
+			"method", method,
+			"stack", string(debug.Stack()),
+		)
+		*err = status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func recoveryUnary(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recovered(ctx, logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func recoveryStream(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recovered(ss.Context(), logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,72 @@
+// Package grpc serves the todo API over gRPC for internal services, on its
+// own port beside the HTTP server. It calls the same services as the HTTP
+// handlers and authenticates calls with the same middleware.Auth.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+type Server struct {
+	grpcServer *grpc.Server
+	addr       string
+	logger     *slog.Logger
+}
+
+// NewServer registers the TodoService on a server for port. Every call is
+// traced, recovered from panics and authenticated, in that order.
+func NewServer(port string, logger *slog.Logger, todoSvc *service.TodoService, auth *middleware.Auth) *Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(recoveryUnary(logger), authUnary(auth)),
+		grpc.ChainStreamInterceptor(recoveryStream(logger), authStream(auth)),
+	)
+	todov1.RegisterTodoServiceServer(srv, &todoServer{svc: todoSvc})
+
+	return &Server{grpcServer: srv, addr: fmt.Sprintf(":%s", port), logger: logger}
+}
+
+// Start listens on the server's port and serves until Shutdown.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves calls accepted on lis until Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("starting gRPC server", "addr", lis.Addr().String())
+	return s.grpcServer.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for running ones to finish. When
+// ctx is done first, the remaining calls are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("shutting down gRPC server")
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
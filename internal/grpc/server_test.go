@@ -0,0 +1,475 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todogrpc "github.com/jaekwang-park/todo-api/internal/grpc"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+const (
+	testUserID  = "6a0b7c8d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+	blockedID   = "0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11"
+	forbiddenID = "1c6f0d5f-9a3b-4b58-8e4a-6b5a1e4d2f22"
+	missingID   = "2d7a1e6a-0b4c-4c69-9f5b-7c6b2f5e3a33"
+)
+
+// memTodoRepo keeps todos in memory, ordered by ID.
+type memTodoRepo struct {
+	mu    sync.Mutex
+	todos map[string]model.Todo
+	seq   int
+}
+
+func newMemTodoRepo() *memTodoRepo {
+	return &memTodoRepo{todos: make(map[string]model.Todo)}
+}
+
+func (m *memTodoRepo) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	todo.ID = fmt.Sprintf("00000000-0000-4000-8000-%012d", m.seq)
+	todo.Status = model.TodoStatusPending
+	todo.CreatedAt = time.Now()
+	todo.UpdatedAt = todo.CreatedAt
+	m.todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (m *memTodoRepo) GetByID(ctx context.Context, userID, todoID string) (model.Todo, error) {
+	if todoID == forbiddenID {
+		return model.Todo{}, service.ErrForbidden
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	todo, ok := m.todos[todoID]
+	if !ok || todo.UserID != userID {
+		return model.Todo{}, sql.ErrNoRows
+	}
+	return todo, nil
+}
+
+func (m *memTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	todo.UpdatedAt = time.Now()
+	m.todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (m *memTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.todos[todo.ID]; !ok || !stored.UpdatedAt.Equal(todo.UpdatedAt) {
+		return model.Todo{}, sql.ErrNoRows
+	}
+	todo.UpdatedAt = time.Now()
+	m.todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (m *memTodoRepo) Delete(ctx context.Context, userID, todoID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.todos[todoID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.todos, todoID)
+	return nil
+}
+
+func (m *memTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.todos[todoID]; !ok || !stored.UpdatedAt.Equal(updatedAt) {
+		return sql.ErrNoRows
+	}
+	delete(m.todos, todoID)
+	return nil
+}
+
+func (m *memTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id, todo := range m.todos {
+		if todo.UserID != params.UserID || id <= params.Cursor {
+			continue
+		}
+		if params.Status != nil && todo.Status != *params.Status {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := model.TodoListResult{Todos: []model.Todo{}}
+	for i, id := range ids {
+		if i == params.Limit {
+			result.NextCursor = result.Todos[i-1].ID
+			break
+		}
+		result.Todos = append(result.Todos, m.todos[id])
+	}
+	return result, nil
+}
+
+func (m *memTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	return nil, nil
+}
+
+// stubDependencyRepo blocks blockedID by one incomplete todo.
+type stubDependencyRepo struct{}
+
+func (stubDependencyRepo) Add(ctx context.Context, userID string, dep model.TodoDependency, check func([]model.TodoDependency) error) error {
+	return nil
+}
+func (stubDependencyRepo) Remove(ctx context.Context, userID string, dep model.TodoDependency) error {
+	return nil
+}
+func (stubDependencyRepo) ListBlockers(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	if todoID == blockedID {
+		return []model.Todo{{ID: missingID, Status: model.TodoStatusPending}}, nil
+	}
+	return nil, nil
+}
+func (stubDependencyRepo) ListBlocking(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	return nil, nil
+}
+func (stubDependencyRepo) ListEdges(ctx context.Context, userID string) ([]model.TodoDependency, error) {
+	return nil, nil
+}
+func (stubDependencyRepo) ListNodes(ctx context.Context, userID string) ([]model.Todo, error) {
+	return nil, nil
+}
+
+// dial serves a TodoService backed by repo over an in-memory connection.
+func dial(t *testing.T, repo *memTodoRepo, auth *middleware.Auth) todov1.TodoServiceClient {
+	t.Helper()
+	svc := service.NewTodoService(repo, service.WithDependencies(stubDependencyRepo{}))
+	srv := todogrpc.NewServer("0", slog.New(slog.NewTextHandler(io.Discard, nil)), svc, auth)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return todov1.NewTodoServiceClient(conn)
+}
+
+func devAuth(t *testing.T) *middleware.Auth {
+	t.Helper()
+	auth, err := middleware.NewAuth(middleware.AuthConfig{DevMode: true})
+	if err != nil {
+		t.Fatalf("NewAuth failed: %v", err)
+	}
+	return auth
+}
+
+func asUser(ctx context.Context, userID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-user-id", userID)
+}
+
+func TestTodoService_CRUD(t *testing.T) {
+	client := dial(t, newMemTodoRepo(), devAuth(t))
+	ctx := asUser(context.Background(), testUserID)
+
+	due := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{
+		Title:           "Write the report",
+		DueAt:           timestamppb.New(due),
+		Priority:        "high",
+		EstimateMinutes: proto.Int32(90),
+	})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	if created.GetUserId() != testUserID || created.GetPriority() != "high" || created.GetEstimateMinutes() != 90 ||
+		!created.GetDueAt().AsTime().Equal(due) {
+		t.Errorf("unexpected created todo: %v", created)
+	}
+
+	got, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("GetTodo: %v", err)
+	}
+	if got.GetTitle() != "Write the report" {
+		t.Errorf("expected title %q, got %q", "Write the report", got.GetTitle())
+	}
+
+	updated, err := client.UpdateTodo(ctx, &todov1.UpdateTodoByIdRequest{
+		Id:     created.GetId(),
+		Update: &todov1.UpdateTodoRequest{Title: proto.String("Send the report")},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTodo: %v", err)
+	}
+	if updated.GetTitle() != "Send the report" || updated.GetPriority() != "high" {
+		t.Errorf("expected only the title to change, got %v", updated)
+	}
+
+	completed, err := client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusByIdRequest{
+		Id:     created.GetId(),
+		Update: &todov1.UpdateTodoStatusRequest{Status: "completed"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTodoStatus: %v", err)
+	}
+	if completed.GetStatus() != "completed" {
+		t.Errorf("expected status completed, got %q", completed.GetStatus())
+	}
+
+	if _, err := client.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	if _, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: created.GetId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound after delete, got %v", err)
+	}
+}
+
+func TestTodoService_ListTodosStreams(t *testing.T) {
+	repo := newMemTodoRepo()
+	client := dial(t, repo, devAuth(t))
+	ctx := asUser(context.Background(), testUserID)
+
+	// More than one page, to stream across the service's cursor.
+	const total = 150
+	for i := range total {
+		if _, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: fmt.Sprintf("todo %d", i)}); err != nil {
+			t.Fatalf("CreateTodo: %v", err)
+		}
+	}
+	if _, err := repo.Create(context.Background(), model.Todo{UserID: "someone-else", Title: "not mine"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	stream, err := client.ListTodos(ctx, &todov1.ListTodosRequest{Status: proto.String("pending")})
+	if err != nil {
+		t.Fatalf("ListTodos: %v", err)
+	}
+	seen := make(map[string]bool)
+	for {
+		todo, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if todo.GetUserId() != testUserID {
+			t.Errorf("streamed another user's todo: %v", todo)
+		}
+		seen[todo.GetId()] = true
+	}
+	if len(seen) != total {
+		t.Errorf("expected %d distinct todos, got %d", total, len(seen))
+	}
+}
+
+func TestTodoService_ErrorCodes(t *testing.T) {
+	repo := newMemTodoRepo()
+	if _, err := repo.Update(context.Background(), model.Todo{ID: blockedID, UserID: testUserID, Title: "blocked"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	client := dial(t, repo, devAuth(t))
+	ctx := asUser(context.Background(), testUserID)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{"not found", func() error {
+			_, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: missingID})
+			return err
+		}, codes.NotFound},
+		{"forbidden", func() error {
+			_, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: forbiddenID})
+			return err
+		}, codes.PermissionDenied},
+		{"invalid id", func() error {
+			_, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: "not-a-uuid"})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid fields", func() error {
+			_, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Priority: "someday"})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid status", func() error {
+			_, err := client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusByIdRequest{Id: blockedID, Update: &todov1.UpdateTodoStatusRequest{Status: "archived"}})
+			return err
+		}, codes.InvalidArgument},
+		{"blocked", func() error {
+			_, err := client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusByIdRequest{Id: blockedID, Update: &todov1.UpdateTodoStatusRequest{Status: "completed"}})
+			return err
+		}, codes.FailedPrecondition},
+		{"invalid list filter", func() error {
+			stream, err := client.ListTodos(ctx, &todov1.ListTodosRequest{Sort: "title"})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.wantCode {
+				t.Errorf("expected %s, got %s", tt.wantCode, got)
+			}
+		})
+	}
+}
+
+func TestTodoService_FieldViolations(t *testing.T) {
+	client := dial(t, newMemTodoRepo(), devAuth(t))
+
+	_, err := client.CreateTodo(asUser(context.Background(), testUserID), &todov1.CreateTodoRequest{Priority: "someday"})
+
+	fields := make(map[string]string)
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields[v.GetField()] = v.GetDescription()
+			}
+		}
+	}
+	if fields["title"] != "required" || fields["priority"] != "invalid_value" {
+		t.Errorf("expected title and priority violations, got %v", fields)
+	}
+}
+
+func TestTodoService_DevModeAuth(t *testing.T) {
+	client := dial(t, newMemTodoRepo(), devAuth(t))
+
+	_, err := client.GetTodo(context.Background(), &todov1.GetTodoRequest{Id: missingID})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without x-user-id, got %v", err)
+	}
+
+	stream, err := client.ListTodos(context.Background(), &todov1.ListTodosRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated on stream without x-user-id, got %v", err)
+	}
+}
+
+type stubUserResolver struct {
+	user middleware.ResolvedUser
+	err  error
+}
+
+func (r stubUserResolver) ResolveUser(ctx context.Context, sub string) (middleware.ResolvedUser, error) {
+	return r.user, r.err
+}
+
+func TestTodoService_JWTAuth(t *testing.T) {
+	const (
+		kid    = "key-1"
+		issuer = "https://cognito-idp.ap-northeast-1.amazonaws.com/pool-1"
+		client = "client-1"
+	)
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]any{{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(privKey.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privKey.E)).Bytes()),
+	}}})
+	jwksSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jwks)
+	}))
+	t.Cleanup(jwksSrv.Close)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "cognito-sub", "iss": issuer, "aud": client, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(privKey)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		resolver stubUserResolver
+		wantCode codes.Code
+	}{
+		{"valid token", "Bearer " + signed, stubUserResolver{user: middleware.ResolvedUser{ID: testUserID}}, codes.NotFound},
+		{"missing token", "", stubUserResolver{}, codes.Unauthenticated},
+		{"invalid token", "Bearer garbage", stubUserResolver{}, codes.Unauthenticated},
+		{"unknown user", "Bearer " + signed, stubUserResolver{err: middleware.ErrUserNotFound}, codes.Unauthenticated},
+		{"resolver failure", "Bearer " + signed, stubUserResolver{err: errors.New("db down")}, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := middleware.NewAuth(middleware.AuthConfig{
+				JWKSClient:   middleware.NewJWKSClient(jwksSrv.URL),
+				Issuer:       issuer,
+				AppClientID:  client,
+				UserResolver: tt.resolver,
+			})
+			if err != nil {
+				t.Fatalf("NewAuth failed: %v", err)
+			}
+			c := dial(t, newMemTodoRepo(), auth)
+
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.header)
+			}
+			// A valid token reaches the service, which finds no such todo.
+			_, err = c.GetTodo(ctx, &todov1.GetTodoRequest{Id: missingID})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("expected %s, got %s (%v)", tt.wantCode, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pb"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// listPageSize is how many todos ListTodos reads per query while streaming.
+const listPageSize = 100
+
+// todoServer implements todov1.TodoServiceServer on the TodoService.
+type todoServer struct {
+	todov1.UnimplementedTodoServiceServer
+	svc *service.TodoService
+}
+
+func (s *todoServer) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.Todo, error) {
+	var estimate *int
+	if req.EstimateMinutes != nil {
+		estimate = intPtr(req.GetEstimateMinutes())
+	}
+	input := service.CreateTodoInput{
+		Title:           req.GetTitle(),
+		Description:     req.GetDescription(),
+		DueAt:           formatTimestamp(req.GetDueAt()),
+		Priority:        req.GetPriority(),
+		Important:       req.GetImportant(),
+		Urgent:          req.GetUrgent(),
+		EstimateMinutes: estimate,
+	}
+
+	todo, err := s.svc.Create(ctx, middleware.UserIDFromContext(ctx), input)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return pb.FromTodo(todo), nil
+}
+
+func (s *todoServer) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.Todo, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	todo, err := s.svc.GetByID(ctx, middleware.UserIDFromContext(ctx), req.GetId())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return pb.FromTodo(todo), nil
+}
+
+func (s *todoServer) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoByIdRequest) (*todov1.Todo, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	update := req.GetUpdate()
+	input := service.UpdateTodoInput{
+		Title:       update.Title,
+		Description: update.Description,
+		DueAt:       formatTimestamp(update.GetDueAt()),
+		Priority:    update.Priority,
+		Important:   update.Important,
+		Urgent:      update.Urgent,
+	}
+	if update.EstimateMinutes != nil {
+		input.EstimateMinutes = intPtr(update.GetEstimateMinutes())
+	}
+
+	todo, err := s.svc.Update(ctx, middleware.UserIDFromContext(ctx), req.GetId(), input)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return pb.FromTodo(todo), nil
+}
+
+func (s *todoServer) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*emptypb.Empty, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	if err := s.svc.Delete(ctx, middleware.UserIDFromContext(ctx), req.GetId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *todoServer) UpdateTodoStatus(ctx context.Context, req *todov1.UpdateTodoStatusByIdRequest) (*todov1.Todo, error) {
+	if err := checkID(req.GetId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	update := req.GetUpdate()
+	todo, err := s.svc.UpdateStatus(ctx, middleware.UserIDFromContext(ctx), req.GetId(), model.TodoStatus(update.GetStatus()), update.GetForce())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return pb.FromTodo(todo), nil
+}
+
+// ListTodos pages through the matching todos and sends them one by one,
+// stopping early when the client goes away.
+func (s *todoServer) ListTodos(req *todov1.ListTodosRequest, stream todov1.TodoService_ListTodosServer) error {
+	ctx := stream.Context()
+
+	params := model.TodoListParams{
+		UserID:    middleware.UserIDFromContext(ctx),
+		Important: req.Important,
+		Urgent:    req.Urgent,
+		Blocked:   req.Blocked,
+		Sort:      model.TodoSort(req.GetSort()),
+		Limit:     listPageSize,
+	}
+	if req.Status != nil {
+		status := model.TodoStatus(req.GetStatus())
+		params.Status = &status
+	}
+	if req.Priority != nil {
+		priority := model.TodoPriority(req.GetPriority())
+		params.Priority = &priority
+	}
+	err := validate.Check(
+		validate.When(params.Status != nil && !params.Status.IsValid(), invalid("status")),
+		validate.When(params.Priority != nil && !params.Priority.IsValid(), invalid("priority")),
+		validate.When(params.Sort != "" && !params.Sort.IsValid(), invalid("sort")),
+	)
+	if err != nil {
+		return statusError(ctx, err)
+	}
+
+	for {
+		result, err := s.svc.List(ctx, params)
+		if err != nil {
+			return statusError(ctx, err)
+		}
+		for _, todo := range result.Todos {
+			if err := stream.Send(pb.FromTodo(todo)); err != nil {
+				return err
+			}
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		params.Cursor = result.NextCursor
+	}
+}
+
+func checkID(id string) error {
+	return validate.Check(validate.Required("id", id), validate.UUID("id", id))
+}
+
+func invalid(field string) func() *validate.FieldError {
+	return func() *validate.FieldError {
+		return validate.Fail(field, validate.ReasonInvalidValue)
+	}
+}
+
+// formatTimestamp formats ts as the RFC 3339 string the service inputs
+// take. Nil means not set.
+func formatTimestamp(ts *timestamppb.Timestamp) *string {
+	if ts == nil {
+		return nil
+	}
+	s := ts.AsTime().Format(time.RFC3339Nano)
+	return &s
+}
+
+func intPtr(v int32) *int {
+	n := int(v)
+	return &n
+}
@@ -0,0 +1,129 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/health"
+)
+
+func TestChecker_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     map[string]health.Check
+		wantStatus string
+		wantFailed []string
+	}{
+		{
+			"no checks",
+			nil,
+			health.StatusOK,
+			nil,
+		},
+		{
+			"all pass",
+			map[string]health.Check{
+				"database": func(ctx context.Context) error { return nil },
+				"jwks":     func(ctx context.Context) error { return nil },
+			},
+			health.StatusOK,
+			nil,
+		},
+		{
+			"one fails",
+			map[string]health.Check{
+				"database": func(ctx context.Context) error { return errors.New("connection refused") },
+				"jwks":     func(ctx context.Context) error { return nil },
+			},
+			health.StatusUnavailable,
+			[]string{"database"},
+		},
+		{
+			"timeout",
+			map[string]health.Check{
+				"database": func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			health.StatusUnavailable,
+			[]string{"database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := health.NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				c.Add(name, check)
+			}
+
+			report := c.Run(context.Background())
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, report.Status)
+			}
+			if report.OK() != (tt.wantStatus == health.StatusOK) {
+				t.Errorf("expected OK() = %v", tt.wantStatus == health.StatusOK)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("expected %d check results, got %d", len(tt.checks), len(report.Checks))
+			}
+			for _, name := range tt.wantFailed {
+				if r := report.Checks[name]; r.Status != health.StatusUnavailable || r.Error == "" {
+					t.Errorf("expected %s to fail with an error, got %+v", name, r)
+				}
+			}
+		})
+	}
+}
+
+func TestChecker_Draining(t *testing.T) {
+	called := false
+	c := health.NewChecker(time.Second)
+	c.Add("database", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	c.SetDraining()
+	report := c.Run(context.Background())
+
+	if report.Status != health.StatusDraining || report.OK() {
+		t.Errorf("expected draining report, got %+v", report)
+	}
+	if called {
+		t.Error("expected checks not to run while draining")
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		dirty   bool
+		err     error
+		wantErr bool
+	}{
+		{"current", 9, false, nil, false},
+		{"newer", 10, false, nil, false},
+		{"behind", 8, false, nil, true},
+		{"dirty", 9, true, nil, true},
+		{"query fails", 0, false, errors.New("relation does not exist"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := health.SchemaVersion(func(ctx context.Context) (uint, bool, error) {
+				return tt.version, tt.dirty, tt.err
+			}, 9)
+
+			err := check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error = %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
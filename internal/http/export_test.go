@@ -0,0 +1,6 @@
+package http
+
+// Patterns returns the ServeMux patterns registered by NewRouter.
+func Patterns(h any) []string {
+	return h.(*router).patterns
+}
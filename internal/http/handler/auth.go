@@ -1,18 +1,18 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/jaekwang-park/todo-api/internal/cognito"
 	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
 )
 
-const maxAuthBodySize = 1 << 20 // 1 MB
-
 // AuthHandler handles authentication-related HTTP requests.
 type AuthHandler struct {
 	svc *service.AuthService
@@ -48,16 +48,15 @@ func (h *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "logout":
 		h.requirePost(w, r, h.handleLogout)
 	default:
-		WriteError(w, http.StatusNotFound, "NOT_FOUND", "endpoint not found")
+		WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "endpoint not found")
 	}
 }
 
 func (h *AuthHandler) requirePost(w http.ResponseWriter, r *http.Request, handler func(http.ResponseWriter, *http.Request)) {
 	if r.Method != http.MethodPost {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
 		return
 	}
-	r.Body = http.MaxBytesReader(w, r.Body, maxAuthBodySize)
 	handler(w, r)
 }
 
@@ -66,6 +65,7 @@ func (h *AuthHandler) requirePost(w http.ResponseWriter, r *http.Request, handle
 type signUpRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Language string `json:"language,omitempty"`
 }
 
 type confirmSignUpRequest struct {
@@ -111,27 +111,26 @@ type logoutRequest struct {
 
 func (h *AuthHandler) handleSignUp(w http.ResponseWriter, r *http.Request) {
 	var req signUpRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	out, err := h.svc.SignUp(r.Context(), service.SignUpInput{
 		Email:    req.Email,
 		Password: req.Password,
+		Language: req.Language,
 	})
 	if err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusCreated, out)
+	WriteJSON(w, r, http.StatusCreated, out)
 }
 
 func (h *AuthHandler) handleConfirmSignUp(w http.ResponseWriter, r *http.Request) {
 	var req confirmSignUpRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -139,34 +138,32 @@ func (h *AuthHandler) handleConfirmSignUp(w http.ResponseWriter, r *http.Request
 		Email: req.Email,
 		Code:  req.Code,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "email confirmed"})
+	WriteMessage(w, r, "EMAIL_CONFIRMED")
 }
 
 func (h *AuthHandler) handleResendCode(w http.ResponseWriter, r *http.Request) {
 	var req resendCodeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	if err := h.svc.ResendCode(r.Context(), service.ResendCodeInput{
 		Email: req.Email,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "confirmation code resent"})
+	WriteMessage(w, r, "CONFIRMATION_CODE_RESENT")
 }
 
 func (h *AuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -175,17 +172,16 @@ func (h *AuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 		Password: req.Password,
 	})
 	if err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, out)
+	WriteJSON(w, r, http.StatusOK, out)
 }
 
 func (h *AuthHandler) handleRefresh(w http.ResponseWriter, r *http.Request) {
 	var req refreshRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -194,34 +190,32 @@ func (h *AuthHandler) handleRefresh(w http.ResponseWriter, r *http.Request) {
 		RefreshToken: req.RefreshToken,
 	})
 	if err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, out)
+	WriteJSON(w, r, http.StatusOK, out)
 }
 
 func (h *AuthHandler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req forgotPasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	if err := h.svc.ForgotPassword(r.Context(), service.ForgotPasswordInput{
 		Email: req.Email,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "password reset code sent"})
+	WriteMessage(w, r, "PASSWORD_RESET_CODE_SENT")
 }
 
 func (h *AuthHandler) handleConfirmForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req confirmForgotPasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -230,17 +224,16 @@ func (h *AuthHandler) handleConfirmForgotPassword(w http.ResponseWriter, r *http
 		Code:        req.Code,
 		NewPassword: req.NewPassword,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "password reset confirmed"})
+	WriteMessage(w, r, "PASSWORD_RESET_CONFIRMED")
 }
 
 func (h *AuthHandler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
 	var req changePasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -249,68 +242,52 @@ func (h *AuthHandler) handleChangePassword(w http.ResponseWriter, r *http.Reques
 		PreviousPassword: req.PreviousPassword,
 		NewPassword:      req.NewPassword,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "password changed"})
+	WriteMessage(w, r, "PASSWORD_CHANGED")
 }
 
 func (h *AuthHandler) handleLogout(w http.ResponseWriter, r *http.Request) {
 	var req logoutRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	if err := h.svc.Logout(r.Context(), service.LogoutInput{
 		AccessToken: req.AccessToken,
 	}); err != nil {
-		handleAuthError(w, err)
+		handleAuthError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"message": "signed out"})
+	WriteMessage(w, r, "SIGNED_OUT")
 }
 
 // handleAuthError maps cognito sentinel errors and service errors to HTTP responses.
-// Uses fixed messages to avoid leaking internal error details to clients.
+// Uses catalog messages to avoid leaking internal error details to clients.
 // Logs actual error details server-side for debugging.
-func handleAuthError(w http.ResponseWriter, err error) {
+func handleAuthError(w http.ResponseWriter, r *http.Request, err error) {
 	// Check cognito sentinel errors first
 	if info, ok := cognito.LookupError(err); ok {
-		slog.Error("auth error", "code", info.Code, "detail", err.Error())
-		WriteError(w, info.Status, info.Code, cognitoErrorMessage(info.Code))
+		slog.ErrorContext(r.Context(), "auth error", "code", info.Code, "detail", err.Error())
+		WriteError(w, r, info.Status, info.Code, "")
 		return
 	}
 
 	// Check service-level errors
+	var fields validate.Errors
+	if errors.As(err, &fields) {
+		WriteFieldErrors(w, r, fields)
+		return
+	}
 	if errors.Is(err, service.ErrInvalidInput) {
-		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		WriteError(w, r, http.StatusBadRequest, "INVALID_INPUT", err.Error())
 		return
 	}
 
-	slog.Error("auth internal error", "error", err.Error())
-	WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
-}
-
-// cognitoErrorMessage returns a safe, user-facing message for each cognito error code.
-func cognitoErrorMessage(code string) string {
-	messages := map[string]string{
-		"USER_ALREADY_EXISTS":   "a user with this email already exists",
-		"USER_NOT_FOUND":        "user not found",
-		"USER_NOT_CONFIRMED":    "email address not confirmed",
-		"INVALID_PASSWORD":      "password does not meet requirements",
-		"INVALID_CODE":          "invalid verification code",
-		"CODE_EXPIRED":          "verification code has expired",
-		"TOO_MANY_REQUESTS":     "too many requests, please try again later",
-		"NOT_AUTHORIZED":        "incorrect email or password",
-		"LIMIT_EXCEEDED":        "attempt limit exceeded, please try again later",
-		"PASSWORD_RESET_REQUIRED": "password reset is required",
-		"INVALID_PARAMETER":     "invalid request parameter",
-	}
-	if msg, ok := messages[code]; ok {
-		return msg
-	}
-	return "an error occurred"
+	slog.ErrorContext(r.Context(), "auth internal error", "error", err.Error())
+	trace.SpanFromContext(r.Context()).RecordError(err)
+	WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
 }
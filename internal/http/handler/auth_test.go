@@ -63,6 +63,7 @@ type mockAuthUserRepo struct {
 	getOrCreateFn     func(ctx context.Context, cognitoSub, email string) (model.User, error)
 	getByCognitoSubFn func(ctx context.Context, cognitoSub string) (model.User, error)
 	updateFn          func(ctx context.Context, user model.User) (model.User, error)
+	listByIDsFn       func(ctx context.Context, ids []string) ([]model.User, error)
 }
 
 func (m *mockAuthUserRepo) GetOrCreate(ctx context.Context, cognitoSub, email string) (model.User, error) {
@@ -74,6 +75,9 @@ func (m *mockAuthUserRepo) GetByCognitoSub(ctx context.Context, cognitoSub strin
 func (m *mockAuthUserRepo) Update(ctx context.Context, user model.User) (model.User, error) {
 	return m.updateFn(ctx, user)
 }
+func (m *mockAuthUserRepo) ListByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	return m.listByIDsFn(ctx, ids)
+}
 
 func fakeIDTokenForHandler(sub, email string) string {
 	header := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9"
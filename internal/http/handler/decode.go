@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+const maxBodySize = 1 << 20 // 1 MB
+
+// decodeJSON reads a single JSON object from the request body into dst.
+// Bodies over maxBodySize, unknown fields, mistyped fields and trailing
+// data are rejected. MessagePack bodies, and Protocol Buffers bodies for dst
+// with a schema, are converted to JSON first so that they are checked the
+// same way. On failure the error response has been written and false is
+// returned.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxBodySize)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == msgpackContentType || mediaType == protobufContentType {
+		converted, ok := binaryBodyToJSON(w, r, mediaType, dst)
+		if !ok {
+			return false
+		}
+		body = bytes.NewReader(converted)
+	}
+
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("trailing data after JSON body")
+	}
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		WriteError(w, r, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", "request body too large")
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		WriteFieldErrors(w, r, validate.Errors{{Field: typeErr.Field, Reason: validate.ReasonInvalidType}})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields.
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		WriteFieldErrors(w, r, validate.Errors{{Field: field, Reason: validate.ReasonUnknownField}})
+	default:
+		WriteError(w, r, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	}
+	return false
+}
+
+func binaryBodyToJSON(w http.ResponseWriter, r *http.Request, mediaType string, dst any) ([]byte, bool) {
+	var msg proto.Message
+	if mediaType == protobufContentType {
+		req, ok := dst.(protoRequest)
+		if !ok {
+			WriteError(w, r, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "unsupported media type")
+			return nil, false
+		}
+		msg = req.protoMessage()
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return nil, false
+	}
+	var converted []byte
+	var err error
+	if msg != nil {
+		converted, err = protoToJSON(body, msg)
+	} else {
+		converted, err = msgpackToJSON(body)
+	}
+	if err != nil {
+		WriteError(w, r, http.StatusBadRequest, "INVALID_BODY", "failed to read request body")
+		return nil, false
+	}
+	return converted, true
+}
+
+// readBody reads a request body that is not decoded by decodeJSON, such as
+// a patch document. Bodies over maxBodySize are rejected. On failure the
+// error response has been written and false is returned.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err == nil {
+		return body, true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		WriteError(w, r, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", "request body too large")
+	} else {
+		WriteError(w, r, http.StatusBadRequest, "INVALID_BODY", "failed to read request body")
+	}
+	return nil, false
+}
+
+// validIDs checks identifiers taken from the path or body, writing a field
+// error response and returning false if any is malformed. Checking before
+// the service call keeps malformed IDs away from the database.
+func validIDs(w http.ResponseWriter, r *http.Request, rules ...*validate.FieldError) bool {
+	var errs validate.Errors
+	if !errors.As(validate.Check(rules...), &errs) {
+		return true
+	}
+	WriteFieldErrors(w, r, errs)
+	return false
+}
@@ -0,0 +1,138 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+func TestTodoHandler_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantFields []validate.FieldError
+	}{
+		{
+			name:       "all field errors at once",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos",
+			body:       `{"title":"","due_at":"2025-01-01","priority":"soon"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{
+				{Field: "title", Reason: "required"},
+				{Field: "due_at", Reason: "invalid_date_time"},
+				{Field: "priority", Reason: "invalid_value"},
+			},
+		},
+		{
+			name:       "unknown field",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos",
+			body:       `{"title":"Buy groceries","colour":"red"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{{Field: "colour", Reason: "unknown_field"}},
+		},
+		{
+			name:       "wrong type",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos",
+			body:       `{"title":"Buy groceries","estimate_minutes":"ten"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{{Field: "estimate_minutes", Reason: "invalid_type"}},
+		},
+		{
+			name:       "trailing data",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos",
+			body:       `{"title":"a"}{"title":"b"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_JSON",
+		},
+		{
+			name:       "body too large",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos",
+			body:       `{"title":"` + strings.Repeat("a", 1<<20) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "REQUEST_TOO_LARGE",
+		},
+		{
+			name:       "malformed path id",
+			method:     http.MethodGet,
+			path:       "/api/v1/todos/not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{{Field: "id", Reason: "invalid_uuid"}},
+		},
+		{
+			name:       "malformed blocker id",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos/" + testTodoID + "/blockers",
+			body:       `{"blocker_id":"todo-2"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{{Field: "blocker_id", Reason: "invalid_uuid"}},
+		},
+		{
+			name:       "malformed cursor",
+			method:     http.MethodGet,
+			path:       "/api/v1/todos?cursor=abc",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_INPUT",
+			wantFields: []validate.FieldError{{Field: "cursor", Reason: "invalid_uuid"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The repository must not be reached for any of these requests.
+			h := newTodoHandler(&mockTodoRepo{
+				getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+					t.Fatal("repository called")
+					return model.Todo{}, nil
+				},
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			var resp handler.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("code: got %q, want %q", resp.Error.Code, tt.wantCode)
+			}
+			if len(resp.Error.Fields) != len(tt.wantFields) {
+				t.Fatalf("fields: got %v, want %v", resp.Error.Fields, tt.wantFields)
+			}
+			for i := range tt.wantFields {
+				if resp.Error.Fields[i] != tt.wantFields[i] {
+					t.Errorf("fields[%d]: got %+v, want %+v", i, resp.Error.Fields[i], tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types the API reads and writes. JSON is the default; the binary
+// encodings are for clients that ask for compact payloads.
+const (
+	jsonContentType     = "application/json"
+	msgpackContentType  = "application/msgpack"
+	protobufContentType = "application/x-protobuf"
+)
+
+// negotiateContentType picks the response media type from an Accept header.
+// Protocol Buffers is only offered if the response has a schema. JSON wins
+// ties and is used when the client accepts nothing on offer.
+func negotiateContentType(accept string, protoOK bool) string {
+	if accept == "" {
+		return jsonContentType
+	}
+	offers := []string{jsonContentType, msgpackContentType}
+	if protoOK {
+		offers = append(offers, protobufContentType)
+	}
+
+	best, bestQ := jsonContentType, 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// acceptQuality is the quality accept gives mediaType, taken from the most
+// specific media range that matches it.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch mediaRange {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			} else {
+				q = 0
+			}
+		}
+	}
+	return q
+}
+
+// jsonToMsgpack encodes data as MessagePack by way of its JSON encoding, so
+// both have the same fields, names and omissions. Timestamps stay RFC 3339
+// strings and whole numbers become integers.
+func jsonToMsgpack(data any) ([]byte, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(fromJSONNumbers(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fromJSONNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = fromJSONNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = fromJSONNumbers(e)
+		}
+	}
+	return v
+}
+
+const (
+	// maxMsgpackDepth bounds how deeply a MessagePack body may nest.
+	maxMsgpackDepth = 32
+	// maxMsgpackValues bounds how many values a MessagePack body may hold.
+	maxMsgpackValues = 1 << 16
+)
+
+// msgpackToJSON converts a MessagePack request body to JSON. The body is
+// untrusted and is not checked against the OpenAPI schema, so arrays and
+// maps are walked one element at a time, within maxMsgpackDepth and
+// maxMsgpackValues, rather than handed to the recursive decoder.
+func msgpackToJSON(body []byte) ([]byte, error) {
+	r := bytes.NewReader(body)
+	c := msgpackConverter{dec: msgpack.NewDecoder(r), budget: maxMsgpackValues}
+	var buf bytes.Buffer
+	if err := c.convert(&buf, 0); err != nil {
+		return nil, err
+	}
+	if r.Len() > 0 {
+		return nil, errors.New("trailing data after MessagePack body")
+	}
+	return buf.Bytes(), nil
+}
+
+type msgpackConverter struct {
+	dec    *msgpack.Decoder
+	budget int // values left
+}
+
+// convert writes the next value as JSON.
+func (c *msgpackConverter) convert(buf *bytes.Buffer, depth int) error {
+	if depth > maxMsgpackDepth {
+		return errors.New("MessagePack body is nested too deeply")
+	}
+	if c.budget--; c.budget < 0 {
+		return errors.New("MessagePack body has too many values")
+	}
+
+	code, err := c.dec.PeekCode()
+	if err != nil {
+		return err
+	}
+	switch {
+	case msgpcode.IsFixedMap(code) || code == msgpcode.Map16 || code == msgpcode.Map32:
+		n, err := c.dec.DecodeMapLen()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := c.dec.DecodeString()
+			if err != nil {
+				return fmt.Errorf("MessagePack map keys must be strings: %w", err)
+			}
+			if err := writeJSONValue(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := c.convert(buf, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case msgpcode.IsFixedArray(code) || code == msgpcode.Array16 || code == msgpcode.Array32:
+		n, err := c.dec.DecodeArrayLen()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('[')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.convert(buf, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		v, err := c.dec.DecodeInterface()
+		if err != nil {
+			return err
+		}
+		return writeJSONValue(buf, v)
+	}
+}
+
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// protoToJSON converts a Protocol Buffers request body, decoded as msg, to
+// JSON with the field names of the JSON API.
+func protoToJSON(body []byte, msg proto.Message) ([]byte, error) {
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+}
@@ -0,0 +1,326 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+)
+
+func TestWriteJSON_Negotiation(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		data     any
+		wantType string
+	}{
+		{"no accept", "", sampleTodo(), "application/json"},
+		{"any", "*/*", sampleTodo(), "application/json"},
+		{"msgpack", "application/msgpack", sampleTodo(), "application/msgpack"},
+		{"protobuf", "application/x-protobuf", sampleTodo(), "application/x-protobuf"},
+		{"quality", "application/json;q=0.5, application/x-protobuf", sampleTodo(), "application/x-protobuf"},
+		{"refused", "application/msgpack;q=0, */*;q=0.1", sampleTodo(), "application/json"},
+		{"unknown type", "text/html", sampleTodo(), "application/json"},
+		{"protobuf without a schema", "application/x-protobuf", map[string]string{"message": "ok"}, "application/json"},
+		{"msgpack without a schema", "application/x-protobuf, application/msgpack;q=0.5", map[string]string{"message": "ok"}, "application/msgpack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/todos/"+testTodoID, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handler.WriteJSON(w, r, http.StatusOK, tt.data)
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type: got %q, want %q", got, tt.wantType)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary: got %q, want Accept", got)
+			}
+		})
+	}
+}
+
+// TestEncodings_RoundTrip decodes the same list in every encoding and
+// expects the todos the repository returned.
+func TestEncodings_RoundTrip(t *testing.T) {
+	due := time.Date(2025, 12, 31, 9, 30, 0, 0, time.UTC)
+	estimate := 45
+	full := sampleTodo()
+	full.Priority = model.TodoPriorityHigh
+	full.Important = true
+	full.DueAt = &due
+	full.EstimateMinutes = &estimate
+	full.BlockedBy = []string{testBlockerID}
+	full.Owner = &model.TodoOwner{ID: "user-1", Email: "user@example.com", Nickname: "kim"}
+	full.Counts = &model.TodoCounts{BlockedBy: 1, TimeEntries: 3}
+	minimal := model.Todo{ID: testBlockerID, UserID: "user-1", Title: "Call mom", Status: model.TodoStatusCompleted,
+		Priority: model.TodoPriorityNone, CreatedAt: now, UpdatedAt: now}
+	want := model.TodoListResult{Todos: []model.Todo{full, minimal}, NextCursor: testBlockerID}
+
+	repo := &mockTodoRepo{
+		listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+			return want, nil
+		},
+	}
+	h := newTodoHandler(repo)
+
+	decoders := map[string]func(t *testing.T, body []byte) model.TodoListResult{
+		"application/json": func(t *testing.T, body []byte) model.TodoListResult {
+			return decodeListJSON(t, body)
+		},
+		"application/msgpack": func(t *testing.T, body []byte) model.TodoListResult {
+			var v any
+			if err := msgpack.Unmarshal(body, &v); err != nil {
+				t.Fatalf("msgpack: %v", err)
+			}
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("msgpack to JSON: %v", err)
+			}
+			return decodeListJSON(t, data)
+		},
+		"application/x-protobuf": func(t *testing.T, body []byte) model.TodoListResult {
+			var list todov1.TodoList
+			if err := proto.Unmarshal(body, &list); err != nil {
+				t.Fatalf("protobuf: %v", err)
+			}
+			data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(&list)
+			if err != nil {
+				t.Fatalf("protobuf to JSON: %v", err)
+			}
+			return decodeListJSON(t, data)
+		},
+	}
+
+	for contentType, decode := range decoders {
+		t.Run(contentType, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+			req.Header.Set("Accept", contentType)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d (body: %s)", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != contentType {
+				t.Fatalf("Content-Type: got %q", got)
+			}
+			if got := decode(t, w.Body.Bytes()); !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the list:\ngot  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func decodeListJSON(t *testing.T, body []byte) model.TodoListResult {
+	t.Helper()
+	var result model.TodoListResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return result
+}
+
+// TestDecodeJSON_Encodings creates the same todo from a body in every
+// encoding.
+func TestDecodeJSON_Encodings(t *testing.T) {
+	due := time.Date(2025, 12, 31, 9, 30, 0, 0, time.UTC)
+
+	msgpackBody, err := msgpack.Marshal(map[string]any{
+		"title": "Buy milk", "due_at": due.Format(time.RFC3339), "priority": "high", "important": true, "estimate_minutes": 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protoBody, err := proto.Marshal(&todov1.CreateTodoRequest{
+		Title: "Buy milk", DueAt: timestamppb.New(due), Priority: "high", Important: true, EstimateMinutes: proto.Int32(20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int
+	}{
+		{"json", "application/json", []byte(`{"title":"Buy milk","due_at":"2025-12-31T09:30:00Z","priority":"high","important":true,"estimate_minutes":20}`), http.StatusCreated},
+		{"msgpack", "application/msgpack", msgpackBody, http.StatusCreated},
+		{"protobuf", "application/x-protobuf", protoBody, http.StatusCreated},
+		{"malformed msgpack", "application/msgpack", []byte{0xc1}, http.StatusBadRequest},
+		{"malformed protobuf", "application/x-protobuf", []byte{0xff, 0xff}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created model.Todo
+			repo := &mockTodoRepo{
+				createFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+					todo.CreatedAt, todo.UpdatedAt = now, now
+					created = todo
+					return todo, nil
+				},
+			}
+			h := newTodoHandler(repo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/todos", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			if created.Title != "Buy milk" || created.Priority != model.TodoPriorityHigh || !created.Important ||
+				created.DueAt == nil || !created.DueAt.Equal(due) ||
+				created.EstimateMinutes == nil || *created.EstimateMinutes != 20 {
+				t.Errorf("unexpected todo %+v", created)
+			}
+		})
+	}
+}
+
+// TestDecodeJSON_ProtobufUpdates sends the update messages the HTTP API
+// shares with protobuf clients, which name the todo in the path.
+func TestDecodeJSON_ProtobufUpdates(t *testing.T) {
+	updateBody, err := proto.Marshal(&todov1.UpdateTodoRequest{Title: proto.String("Buy milk")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusBody, err := proto.Marshal(&todov1.UpdateTodoStatusRequest{Status: "completed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   []byte
+		check  func(model.Todo) bool
+	}{
+		{"update", http.MethodPut, "/api/v1/todos/" + testTodoID, updateBody,
+			func(todo model.Todo) bool { return todo.Title == "Buy milk" }},
+		{"status", http.MethodPatch, "/api/v1/todos/" + testTodoID + "/status", statusBody,
+			func(todo model.Todo) bool { return todo.Status == model.TodoStatusCompleted }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated model.Todo
+			repo := &mockTodoRepo{
+				getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+					return sampleTodo(), nil
+				},
+				updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+					updated = todo
+					return todo, nil
+				},
+			}
+			h := newTodoHandler(repo)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d (body: %s)", w.Code, w.Body.String())
+			}
+			if !tt.check(updated) {
+				t.Errorf("unexpected todo %+v", updated)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_MsgpackLimits(t *testing.T) {
+	// 1 million nested single-element arrays.
+	deep := append(bytes.Repeat([]byte{0x91}, 1_000_000), 0xc0)
+	// An array claiming 2^32-1 elements, followed by 100,000 nils.
+	wide := append([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, bytes.Repeat([]byte{0xc0}, 100_000)...)
+	// A map with a non-string key.
+	intKey := []byte{0x81, 0x01, 0xc0}
+	// 32 levels of nesting are fine; the todo is then rejected as JSON.
+	nested := append(bytes.Repeat([]byte{0x91}, 32), 0xc0)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		wantStatus int
+		wantCode   string
+	}{
+		{"deeply nested", deep, http.StatusBadRequest, "INVALID_BODY"},
+		{"too many values", wide, http.StatusBadRequest, "INVALID_BODY"},
+		{"non-string key", intKey, http.StatusBadRequest, "INVALID_BODY"},
+		{"nested within the limit", nested, http.StatusBadRequest, "INVALID_JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTodoHandler(&mockTodoRepo{})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/todos", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/msgpack")
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp handler.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error.Code)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_ProtobufWithoutSchema(t *testing.T) {
+	repo := &mockTodoRepo{}
+	h := newTodoHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/todos/"+testTodoID+"/blockers", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req = withUserID(req, "user-1")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status 415, got %d (body: %s)", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/stream"
+)
+
+const (
+	defaultHeartbeatInterval = 15 * time.Second
+	// streamWriteTimeout bounds each write to an event stream. The server's
+	// WriteTimeout covers a whole response, which a stream never finishes, so
+	// the handler clears it and applies this rolling deadline instead.
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry is the reconnect delay suggested to clients, in milliseconds.
+	streamRetry = 3000
+)
+
+// EventStreamHandler serves a user's todo events as Server-Sent Events.
+type EventStreamHandler struct {
+	broker    *stream.Broker
+	heartbeat time.Duration
+}
+
+func NewEventStreamHandler(broker *stream.Broker, heartbeat time.Duration) *EventStreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeatInterval
+	}
+	return &EventStreamHandler{broker: broker, heartbeat: heartbeat}
+}
+
+func (h *EventStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "streaming not supported")
+		return
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter lets
+	// clients resume on a fresh connection, where they cannot set headers.
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub, missed, ok := h.broker.Subscribe(getUserID(r), lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sse := &sseWriter{w: w, rc: rc}
+	sse.printf("retry: %d\n\n", streamRetry)
+	if !ok {
+		// Missed events are gone; tell the client to refetch its todos.
+		sse.printf("event: reset\ndata: {}\n\n")
+	}
+	for _, msg := range missed {
+		sse.message(msg)
+	}
+	if !sse.flush() {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case msg := <-sub.Messages():
+			sse.message(msg)
+		case <-heartbeat.C:
+			sse.printf(": heartbeat\n\n")
+		}
+		if !sse.flush() {
+			return
+		}
+	}
+}
+
+// sseWriter writes Server-Sent Events, remembering the first write error so
+// callers only check once per flush.
+type sseWriter struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	err error
+}
+
+func (s *sseWriter) printf(format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+	if err := s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, format, args...)
+}
+
+func (s *sseWriter) message(msg stream.Message) {
+	data, err := json.Marshal(msg.Event)
+	if err != nil {
+		slog.Error("failed to encode event", "event_id", msg.Event.ID, "error", err)
+		return
+	}
+	s.printf("id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Event.Type, data)
+}
+
+func (s *sseWriter) flush() bool {
+	if s.err == nil {
+		s.err = s.rc.Flush()
+	}
+	return s.err == nil
+}
@@ -0,0 +1,160 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/stream"
+)
+
+// startEventServer serves the stream with a WriteTimeout far shorter than
+// the test, so it also checks that streams are exempt from it.
+func startEventServer(t *testing.T, broker *stream.Broker, heartbeat time.Duration) *httptest.Server {
+	t.Helper()
+	h := handler.NewEventStreamHandler(broker, heartbeat)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := middleware.SetUserID(r.Context(), r.Header.Get("X-User-ID"))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	t.Cleanup(func() {
+		broker.Close()
+		srv.Close()
+	})
+	return srv
+}
+
+func openStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set("X-User-ID", "user-1")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %s", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// readFrame reads one SSE frame, up to the blank line that ends it.
+func readFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var frame strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended: %v", err)
+		}
+		if line == "\n" {
+			return frame.String()
+		}
+		frame.WriteString(line)
+	}
+}
+
+func frameField(frame, field string) string {
+	for _, line := range strings.Split(frame, "\n") {
+		if v, ok := strings.CutPrefix(line, field+": "); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func TestEventStreamHandler_StreamsEvents(t *testing.T) {
+	broker := stream.NewBroker(stream.Config{})
+	srv := startEventServer(t, broker, time.Hour)
+	r := openStream(t, srv.URL, "")
+
+	if frame := readFrame(t, r); !strings.HasPrefix(frame, "retry: ") {
+		t.Fatalf("expected retry frame, got %q", frame)
+	}
+
+	// Publish after the WriteTimeout has elapsed.
+	time.Sleep(200 * time.Millisecond)
+	broker.Publish(context.Background(), model.Event{ID: "evt_other", Type: model.EventTodoCreated, UserID: "user-2", TodoID: "todo-2"})
+	broker.Publish(context.Background(), model.Event{ID: "evt_1", Type: model.EventTodoCompleted, UserID: "user-1", TodoID: "todo-1"})
+
+	frame := readFrame(t, r)
+	if got := frameField(frame, "event"); got != "todo.completed" {
+		t.Errorf("expected todo.completed, got %q", got)
+	}
+	if got := frameField(frame, "data"); !strings.Contains(got, `"todo_id":"todo-1"`) {
+		t.Errorf("unexpected data: %s", got)
+	}
+	if frameField(frame, "id") == "" {
+		t.Error("expected event id")
+	}
+}
+
+func TestEventStreamHandler_Resume(t *testing.T) {
+	broker := stream.NewBroker(stream.Config{})
+	srv := startEventServer(t, broker, time.Hour)
+
+	first := openStream(t, srv.URL, "")
+	readFrame(t, first)
+	broker.Publish(context.Background(), model.Event{ID: "evt_1", Type: model.EventTodoCreated, UserID: "user-1", TodoID: "todo-1"})
+	lastID := frameField(readFrame(t, first), "id")
+
+	broker.Publish(context.Background(), model.Event{ID: "evt_2", Type: model.EventTodoUpdated, UserID: "user-1", TodoID: "todo-1"})
+
+	t.Run("replays missed events", func(t *testing.T) {
+		r := openStream(t, srv.URL, lastID)
+		readFrame(t, r)
+		if got := frameField(readFrame(t, r), "event"); got != "todo.updated" {
+			t.Errorf("expected replayed todo.updated, got %q", got)
+		}
+	})
+
+	t.Run("unknown id asks for reset", func(t *testing.T) {
+		r := openStream(t, srv.URL, "stale-7")
+		readFrame(t, r)
+		if got := frameField(readFrame(t, r), "event"); got != "reset" {
+			t.Errorf("expected reset, got %q", got)
+		}
+	})
+}
+
+func TestEventStreamHandler_Heartbeat(t *testing.T) {
+	srv := startEventServer(t, stream.NewBroker(stream.Config{}), 20*time.Millisecond)
+	r := openStream(t, srv.URL, "")
+	readFrame(t, r)
+
+	if frame := readFrame(t, r); !strings.HasPrefix(frame, ": heartbeat") {
+		t.Errorf("expected heartbeat comment, got %q", frame)
+	}
+}
+
+func TestEventStreamHandler_MethodNotAllowed(t *testing.T) {
+	h := handler.NewEventStreamHandler(stream.NewBroker(stream.Config{}), 0)
+	req := withUserID(httptest.NewRequest(http.MethodPost, "/api/v1/events", nil), "user-1")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,7 @@
+package handler
+
+// Exported for tests in handler_test.
+var (
+	HandleServiceError = handleServiceError
+	HandleAuthError    = handleAuthError
+)
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// alwaysProjected are kept by every projection: id, which identifies the
+// todo and pages the list, and the expansions, so that expand= works
+// together with fields=.
+var alwaysProjected = []string{"id", string(model.TodoExpandOwner), string(model.TodoExpandCounts)}
+
+// parseListShape reads the comma-separated fields= and expand= query
+// parameters of todo lists. It writes a 400 naming every unknown entry and
+// returns false if there is one.
+func parseListShape(w http.ResponseWriter, r *http.Request) (fields []string, expand []model.TodoExpand, ok bool) {
+	var errs validate.Errors
+	for _, f := range splitQueryList(r.URL.Query().Get("fields")) {
+		if !model.IsTodoField(f) {
+			errs = append(errs, validate.FieldError{Field: "fields." + f, Reason: validate.ReasonUnknownField})
+			continue
+		}
+		fields = append(fields, f)
+	}
+	for _, e := range splitQueryList(r.URL.Query().Get("expand")) {
+		if !model.TodoExpand(e).IsValid() {
+			errs = append(errs, validate.FieldError{Field: "expand." + e, Reason: validate.ReasonInvalidValue})
+			continue
+		}
+		expand = append(expand, model.TodoExpand(e))
+	}
+	if len(errs) > 0 {
+		WriteFieldErrors(w, r, errs)
+		return nil, nil, false
+	}
+	return fields, expand, true
+}
+
+func splitQueryList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// todoPage is a model.TodoListResult whose todos are projected.
+type todoPage struct {
+	Todos      []map[string]json.RawMessage `json:"todos"`
+	NextCursor string                       `json:"next_cursor,omitempty"`
+}
+
+// projectPage narrows every todo in result to id, fields and any expansions.
+// Without fields it returns result unchanged.
+func projectPage(result model.TodoListResult, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return result, nil
+	}
+	keep := slices.Concat(alwaysProjected, fields)
+	page := todoPage{Todos: make([]map[string]json.RawMessage, 0, len(result.Todos)), NextCursor: result.NextCursor}
+	for _, todo := range result.Todos {
+		data, err := json.Marshal(todo)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		projected := make(map[string]json.RawMessage, len(keep))
+		for _, f := range keep {
+			if v, ok := all[f]; ok {
+				projected[f] = v
+			}
+		}
+		page.Todos = append(page.Todos, projected)
+	}
+	return page, nil
+}
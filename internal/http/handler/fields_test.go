@@ -0,0 +1,166 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+func TestTodoHandler_ListFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantFields []string
+		wantExpand []model.TodoExpand
+		wantKeys   []string
+	}{
+		{
+			name:     "all fields by default",
+			query:    "",
+			wantKeys: []string{"created_at", "description", "id", "important", "priority", "status", "title", "updated_at", "urgent", "user_id"},
+		},
+		{
+			name:       "sparse fieldset",
+			query:      "?fields=title,%20status,due_at",
+			wantFields: []string{"title", "status", "due_at"},
+			wantKeys:   []string{"id", "status", "title"},
+		},
+		{
+			name:       "sparse fieldset with expansions",
+			query:      "?fields=title&expand=owner,counts",
+			wantFields: []string{"title"},
+			wantExpand: []model.TodoExpand{model.TodoExpandOwner, model.TodoExpandCounts},
+			wantKeys:   []string{"counts", "id", "owner", "title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockTodoRepo{
+				listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+					if !slices.Equal(params.Fields, tt.wantFields) || !slices.Equal(params.Expand, tt.wantExpand) {
+						t.Errorf("repository got fields=%v expand=%v, want %v %v", params.Fields, params.Expand, tt.wantFields, tt.wantExpand)
+					}
+					todo := sampleTodo()
+					if params.Expands(model.TodoExpandOwner) {
+						todo.Owner = &model.TodoOwner{ID: "user-1", Email: "a@example.com"}
+					}
+					if params.Expands(model.TodoExpandCounts) {
+						todo.Counts = &model.TodoCounts{BlockedBy: 1}
+					}
+					return model.TodoListResult{Todos: []model.Todo{todo}}, nil
+				},
+			}
+			h := newTodoHandler(repo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos"+tt.query, nil)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d (body: %s)", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Todos []map[string]json.RawMessage `json:"todos"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if len(resp.Todos) != 1 {
+				t.Fatalf("expected 1 todo, got %d", len(resp.Todos))
+			}
+			var keys []string
+			for k := range resp.Todos[0] {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("keys: got %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_ListFieldsInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantFields []string
+	}{
+		{"unknown field", "?fields=title,secret", []string{"fields.secret"}},
+		{"dependency lists are not listed", "?fields=blocked_by", []string{"fields.blocked_by"}},
+		{"unknown expansion", "?expand=owner,tags", []string{"expand.tags"}},
+		{"both", "?fields=x&expand=y", []string{"fields.x", "expand.y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTodoHandler(&mockTodoRepo{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos"+tt.query, nil)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", w.Code)
+			}
+			var resp handler.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			var got []string
+			for _, f := range resp.Error.Fields {
+				got = append(got, f.Field)
+			}
+			if resp.Error.Code != "INVALID_INPUT" || !slices.Equal(got, tt.wantFields) {
+				t.Errorf("got %s %v, want INVALID_INPUT %v", resp.Error.Code, got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestViewHandler_MatrixFields(t *testing.T) {
+	repo := &mockTodoRepo{
+		listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+			if !slices.Equal(params.Fields, []string{"title"}) {
+				t.Errorf("repository got fields=%v", params.Fields)
+			}
+			return model.TodoListResult{Todos: []model.Todo{sampleTodo()}}, nil
+		},
+	}
+	h := handler.NewViewHandler(service.NewTodoService(repo))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views/matrix?fields=title", nil)
+	req = withUserID(req, "user-1")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+	var resp map[string]struct {
+		Todos []map[string]json.RawMessage `json:"todos"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	for _, q := range model.Quadrants {
+		todos := resp[string(q)].Todos
+		if len(todos) != 1 || len(todos[0]) != 2 {
+			t.Errorf("%s: expected one todo with id and title, got %v", q, todos)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// GraphQLHandler serves GraphQL over HTTP. Queries may be sent with GET or
+// POST; mutations only with POST, so that links and prefetches cannot
+// change data.
+type GraphQLHandler struct {
+	exec *graphql.Executor
+}
+
+func NewGraphQLHandler(exec *graphql.Executor) *GraphQLHandler {
+	return &GraphQLHandler{exec: exec}
+}
+
+// graphqlRequest is the JSON body of a POST. Extensions, such as persisted
+// query hashes, are accepted and ignored.
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    map[string]any `json:"extensions"`
+}
+
+func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req = graphql.Request{Query: q.Get("query"), OperationName: q.Get("operationName"), ReadOnly: true}
+		if vars := q.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				WriteFieldErrors(w, r, validate.Errors{{Field: "variables", Reason: validate.ReasonInvalidType}})
+				return
+			}
+		}
+	case http.MethodPost:
+		var body graphqlRequest
+		if !decodeJSON(w, r, &body) {
+			return
+		}
+		req = graphql.Request{Query: body.Query, OperationName: body.OperationName, Variables: body.Variables}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+
+	if req.Query == "" {
+		WriteFieldErrors(w, r, validate.Errors{{Field: "query", Reason: validate.ReasonRequired}})
+		return
+	}
+
+	result, err := h.exec.Execute(r.Context(), req)
+	if errors.Is(err, graphql.ErrReadOnly) {
+		w.Header().Set("Allow", "POST")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "mutations must be sent with POST")
+		return
+	}
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, result)
+}
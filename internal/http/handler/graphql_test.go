@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+const graphqlTodoID = "0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11"
+
+func newGraphQLHandler(t *testing.T) *handler.GraphQLHandler {
+	t.Helper()
+	repo := &mockTodoRepo{
+		listIDsFn: func(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+			todos := make([]model.Todo, len(ids))
+			for i, id := range ids {
+				todos[i] = model.Todo{ID: id, UserID: userID, Title: "Write tests", Status: model.TodoStatusPending, CreatedAt: now, UpdatedAt: now}
+			}
+			return todos, nil
+		},
+	}
+	exec, err := graphql.NewExecutor(service.NewTodoService(repo), nil, graphql.DefaultLimits)
+	if err != nil {
+		t.Fatalf("failed to build executor: %v", err)
+	}
+	return handler.NewGraphQLHandler(exec)
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	query := `{ todo(id: "` + graphqlTodoID + `") { title } }`
+	mutation := `mutation { deleteTodo(id: "` + graphqlTodoID + `") }`
+	post := func(q string) string {
+		b, _ := json.Marshal(map[string]any{"query": q})
+		return string(b)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"post query", http.MethodPost, "/api/v1/graphql", post(query), http.StatusOK, ""},
+		{"get query", http.MethodGet, "/api/v1/graphql?query=" + url.QueryEscape(query), "", http.StatusOK, ""},
+		{"get with variables", http.MethodGet, "/api/v1/graphql?query=" +
+			url.QueryEscape(`query($id: ID!) { todo(id: $id) { title } }`) +
+			"&variables=" + url.QueryEscape(`{"id":"`+graphqlTodoID+`"}`), "", http.StatusOK, ""},
+		{"get invalid variables", http.MethodGet, "/api/v1/graphql?query=" + url.QueryEscape(query) + "&variables=nope", "", http.StatusBadRequest, ""},
+		{"get mutation", http.MethodGet, "/api/v1/graphql?query=" + url.QueryEscape(mutation), "", http.StatusMethodNotAllowed, "POST"},
+		{"missing query", http.MethodPost, "/api/v1/graphql", `{}`, http.StatusBadRequest, ""},
+		{"invalid json", http.MethodPost, "/api/v1/graphql", `{bad`, http.StatusBadRequest, ""},
+		{"query errors are 200", http.MethodPost, "/api/v1/graphql", post(`{ nope }`), http.StatusOK, ""},
+		{"wrong method", http.MethodDelete, "/api/v1/graphql", "", http.StatusMethodNotAllowed, "GET, POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newGraphQLHandler(t)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_Result(t *testing.T) {
+	h := newGraphQLHandler(t)
+
+	body, _ := json.Marshal(map[string]any{
+		"query":     `query Get($id: ID!) { todo(id: $id) { id title status } }`,
+		"variables": map[string]any{"id": graphqlTodoID},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = withUserID(req, "user-1")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var resp struct {
+		Data struct {
+			Todo struct {
+				ID     string `json:"id"`
+				Title  string `json:"title"`
+				Status string `json:"status"`
+			} `json:"todo"`
+		} `json:"data"`
+		Errors []any `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if resp.Data.Todo.ID != graphqlTodoID || resp.Data.Todo.Title != "Write tests" || resp.Data.Todo.Status != "PENDING" {
+		t.Errorf("unexpected todo: %+v", resp.Data.Todo)
+	}
+}
@@ -1,7 +1,13 @@
 package handler
 
-import "net/http"
+import (
+	"net/http"
 
+	"github.com/jaekwang-park/todo-api/internal/health"
+)
+
+// HealthHandler answers liveness probes: it reports ok as long as the
+// process is serving requests.
 type HealthHandler struct{}
 
 func NewHealthHandler() *HealthHandler {
@@ -10,9 +16,65 @@ func NewHealthHandler() *HealthHandler {
 
 func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	WriteJSON(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// DrainHandler answers the load balancer's health check: ok while the
+// process is serving requests and 503 once it starts draining. It does not
+// check dependencies, so a database outage does not take every instance
+// out of rotation at once; ReadinessHandler reports those.
+type DrainHandler struct {
+	checker *health.Checker
+}
+
+func NewDrainHandler(checker *health.Checker) *DrainHandler {
+	return &DrainHandler{checker: checker}
+}
+
+func (h *DrainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	if h.checker.Draining() {
+		WriteJSON(w, r, http.StatusServiceUnavailable, health.Report{Status: health.StatusDraining})
+		return
+	}
+	WriteJSON(w, r, http.StatusOK, health.Report{Status: health.StatusOK})
+}
+
+// ReadinessHandler answers readiness probes with 200 when every check
+// passes and 503 otherwise, including while the server drains.
+type ReadinessHandler struct {
+	checker *health.Checker
+	details bool
+}
+
+// NewReadinessHandler returns a handler for checker. With details, the
+// response includes each check's result; leave it off where the endpoint
+// is reachable from outside, since errors name internal hosts.
+func NewReadinessHandler(checker *health.Checker, details bool) *ReadinessHandler {
+	return &ReadinessHandler{checker: checker, details: details}
+}
+
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	report := h.checker.Run(r.Context())
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	if !h.details {
+		report.Checks = nil
+	}
+	WriteJSON(w, r, status, report)
 }
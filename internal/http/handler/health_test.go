@@ -1,11 +1,15 @@
 package handler_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jaekwang-park/todo-api/internal/health"
 	"github.com/jaekwang-park/todo-api/internal/http/handler"
 )
 
@@ -57,3 +61,103 @@ func TestHealthHandler_MethodNotAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestReadinessHandler(t *testing.T) {
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	passing := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name       string
+		check      health.Check
+		draining   bool
+		details    bool
+		wantCode   int
+		wantStatus string
+	}{
+		{"ready", passing, false, false, http.StatusOK, health.StatusOK},
+		{"dependency down", failing, false, false, http.StatusServiceUnavailable, health.StatusUnavailable},
+		{"draining", passing, true, false, http.StatusServiceUnavailable, health.StatusDraining},
+		{"details", failing, false, true, http.StatusServiceUnavailable, health.StatusUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker(time.Second)
+			checker.Add("database", tt.check)
+			if tt.draining {
+				checker.SetDraining()
+			}
+			h := handler.NewReadinessHandler(checker, tt.details)
+			req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+
+			var result health.Report
+			if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status=%s, got %s", tt.wantStatus, result.Status)
+			}
+			if tt.details {
+				if r := result.Checks["database"]; r.Error != "connection refused" {
+					t.Errorf("expected database error in details, got %+v", r)
+				}
+			} else if len(result.Checks) != 0 {
+				t.Errorf("expected no check details, got %v", result.Checks)
+			}
+		})
+	}
+}
+
+func TestDrainHandler(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	h := handler.NewDrainHandler(checker)
+
+	for _, tt := range []struct {
+		name       string
+		draining   bool
+		wantCode   int
+		wantStatus string
+	}{
+		{"dependency down", false, http.StatusOK, health.StatusOK},
+		{"draining", true, http.StatusServiceUnavailable, health.StatusDraining},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.draining {
+				checker.SetDraining()
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var result health.Report
+			if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status=%s, got %s", tt.wantStatus, result.Status)
+			}
+		})
+	}
+}
+
+func TestReadinessHandler_MethodNotAllowed(t *testing.T) {
+	h := handler.NewReadinessHandler(health.NewChecker(time.Second), false)
+	req := httptest.NewRequest(http.MethodPost, "/health/ready", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
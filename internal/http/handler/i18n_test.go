@@ -0,0 +1,109 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/cognito"
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/i18n"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// TestErrorCodes_Translated fails when LookupError or handleServiceError can
+// return a code that has no text in every supported language.
+func TestErrorCodes_Translated(t *testing.T) {
+	for _, code := range cognito.ErrorCodes() {
+		if !i18n.Translated(code) {
+			t.Errorf("cognito code %s has no translation", code)
+		}
+	}
+
+	errs := []error{
+		service.ErrNotFound,
+		service.ErrInvalidInput,
+		service.ErrForbidden,
+		service.ErrConflict,
+		fmt.Errorf("wrapped: %w", validate.Errors{{Field: "title", Reason: validate.ReasonRequired}}),
+		errors.New("boom"),
+		cognito.ErrUserAlreadyExists,
+		cognito.ErrUserNotFound,
+		cognito.ErrUserNotConfirmed,
+		cognito.ErrInvalidPassword,
+		cognito.ErrInvalidCode,
+		cognito.ErrCodeExpired,
+		cognito.ErrTooManyRequests,
+		cognito.ErrNotAuthorized,
+		cognito.ErrLimitExceeded,
+		cognito.ErrPasswordResetRequired,
+		cognito.ErrInvalidParameter,
+	}
+	writers := map[string]func(http.ResponseWriter, *http.Request, error){
+		"handleServiceError": handler.HandleServiceError,
+		"handleAuthError":    handler.HandleAuthError,
+	}
+
+	for name, write := range writers {
+		for _, err := range errs {
+			t.Run(name+"/"+err.Error(), func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+				r.Header.Set("Accept-Language", "ko")
+				w := httptest.NewRecorder()
+
+				write(w, r, err)
+
+				var resp handler.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				if !i18n.Translated(resp.Error.Code) {
+					t.Errorf("code %s has no translation", resp.Error.Code)
+				}
+				if got := w.Header().Get("Content-Language"); got != "ko" {
+					t.Errorf("Content-Language: got %q", got)
+				}
+			})
+		}
+	}
+}
+
+func TestWriteError_Localized(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantMessage    string
+		wantLanguage   string
+	}{
+		{"default keeps the caller's message", "", "todo 42 not found", "en"},
+		{"korean uses the catalog", "ko-KR,ko;q=0.9", "리소스를 찾을 수 없습니다", "ko"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/todos/42", nil)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+
+			handler.WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "todo 42 not found")
+
+			var resp handler.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if resp.Error.Code != "NOT_FOUND" {
+				t.Errorf("code: got %q", resp.Error.Code)
+			}
+			if resp.Error.Message != tt.wantMessage {
+				t.Errorf("message: got %q, want %q", resp.Error.Message, tt.wantMessage)
+			}
+			if got := w.Header().Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Content-Language: got %q, want %q", got, tt.wantLanguage)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pb"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+)
+
+var errNoProtoSchema = errors.New("no Protocol Buffers schema")
+
+// protoRequest is a request body with a Protocol Buffers schema.
+type protoRequest interface {
+	protoMessage() proto.Message
+}
+
+func (*createTodoRequest) protoMessage() proto.Message   { return new(todov1.CreateTodoRequest) }
+func (*updateTodoRequest) protoMessage() proto.Message   { return new(todov1.UpdateTodoRequest) }
+func (*updateStatusRequest) protoMessage() proto.Message { return new(todov1.UpdateTodoStatusRequest) }
+
+// hasProtoSchema reports whether toProto can encode data.
+func hasProtoSchema(data any) bool {
+	switch data.(type) {
+	case model.Todo, model.TodoListResult, todoPage:
+		return true
+	}
+	return false
+}
+
+// toProto converts a response that hasProtoSchema accepts.
+func toProto(data any) (proto.Message, error) {
+	switch d := data.(type) {
+	case model.Todo:
+		return pb.FromTodo(d), nil
+	case model.TodoListResult:
+		return pb.FromTodoList(d), nil
+	case todoPage:
+		// Fields left out by the projection read as their defaults, as
+		// proto3 cannot tell them apart from zero values.
+		body, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		var result model.TodoListResult
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		return pb.FromTodoList(result), nil
+	}
+	return nil, errNoProtoSchema
+}
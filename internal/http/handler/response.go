@@ -4,30 +4,77 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/jaekwang-park/todo-api/internal/i18n"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/validate"
 )
 
-type ErrorBody struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
+// ErrorBody and ErrorResponse are the default error shape. Clients that
+// accept application/problem+json get an RFC 7807 document instead.
+type ErrorBody = middleware.ErrorBody
 
-type ErrorResponse struct {
-	Error ErrorBody `json:"error"`
-}
+type ErrorResponse = middleware.ErrorResponse
+
+// WriteJSON writes data as JSON, or as MessagePack or Protocol Buffers when
+// the Accept header prefers them. Only todos and todo lists have a Protocol
+// Buffers schema; other data is written as JSON to clients asking for it.
+func WriteJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	w.Header().Add("Vary", "Accept")
+
+	contentType := negotiateContentType(r.Header.Get("Accept"), hasProtoSchema(data))
+	if contentType == jsonContentType {
+		w.Header().Set("Content-Type", jsonContentType)
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		}
+		return
+	}
 
-func WriteJSON(w http.ResponseWriter, status int, data any) {
-	w.Header().Set("Content-Type", "application/json")
+	var body []byte
+	var err error
+	if contentType == msgpackContentType {
+		body, err = jsonToMsgpack(data)
+	} else {
+		var msg proto.Message
+		if msg, err = toProto(data); err == nil {
+			body, err = proto.Marshal(msg)
+		}
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response", "content_type", contentType, "error", err)
+		WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		slog.Error("failed to encode response", "error", err)
+	if _, err := w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write response", "error", err)
 	}
 }
 
-func WriteError(w http.ResponseWriter, status int, code, message string) {
-	WriteJSON(w, status, ErrorResponse{
-		Error: ErrorBody{
-			Code:    code,
-			Message: message,
-		},
-	})
+// WriteError writes an error response in the format the client accepts.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	middleware.WriteError(w, r, status, code, message)
+}
+
+// WriteFieldErrors writes a 400 INVALID_INPUT response listing every field
+// that failed validation.
+func WriteFieldErrors(w http.ResponseWriter, r *http.Request, errs validate.Errors) {
+	middleware.WriteFieldErrors(w, r, errs)
+}
+
+// WriteMessage writes a 200 {"message": ...} response with the catalog text
+// for key in the request's language.
+func WriteMessage(w http.ResponseWriter, r *http.Request, key string) {
+	lang := middleware.Language(r)
+	msg, ok := i18n.Message(lang, key)
+	if !ok {
+		msg = key
+	}
+	w.Header().Set("Content-Language", lang.String())
+	WriteJSON(w, r, http.StatusOK, map[string]string{"message": msg})
 }
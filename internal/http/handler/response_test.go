@@ -7,13 +7,15 @@ import (
 	"testing"
 
 	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
 )
 
 func TestWriteJSON(t *testing.T) {
 	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/health", nil)
 	data := map[string]string{"status": "ok"}
 
-	handler.WriteJSON(w, http.StatusOK, data)
+	handler.WriteJSON(w, r, http.StatusOK, data)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", w.Code)
@@ -33,8 +35,9 @@ func TestWriteJSON(t *testing.T) {
 
 func TestWriteError(t *testing.T) {
 	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/todos", nil)
 
-	handler.WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "name is required")
+	handler.WriteError(w, r, http.StatusBadRequest, "INVALID_INPUT", "name is required")
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("expected status 400, got %d", w.Code)
@@ -51,3 +54,19 @@ func TestWriteError(t *testing.T) {
 		t.Errorf("expected message='name is required', got %s", result.Error.Message)
 	}
 }
+
+func TestWriteError_IncludesRequestID(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(middleware.RequestIDHeader, "req-123")
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/todos/x", nil)
+
+	handler.WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
+
+	var result handler.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Error.RequestID != "req-123" {
+		t.Errorf("expected request_id=req-123, got %q", result.Error.RequestID)
+	}
+}
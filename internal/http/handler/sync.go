@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+// SyncHandler serves delta sync for offline-first clients.
+type SyncHandler struct {
+	svc *service.SyncService
+}
+
+func NewSyncHandler(svc *service.SyncService) *SyncHandler {
+	return &SyncHandler{svc: svc}
+}
+
+func (h *SyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleChanges(w, r)
+	case http.MethodPost:
+		h.handleMutations(w, r)
+	default:
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+	}
+}
+
+// handleChanges returns one page of changes since the ?since= token. Pages
+// are larger than list pages since clients page until caught up.
+func (h *SyncHandler) handleChanges(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 500 {
+			limit = l
+		}
+	}
+
+	result, err := h.svc.Changes(r.Context(), getUserID(r), r.URL.Query().Get("since"), limit)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, result)
+}
+
+type syncMutationRequest struct {
+	Op            string            `json:"op"`
+	ClientID      string            `json:"client_id,omitempty"`
+	ID            string            `json:"id,omitempty"`
+	Fields        syncFieldsRequest `json:"fields"`
+	BaseUpdatedAt *time.Time        `json:"base_updated_at,omitempty"`
+	ModifiedAt    *time.Time        `json:"modified_at,omitempty"`
+	OnConflict    string            `json:"on_conflict,omitempty"`
+}
+
+type syncFieldsRequest struct {
+	updateTodoRequest
+	Status *string `json:"status,omitempty"`
+}
+
+type syncMutationsRequest struct {
+	Mutations []syncMutationRequest `json:"mutations"`
+}
+
+type syncMutationsResponse struct {
+	Results []model.MutationResult `json:"results"`
+}
+
+// handleMutations applies a batch of offline changes. The response is 200
+// even when individual mutations fail or conflict; see each result's status.
+func (h *SyncHandler) handleMutations(w http.ResponseWriter, r *http.Request) {
+	var req syncMutationsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	mutations := make([]service.SyncMutation, len(req.Mutations))
+	for i, m := range req.Mutations {
+		f := m.Fields
+		mutations[i] = service.SyncMutation{
+			Op:       model.SyncOp(m.Op),
+			ClientID: m.ClientID,
+			ID:       m.ID,
+			Fields: service.UpdateTodoInput{
+				Title:           f.Title,
+				Description:     f.Description,
+				DueAt:           f.DueAt,
+				Priority:        f.Priority,
+				Important:       f.Important,
+				Urgent:          f.Urgent,
+				EstimateMinutes: f.EstimateMinutes,
+			},
+			Status:        f.Status,
+			BaseUpdatedAt: m.BaseUpdatedAt,
+			ModifiedAt:    m.ModifiedAt,
+			OnConflict:    model.ConflictStrategy(m.OnConflict),
+		}
+	}
+
+	results, err := h.svc.ApplyMutations(r.Context(), getUserID(r), mutations)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, syncMutationsResponse{Results: results})
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+type stubChangeRepo struct{}
+
+func (stubChangeRepo) Changes(ctx context.Context, userID string, since model.SyncPosition, limit int) (model.TodoChanges, error) {
+	return model.TodoChanges{Todos: []model.Todo{}, Deleted: []model.Tombstone{}, Next: model.SyncPosition{Seq: 7}}, nil
+}
+
+func (stubChangeRepo) PruneTombstones(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestSyncHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"changes", http.MethodGet, "/api/v1/sync", "", http.StatusOK},
+		{"invalid token", http.MethodGet, "/api/v1/sync?since=garbage", "", http.StatusBadRequest},
+		{"mutations invalid json", http.MethodPost, "/api/v1/sync", `{bad`, http.StatusBadRequest},
+		{"mutations empty", http.MethodPost, "/api/v1/sync", `{"mutations":[]}`, http.StatusBadRequest},
+		{"mutation failure is per item", http.MethodPost, "/api/v1/sync", `{"mutations":[{"op":"archive"}]}`, http.StatusOK},
+		{"wrong method", http.MethodDelete, "/api/v1/sync", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), stubChangeRepo{})
+			h := handler.NewSyncHandler(svc)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSyncHandler_ChangesResponse(t *testing.T) {
+	h := handler.NewSyncHandler(service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), stubChangeRepo{}))
+
+	req := withUserID(httptest.NewRequest(http.MethodGet, "/api/v1/sync", nil), "user-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var body model.SyncResult
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.NextToken == "" || body.HasMore || body.Todos == nil || body.Deleted == nil {
+		t.Errorf("unexpected response: %+v", body)
+	}
+}
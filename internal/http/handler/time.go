@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// TimeHandler handles time tracking requests.
+type TimeHandler struct {
+	svc *service.TimeService
+}
+
+// NewTimeHandler creates a new TimeHandler.
+func NewTimeHandler(svc *service.TimeService) *TimeHandler {
+	return &TimeHandler{svc: svc}
+}
+
+// ServeHTTP routes /api/v1/time/* requests.
+func (h *TimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/time/")
+	path = strings.TrimRight(path, "/")
+
+	switch {
+	case path == "timer" && r.Method == http.MethodGet:
+		h.handleGetTimer(w, r)
+	case path == "timer/start" && r.Method == http.MethodPost:
+		h.handleStartTimer(w, r)
+	case path == "timer/stop" && r.Method == http.MethodPost:
+		h.handleStopTimer(w, r)
+	case path == "entries" && r.Method == http.MethodGet:
+		h.handleListEntries(w, r)
+	case path == "entries" && r.Method == http.MethodPost:
+		h.handleAddEntry(w, r)
+	case strings.HasPrefix(path, "entries/") && r.Method == http.MethodDelete:
+		h.handleDeleteEntry(w, r, strings.TrimPrefix(path, "entries/"))
+	case path == "report" && r.Method == http.MethodGet:
+		h.handleReport(w, r)
+	case path == "timer", path == "timer/start", path == "timer/stop",
+		path == "entries", strings.HasPrefix(path, "entries/"), path == "report":
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+	default:
+		WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "endpoint not found")
+	}
+}
+
+type startTimerRequest struct {
+	TodoID string `json:"todo_id"`
+	Note   string `json:"note"`
+}
+
+func (h *TimeHandler) handleStartTimer(w http.ResponseWriter, r *http.Request) {
+	var req startTimerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validIDs(w, r, validate.UUID("todo_id", req.TodoID)) {
+		return
+	}
+
+	entry, err := h.svc.StartTimer(r.Context(), getUserID(r), service.StartTimerInput{
+		TodoID: req.TodoID,
+		Note:   req.Note,
+	})
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusCreated, entry)
+}
+
+func (h *TimeHandler) handleStopTimer(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.svc.StopTimer(r.Context(), getUserID(r))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, entry)
+}
+
+func (h *TimeHandler) handleGetTimer(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.svc.RunningTimer(r.Context(), getUserID(r))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, entry)
+}
+
+type addTimeEntryRequest struct {
+	TodoID    string `json:"todo_id"`
+	Note      string `json:"note"`
+	StartedAt string `json:"started_at"`
+	EndedAt   string `json:"ended_at"`
+}
+
+func (h *TimeHandler) handleAddEntry(w http.ResponseWriter, r *http.Request) {
+	var req addTimeEntryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if !validIDs(w, r, validate.UUID("todo_id", req.TodoID)) {
+		return
+	}
+
+	entry, err := h.svc.AddEntry(r.Context(), getUserID(r), service.AddTimeEntryInput{
+		TodoID:    req.TodoID,
+		Note:      req.Note,
+		StartedAt: req.StartedAt,
+		EndedAt:   req.EndedAt,
+	})
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusCreated, entry)
+}
+
+func (h *TimeHandler) handleListEntries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !validIDs(w, r, validate.UUID("todo_id", q.Get("todo_id"))) {
+		return
+	}
+	entries, err := h.svc.ListEntries(r.Context(), getUserID(r), q.Get("todo_id"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, map[string]any{"entries": entries})
+}
+
+func (h *TimeHandler) handleDeleteEntry(w http.ResponseWriter, r *http.Request, entryID string) {
+	if !validIDs(w, r, validate.UUID("id", entryID)) {
+		return
+	}
+	if err := h.svc.DeleteEntry(r.Context(), getUserID(r), entryID); err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TimeHandler) handleReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	report, err := h.svc.Report(r.Context(), getUserID(r), q.Get("from"), q.Get("to"), q.Get("tz"))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, report)
+}
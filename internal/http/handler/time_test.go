@@ -0,0 +1,46 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+func TestTimeHandler_Routing(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"start timer invalid json", http.MethodPost, "/api/v1/time/timer/start", `{bad`, http.StatusBadRequest},
+		{"start timer missing todo", http.MethodPost, "/api/v1/time/timer/start", `{}`, http.StatusBadRequest},
+		{"add entry invalid dates", http.MethodPost, "/api/v1/time/entries", `{"todo_id":"` + testTodoID + `","started_at":"x","ended_at":"y"}`, http.StatusBadRequest},
+		{"report invalid range", http.MethodGet, "/api/v1/time/report?from=nope", "", http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/api/v1/time/timer/start", "", http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodGet, "/api/v1/time/unknown", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewTimeService(nil, &mockTodoRepo{}, 8*time.Hour)
+			h := handler.NewTimeHandler(svc)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
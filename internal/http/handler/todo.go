@@ -1,15 +1,19 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/jaekwang-park/todo-api/internal/middleware"
 	"github.com/jaekwang-park/todo-api/internal/model"
 	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
 )
 
 type TodoHandler struct {
@@ -39,6 +43,12 @@ func (h *TodoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// /api/v1/todos/{id}/blockers and /api/v1/todos/{id}/blockers/{blockerID}
+	if todoID != "" && (subPath == "blockers" || strings.HasPrefix(subPath, "blockers/")) {
+		h.handleBlockers(w, r, todoID, strings.TrimPrefix(strings.TrimPrefix(subPath, "blockers"), "/"))
+		return
+	}
+
 	// /api/v1/todos/{id}
 	if todoID != "" {
 		switch r.Method {
@@ -46,10 +56,12 @@ func (h *TodoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			h.handleGetByID(w, r, todoID)
 		case http.MethodPut:
 			h.handleUpdate(w, r, todoID)
+		case http.MethodPatch:
+			h.handlePatch(w, r, todoID)
 		case http.MethodDelete:
 			h.handleDelete(w, r, todoID)
 		default:
-			WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+			WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
 		}
 		return
 	}
@@ -61,87 +73,113 @@ func (h *TodoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		h.handleCreate(w, r)
 	default:
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
 	}
 }
 
 type createTodoRequest struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	DueAt       *string `json:"due_at,omitempty"`
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	DueAt           *string `json:"due_at,omitempty"`
+	Priority        string  `json:"priority,omitempty"`
+	Important       bool    `json:"important,omitempty"`
+	Urgent          bool    `json:"urgent,omitempty"`
+	EstimateMinutes *int    `json:"estimate_minutes,omitempty"`
 }
 
 func (h *TodoHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
 	var req createTodoRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	input := service.CreateTodoInput{
-		Title:       req.Title,
-		Description: req.Description,
-		DueAt:       req.DueAt,
+		Title:           req.Title,
+		Description:     req.Description,
+		DueAt:           req.DueAt,
+		Priority:        req.Priority,
+		Important:       req.Important,
+		Urgent:          req.Urgent,
+		EstimateMinutes: req.EstimateMinutes,
 	}
 
 	todo, err := h.svc.Create(r.Context(), userID, input)
 	if err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusCreated, todo)
+	WriteJSON(w, r, http.StatusCreated, todo)
 }
 
 func (h *TodoHandler) handleGetByID(w http.ResponseWriter, r *http.Request, todoID string) {
+	if !validIDs(w, r, validate.UUID("id", todoID)) {
+		return
+	}
+
 	userID := getUserID(r)
 
 	todo, err := h.svc.GetByID(r.Context(), userID, todoID)
 	if err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, todo)
+	WriteJSON(w, r, http.StatusOK, todo)
 }
 
 type updateTodoRequest struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
-	DueAt       *string `json:"due_at,omitempty"`
+	Title           *string `json:"title,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	DueAt           *string `json:"due_at,omitempty"`
+	Priority        *string `json:"priority,omitempty"`
+	Important       *bool   `json:"important,omitempty"`
+	Urgent          *bool   `json:"urgent,omitempty"`
+	EstimateMinutes *int    `json:"estimate_minutes,omitempty"`
 }
 
 func (h *TodoHandler) handleUpdate(w http.ResponseWriter, r *http.Request, todoID string) {
+	if !validIDs(w, r, validate.UUID("id", todoID)) {
+		return
+	}
+
 	userID := getUserID(r)
 
 	var req updateTodoRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	input := service.UpdateTodoInput{
-		Title:       req.Title,
-		Description: req.Description,
-		DueAt:       req.DueAt,
+		Title:           req.Title,
+		Description:     req.Description,
+		DueAt:           req.DueAt,
+		Priority:        req.Priority,
+		Important:       req.Important,
+		Urgent:          req.Urgent,
+		EstimateMinutes: req.EstimateMinutes,
 	}
 
 	todo, err := h.svc.Update(r.Context(), userID, todoID, input)
 	if err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, todo)
+	WriteJSON(w, r, http.StatusOK, todo)
 }
 
 func (h *TodoHandler) handleDelete(w http.ResponseWriter, r *http.Request, todoID string) {
+	if !validIDs(w, r, validate.UUID("id", todoID)) {
+		return
+	}
+
 	userID := getUserID(r)
 
 	if err := h.svc.Delete(r.Context(), userID, todoID); err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
@@ -150,29 +188,113 @@ func (h *TodoHandler) handleDelete(w http.ResponseWriter, r *http.Request, todoI
 
 type updateStatusRequest struct {
 	Status string `json:"status"`
+	Force  bool   `json:"force,omitempty"`
+}
+
+// Patch document media types accepted by PATCH /api/v1/todos/{id}.
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// handlePatch applies a JSON Merge Patch or a JSON Patch, chosen by the
+// Content-Type. Unlike PUT, a patch can clear due_at and estimate_minutes.
+func (h *TodoHandler) handlePatch(w http.ResponseWriter, r *http.Request, todoID string) {
+	if !validIDs(w, r, validate.UUID("id", todoID)) {
+		return
+	}
+
+	userID := getUserID(r)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergePatchContentType && mediaType != jsonPatchContentType {
+		w.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+		WriteError(w, r, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "unsupported media type")
+		return
+	}
+
+	patch, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var todo model.Todo
+	var err error
+	if mediaType == mergePatchContentType {
+		todo, err = h.svc.MergePatch(r.Context(), userID, todoID, patch)
+	} else {
+		todo, err = h.svc.JSONPatch(r.Context(), userID, todoID, patch)
+	}
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, todo)
 }
 
 func (h *TodoHandler) handleUpdateStatus(w http.ResponseWriter, r *http.Request, todoID string) {
 	if r.Method != http.MethodPatch {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !validIDs(w, r, validate.UUID("id", todoID)) {
 		return
 	}
 
 	userID := getUserID(r)
 
 	var req updateStatusRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	todo, err := h.svc.UpdateStatus(r.Context(), userID, todoID, model.TodoStatus(req.Status))
+	todo, err := h.svc.UpdateStatus(r.Context(), userID, todoID, model.TodoStatus(req.Status), req.Force)
 	if err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, todo)
+	WriteJSON(w, r, http.StatusOK, todo)
+}
+
+type addBlockerRequest struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+func (h *TodoHandler) handleBlockers(w http.ResponseWriter, r *http.Request, todoID, blockerID string) {
+	userID := getUserID(r)
+
+	switch {
+	case blockerID == "" && r.Method == http.MethodPost:
+		var req addBlockerRequest
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+		if !validIDs(w, r, validate.UUID("id", todoID), validate.UUID("blocker_id", req.BlockerID)) {
+			return
+		}
+
+		todo, err := h.svc.AddBlocker(r.Context(), userID, todoID, req.BlockerID)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+
+		WriteJSON(w, r, http.StatusCreated, todo)
+	case blockerID != "" && r.Method == http.MethodDelete:
+		if !validIDs(w, r, validate.UUID("id", todoID), validate.UUID("blocker_id", blockerID)) {
+			return
+		}
+		if err := h.svc.RemoveBlocker(r.Context(), userID, todoID, blockerID); err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+	}
 }
 
 func (h *TodoHandler) handleList(w http.ResponseWriter, r *http.Request) {
@@ -182,46 +304,98 @@ func (h *TodoHandler) handleList(w http.ResponseWriter, r *http.Request) {
 		UserID: userID,
 		Cursor: r.URL.Query().Get("cursor"),
 	}
+	if !validIDs(w, r, validate.UUID("cursor", params.Cursor)) {
+		return
+	}
 
 	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
 		status := model.TodoStatus(statusStr)
 		if !status.IsValid() {
-			WriteError(w, http.StatusBadRequest, "INVALID_STATUS", "status must be 'pending' or 'completed'")
+			WriteError(w, r, http.StatusBadRequest, "INVALID_STATUS", "status must be 'pending' or 'completed'")
 			return
 		}
 		params.Status = &status
 	}
 
-	limit := 20
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
+	if priorityStr := r.URL.Query().Get("priority"); priorityStr != "" {
+		priority := model.TodoPriority(priorityStr)
+		if !priority.IsValid() {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_PRIORITY", "priority must be one of none, low, medium, high, urgent")
+			return
 		}
+		params.Priority = &priority
+	}
+
+	if sortStr := r.URL.Query().Get("sort"); sortStr != "" {
+		sort := model.TodoSort(sortStr)
+		if !sort.IsValid() {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_SORT", "sort must be 'created_at' or 'priority'")
+			return
+		}
+		params.Sort = sort
+	}
+
+	if blockedStr := r.URL.Query().Get("blocked"); blockedStr != "" {
+		blocked, err := strconv.ParseBool(blockedStr)
+		if err != nil {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_BLOCKED", "blocked must be 'true' or 'false'")
+			return
+		}
+		params.Blocked = &blocked
+	}
+
+	params.Limit = parseLimit(r)
+
+	var ok bool
+	if params.Fields, params.Expand, ok = parseListShape(w, r); !ok {
+		return
 	}
-	params.Limit = limit
 
 	result, err := h.svc.List(r.Context(), params)
 	if err != nil {
-		handleServiceError(w, err)
+		handleServiceError(w, r, err)
 		return
 	}
 
-	WriteJSON(w, http.StatusOK, result)
+	page, err := projectPage(result, params.Fields)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	WriteJSON(w, r, http.StatusOK, page)
+}
+
+// parseLimit reads the limit query parameter, falling back to 20 when absent or out of range.
+func parseLimit(r *http.Request) int {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	return limit
 }
 
 func getUserID(r *http.Request) string {
 	return middleware.GetUserID(r)
 }
 
-func handleServiceError(w http.ResponseWriter, err error) {
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var fields validate.Errors
 	switch {
+	case errors.As(err, &fields):
+		WriteFieldErrors(w, r, fields)
 	case errors.Is(err, service.ErrNotFound):
-		WriteError(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
 	case errors.Is(err, service.ErrInvalidInput):
-		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		WriteError(w, r, http.StatusBadRequest, "INVALID_INPUT", err.Error())
 	case errors.Is(err, service.ErrForbidden):
-		WriteError(w, http.StatusForbidden, "FORBIDDEN", "access denied")
+		WriteError(w, r, http.StatusForbidden, "FORBIDDEN", "access denied")
+	case errors.Is(err, service.ErrConflict):
+		WriteError(w, r, http.StatusConflict, "CONFLICT", err.Error())
 	default:
-		WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		slog.ErrorContext(r.Context(), "internal error", "error", err)
+		trace.SpanFromContext(r.Context()).RecordError(err)
+		WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
 	}
 }
@@ -24,6 +24,7 @@ type mockTodoRepo struct {
 	updateFn  func(ctx context.Context, todo model.Todo) (model.Todo, error)
 	deleteFn  func(ctx context.Context, userID, todoID string) error
 	listFn    func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error)
+	listIDsFn func(ctx context.Context, userID string, ids []string) ([]model.Todo, error)
 }
 
 func (m *mockTodoRepo) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
@@ -35,18 +36,32 @@ func (m *mockTodoRepo) GetByID(ctx context.Context, userID, todoID string) (mode
 func (m *mockTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
 	return m.updateFn(ctx, todo)
 }
+func (m *mockTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	return m.updateFn(ctx, todo)
+}
 func (m *mockTodoRepo) Delete(ctx context.Context, userID, todoID string) error {
 	return m.deleteFn(ctx, userID, todoID)
 }
+func (m *mockTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	return m.deleteFn(ctx, userID, todoID)
+}
 func (m *mockTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
 	return m.listFn(ctx, params)
 }
+func (m *mockTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	return m.listIDsFn(ctx, userID, ids)
+}
 
 var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
+const (
+	testTodoID    = "6f1c2a4e-3b5d-4e7f-8a9b-0c1d2e3f4a5b"
+	testBlockerID = "9a8b7c6d-5e4f-4a3b-9c2d-1e0f9a8b7c6d"
+)
+
 func sampleTodo() model.Todo {
 	return model.Todo{
-		ID:          "todo-1",
+		ID:          testTodoID,
 		UserID:      "user-1",
 		Title:       "Buy groceries",
 		Description: "Milk, eggs, bread",
@@ -143,7 +158,7 @@ func TestTodoHandler_GetByID(t *testing.T) {
 	}{
 		{
 			name:   "success",
-			todoID: "todo-1",
+			todoID: testTodoID,
 			repoFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
 				return sampleTodo(), nil
 			},
@@ -151,7 +166,7 @@ func TestTodoHandler_GetByID(t *testing.T) {
 		},
 		{
 			name:   "not found",
-			todoID: "nonexistent",
+			todoID: "0e0e0e0e-0e0e-4e0e-8e0e-0e0e0e0e0e0e",
 			repoFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
 				return model.Todo{}, fmt.Errorf("scan: %w", sql.ErrNoRows)
 			},
@@ -218,7 +233,7 @@ func TestTodoHandler_Update(t *testing.T) {
 			}
 			h := newTodoHandler(repo)
 
-			req := httptest.NewRequest(http.MethodPut, "/api/v1/todos/todo-1", bytes.NewBufferString(tt.body))
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/todos/"+testTodoID, bytes.NewBufferString(tt.body))
 			req = withUserID(req, "user-1")
 			w := httptest.NewRecorder()
 
@@ -231,6 +246,98 @@ func TestTodoHandler_Update(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		getFn       func(ctx context.Context, userID, todoID string) (model.Todo, error)
+		wantStatus  int
+		wantCode    string
+	}{
+		{
+			name:        "merge patch",
+			contentType: "application/merge-patch+json",
+			body:        `{"title":"Updated title","due_at":null}`,
+			getFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+				return sampleTodo(), nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "json patch",
+			contentType: "application/json-patch+json; charset=utf-8",
+			body:        `[{"op":"replace","path":"/title","value":"Updated title"}]`,
+			getFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+				return sampleTodo(), nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "failed test",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/title","value":"Something else"}]`,
+			getFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+				return sampleTodo(), nil
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   "CONFLICT",
+		},
+		{
+			name:        "plain json",
+			contentType: "application/json",
+			body:        `{"title":"Updated title"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantCode:    "UNSUPPORTED_MEDIA_TYPE",
+		},
+		{
+			name:        "not found",
+			contentType: "application/merge-patch+json",
+			body:        `{"title":"Updated title"}`,
+			getFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+				return model.Todo{}, fmt.Errorf("scan: %w", sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockTodoRepo{
+				getByIDFn: tt.getFn,
+				updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+					return todo, nil
+				},
+			}
+			h := newTodoHandler(repo)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/todos/"+testTodoID, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp middleware.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				if resp.Error.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, resp.Error.Code)
+				}
+			}
+			if tt.wantStatus == http.StatusUnsupportedMediaType && w.Header().Get("Accept-Patch") == "" {
+				t.Error("expected Accept-Patch on 415")
+			}
+		})
+	}
+}
+
 func TestTodoHandler_Delete(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -250,7 +357,7 @@ func TestTodoHandler_Delete(t *testing.T) {
 			}
 			h := newTodoHandler(repo)
 
-			req := httptest.NewRequest(http.MethodDelete, "/api/v1/todos/todo-1", nil)
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/todos/"+testTodoID, nil)
 			req = withUserID(req, "user-1")
 			w := httptest.NewRecorder()
 
@@ -313,7 +420,7 @@ func TestTodoHandler_UpdateStatus(t *testing.T) {
 			}
 			h := newTodoHandler(repo)
 
-			req := httptest.NewRequest(tt.method, "/api/v1/todos/todo-1/status", bytes.NewBufferString(tt.body))
+			req := httptest.NewRequest(tt.method, "/api/v1/todos/"+testTodoID+"/status", bytes.NewBufferString(tt.body))
 			req = withUserID(req, "user-1")
 			w := httptest.NewRecorder()
 
@@ -358,11 +465,54 @@ func TestTodoHandler_List(t *testing.T) {
 			listFn:     nil,
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:  "with priority filter and sort",
+			query: "?priority=high&sort=priority",
+			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+				if params.Priority == nil || *params.Priority != model.TodoPriorityHigh {
+					return model.TodoListResult{}, fmt.Errorf("expected priority filter high")
+				}
+				if params.Sort != model.TodoSortPriority {
+					return model.TodoListResult{}, fmt.Errorf("expected sort=priority")
+				}
+				return model.TodoListResult{Todos: []model.Todo{sampleTodo()}}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid priority filter",
+			query:      "?priority=critical",
+			listFn:     nil,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "with blocked filter",
+			query: "?blocked=true",
+			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+				if params.Blocked == nil || !*params.Blocked {
+					return model.TodoListResult{}, fmt.Errorf("expected blocked=true")
+				}
+				return model.TodoListResult{Todos: []model.Todo{sampleTodo()}}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid blocked filter",
+			query:      "?blocked=maybe",
+			listFn:     nil,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid sort",
+			query:      "?sort=title",
+			listFn:     nil,
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:  "with cursor and limit",
-			query: "?cursor=abc&limit=10",
+			query: "?cursor=" + testTodoID + "&limit=10",
 			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
-				if params.Cursor != "abc" || params.Limit != 10 {
+				if params.Cursor != testTodoID || params.Limit != 10 {
 					return model.TodoListResult{}, fmt.Errorf("expected cursor=abc, limit=10")
 				}
 				return model.TodoListResult{Todos: []model.Todo{}, NextCursor: "def"}, nil
@@ -391,6 +541,59 @@ func TestTodoHandler_List(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_Blockers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "add without dependency tracking",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos/" + testTodoID + "/blockers",
+			body:       `{"blocker_id":"` + testBlockerID + `"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "add with invalid json",
+			method:     http.MethodPost,
+			path:       "/api/v1/todos/" + testTodoID + "/blockers",
+			body:       `{bad`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "delete requires blocker id",
+			method:     http.MethodDelete,
+			path:       "/api/v1/todos/" + testTodoID + "/blockers",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "get not allowed",
+			method:     http.MethodGet,
+			path:       "/api/v1/todos/" + testTodoID + "/blockers/" + testBlockerID,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTodoHandler(&mockTodoRepo{})
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestTodoHandler_MethodNotAllowed(t *testing.T) {
 	repo := &mockTodoRepo{}
 	h := newTodoHandler(repo)
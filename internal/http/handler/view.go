@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// ViewHandler serves aggregated, read-only views over a user's todos.
+type ViewHandler struct {
+	svc *service.TodoService
+}
+
+// NewViewHandler creates a new ViewHandler.
+func NewViewHandler(svc *service.TodoService) *ViewHandler {
+	return &ViewHandler{svc: svc}
+}
+
+// ServeHTTP routes /api/v1/views/* requests.
+func (h *ViewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/views/")
+	path = strings.TrimRight(path, "/")
+
+	if r.Method != http.MethodGet {
+		WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+
+	switch path {
+	case "matrix":
+		h.handleMatrix(w, r)
+	case "dependencies":
+		h.handleDependencies(w, r)
+	default:
+		WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "endpoint not found")
+	}
+}
+
+// handleMatrix returns the Eisenhower quadrants in one response. Each quadrant
+// is paged with its own cursor query parameter, e.g. ?do_cursor=...&schedule_cursor=...
+func (h *ViewHandler) handleMatrix(w http.ResponseWriter, r *http.Request) {
+	params := service.MatrixParams{
+		UserID:  getUserID(r),
+		Cursors: make(map[model.EisenhowerQuadrant]string, len(model.Quadrants)),
+		Limit:   parseLimit(r),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status := model.TodoStatus(statusStr)
+		if !status.IsValid() {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_STATUS", "status must be 'pending' or 'completed'")
+			return
+		}
+		params.Status = &status
+	}
+
+	var cursorRules []*validate.FieldError
+	for _, q := range model.Quadrants {
+		if cursor := r.URL.Query().Get(string(q) + "_cursor"); cursor != "" {
+			params.Cursors[q] = cursor
+			cursorRules = append(cursorRules, validate.UUID(string(q)+"_cursor", cursor))
+		}
+	}
+	if !validIDs(w, r, cursorRules...) {
+		return
+	}
+
+	var ok bool
+	if params.Fields, params.Expand, ok = parseListShape(w, r); !ok {
+		return
+	}
+
+	result, err := h.svc.Matrix(r.Context(), params)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	if len(params.Fields) == 0 {
+		WriteJSON(w, r, http.StatusOK, result)
+		return
+	}
+
+	quadrants := map[string]model.TodoListResult{
+		string(model.QuadrantDo):        result.Do,
+		string(model.QuadrantSchedule):  result.Schedule,
+		string(model.QuadrantDelegate):  result.Delegate,
+		string(model.QuadrantEliminate): result.Eliminate,
+	}
+	projected := make(map[string]any, len(quadrants))
+	for q, page := range quadrants {
+		if projected[q], err = projectPage(page, params.Fields); err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+	}
+	WriteJSON(w, r, http.StatusOK, projected)
+}
+
+// handleDependencies returns the user's dependency graph in topological
+// order, or with ?todo_id= the part of it connected to that todo.
+func (h *ViewHandler) handleDependencies(w http.ResponseWriter, r *http.Request) {
+	todoID := r.URL.Query().Get("todo_id")
+	if !validIDs(w, r, validate.UUID("todo_id", todoID)) {
+		return
+	}
+
+	graph, err := h.svc.DependencyGraph(r.Context(), getUserID(r), todoID)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, graph)
+}
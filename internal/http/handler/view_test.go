@@ -0,0 +1,91 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+func TestViewHandler_Matrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		listFn     func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error)
+		wantStatus int
+	}{
+		{
+			name:   "success with per-quadrant cursor",
+			method: http.MethodGet,
+			path:   "/api/v1/views/matrix?do_cursor=" + testTodoID + "&status=pending",
+			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+				if *params.Important && *params.Urgent && params.Cursor != testTodoID {
+					return model.TodoListResult{}, fmt.Errorf("expected do cursor abc")
+				}
+				if params.Status == nil || *params.Status != model.TodoStatusPending {
+					return model.TodoListResult{}, fmt.Errorf("expected status filter pending")
+				}
+				return model.TodoListResult{Todos: []model.Todo{sampleTodo()}, NextCursor: "next"}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid status",
+			method:     http.MethodGet,
+			path:       "/api/v1/views/matrix?status=done",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			path:       "/api/v1/views/matrix",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "dependencies of an invalid todo ID",
+			method:     http.MethodGet,
+			path:       "/api/v1/views/dependencies?todo_id=not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown view",
+			method:     http.MethodGet,
+			path:       "/api/v1/views/kanban",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockTodoRepo{listFn: tt.listFn}
+			h := handler.NewViewHandler(service.NewTodoService(repo))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var result model.MatrixResult
+				if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				if result.Do.NextCursor != "next" || len(result.Eliminate.Todos) != 1 {
+					t.Errorf("expected every quadrant populated, got %+v", result)
+				}
+			}
+		})
+	}
+}
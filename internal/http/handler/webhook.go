@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// WebhookHandler handles webhook subscription requests.
+type WebhookHandler struct {
+	svc *service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// ServeHTTP routes /api/v1/webhooks, /api/v1/webhooks/{id},
+// /api/v1/webhooks/{id}/deliveries and
+// /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/webhooks")
+	path = strings.Trim(path, "/")
+
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	switch {
+	case len(parts) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			h.handleList(w, r)
+		case http.MethodPost:
+			h.handleCreate(w, r)
+		default:
+			WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		}
+	case len(parts) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			h.handleGetByID(w, r, parts[0])
+		case http.MethodPut:
+			h.handleUpdate(w, r, parts[0])
+		case http.MethodDelete:
+			h.handleDelete(w, r, parts[0])
+		default:
+			WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		}
+	case len(parts) == 2 && parts[1] == "deliveries":
+		if r.Method != http.MethodGet {
+			WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+			return
+		}
+		h.handleListDeliveries(w, r, parts[0])
+	case len(parts) == 4 && parts[1] == "deliveries" && parts[3] == "redeliver":
+		if r.Method != http.MethodPost {
+			WriteError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+			return
+		}
+		h.handleRedeliver(w, r, parts[0], parts[2])
+	default:
+		WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "endpoint not found")
+	}
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+func (h *WebhookHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.svc.Create(r.Context(), getUserID(r), service.CreateWebhookInput{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.svc.List(r.Context(), getUserID(r))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, map[string]any{"webhooks": webhooks})
+}
+
+func (h *WebhookHandler) handleGetByID(w http.ResponseWriter, r *http.Request, webhookID string) {
+	if !validIDs(w, r, validate.UUID("id", webhookID)) {
+		return
+	}
+
+	webhook, err := h.svc.GetByID(r.Context(), getUserID(r), webhookID)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, webhook)
+}
+
+type updateWebhookRequest struct {
+	URL        *string  `json:"url,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+func (h *WebhookHandler) handleUpdate(w http.ResponseWriter, r *http.Request, webhookID string) {
+	if !validIDs(w, r, validate.UUID("id", webhookID)) {
+		return
+	}
+
+	var req updateWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.svc.Update(r.Context(), getUserID(r), webhookID, service.UpdateWebhookInput{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	})
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) handleDelete(w http.ResponseWriter, r *http.Request, webhookID string) {
+	if !validIDs(w, r, validate.UUID("id", webhookID)) {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), getUserID(r), webhookID); err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) handleListDeliveries(w http.ResponseWriter, r *http.Request, webhookID string) {
+	if !validIDs(w, r, validate.UUID("id", webhookID)) {
+		return
+	}
+
+	deliveries, err := h.svc.ListDeliveries(r.Context(), getUserID(r), webhookID, parseLimit(r))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusOK, map[string]any{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) handleRedeliver(w http.ResponseWriter, r *http.Request, webhookID, deliveryID string) {
+	if !validIDs(w, r, validate.UUID("id", webhookID), validate.UUID("delivery_id", deliveryID)) {
+		return
+	}
+
+	delivery, err := h.svc.Redeliver(r.Context(), getUserID(r), webhookID, deliveryID)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, r, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,46 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+func TestWebhookHandler_Routing(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"create invalid json", http.MethodPost, "/api/v1/webhooks", `{bad`, http.StatusBadRequest},
+		{"create invalid url", http.MethodPost, "/api/v1/webhooks", `{"url":"ftp://example.com","event_types":["todo.created"]}`, http.StatusBadRequest},
+		{"create unknown event", http.MethodPost, "/api/v1/webhooks", `{"url":"https://example.com","event_types":["todo.archived"]}`, http.StatusBadRequest},
+		{"update invalid json", http.MethodPut, "/api/v1/webhooks/wh-1", `{bad`, http.StatusBadRequest},
+		{"collection wrong method", http.MethodDelete, "/api/v1/webhooks", "", http.StatusMethodNotAllowed},
+		{"deliveries wrong method", http.MethodPost, "/api/v1/webhooks/wh-1/deliveries", "", http.StatusMethodNotAllowed},
+		{"redeliver wrong method", http.MethodGet, "/api/v1/webhooks/wh-1/deliveries/dlv-1/redeliver", "", http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodGet, "/api/v1/webhooks/wh-1/unknown", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewWebhookHandler(service.NewWebhookService(nil, nil))
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req = withUserID(req, "user-1")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package http_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	"github.com/jaekwang-park/todo-api/internal/health"
+	todohttp "github.com/jaekwang-park/todo-api/internal/http"
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/openapi"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/stream"
+)
+
+const specTodoID = "0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11"
+
+// specTodoRepo returns complete todos so responses can be checked against
+// the OpenAPI document.
+type specTodoRepo struct{}
+
+func specTodo() model.Todo {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return model.Todo{
+		ID:        specTodoID,
+		UserID:    "6a0b7c8d-1e2f-4a3b-8c4d-5e6f7a8b9c0d",
+		Title:     "Buy milk",
+		Status:    model.TodoStatusPending,
+		Priority:  model.TodoPriorityNone,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (m *specTodoRepo) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	created := specTodo()
+	created.Title = todo.Title
+	return created, nil
+}
+func (m *specTodoRepo) GetByID(ctx context.Context, userID, todoID string) (model.Todo, error) {
+	if todoID != specTodoID {
+		return model.Todo{}, sql.ErrNoRows
+	}
+	return specTodo(), nil
+}
+func (m *specTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	return todo, nil
+}
+func (m *specTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	return todo, nil
+}
+func (m *specTodoRepo) Delete(ctx context.Context, userID, todoID string) error {
+	return nil
+}
+func (m *specTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	return nil
+}
+func (m *specTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+	return model.TodoListResult{Todos: []model.Todo{specTodo()}}, nil
+}
+func (m *specTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	return []model.Todo{specTodo()}, nil
+}
+
+func mustLoadSpec(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+	return doc
+}
+
+func mustGraphQL(t *testing.T) *graphql.Executor {
+	t.Helper()
+	exec, err := graphql.NewExecutor(newTestTodoSvc(), nil, graphql.DefaultLimits)
+	if err != nil {
+		t.Fatalf("failed to build GraphQL executor: %v", err)
+	}
+	return exec
+}
+
+// TestRouter_RoutesInOpenAPI fails when NewRouter registers a route that the
+// OpenAPI document does not describe. Prefix patterns such as
+// /api/v1/views/ must have at least one path under them.
+func TestRouter_RoutesInOpenAPI(t *testing.T) {
+	doc := mustLoadSpec(t)
+	router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc(),
+		todohttp.WithTimeService(service.NewTimeService(nil, &mockTodoRepo{}, time.Hour)),
+		todohttp.WithWebhookService(service.NewWebhookService(nil, nil)),
+		todohttp.WithEventStream(stream.NewBroker(stream.Config{})),
+		todohttp.WithSyncService(service.NewSyncService(newTestTodoSvc(), nil)),
+		todohttp.WithGraphQL(mustGraphQL(t)),
+		todohttp.WithMetricsHandler(http.NotFoundHandler()),
+		todohttp.WithHealthChecker(health.NewChecker(time.Second), false),
+	)
+
+	paths := doc.Paths()
+	for _, pattern := range todohttp.Patterns(router) {
+		t.Run(pattern, func(t *testing.T) {
+			for _, p := range paths {
+				if p == pattern || (strings.HasSuffix(pattern, "/") && strings.HasPrefix(p, pattern)) {
+					return
+				}
+			}
+			t.Errorf("route %s is registered in NewRouter but missing from internal/openapi/openapi.json", pattern)
+		})
+	}
+}
+
+func TestRouter_ResponsesMatchOpenAPI(t *testing.T) {
+	doc := mustLoadSpec(t)
+	router := todohttp.NewRouter(service.NewTodoService(&specTodoRepo{}), newTestAuthSvc(),
+		todohttp.WithHealthChecker(health.NewChecker(time.Second), true),
+	)
+
+	tests := []struct {
+		method   string
+		target   string
+		body     string
+		wantCode int
+	}{
+		{http.MethodGet, "/health", "", http.StatusOK},
+		{http.MethodGet, "/health/live", "", http.StatusOK},
+		{http.MethodGet, "/health/ready", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/openapi.json", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/todos", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/todos?status=done", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/todos?fields=title,due_at&expand=counts", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/todos?fields=secret", "", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/todos", `{"title":"Buy milk"}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/todos", `{"title":""}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/todos", `{"title":`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/todos/" + specTodoID, "", http.StatusOK},
+		{http.MethodGet, "/api/v1/todos/6a0b7c8d-1e2f-4a3b-8c4d-5e6f7a8b9c0d", "", http.StatusNotFound},
+		{http.MethodPut, "/api/v1/todos/" + specTodoID, `{"priority":"high"}`, http.StatusOK},
+		{http.MethodPatch, "/api/v1/todos/" + specTodoID + "/status", `{"status":"completed"}`, http.StatusOK},
+		{http.MethodDelete, "/api/v1/todos/" + specTodoID, "", http.StatusNoContent},
+		{http.MethodGet, "/api/v1/views/matrix", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/views/matrix?fields=title", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/auth/login", `{"email":"a@example.com","password":"secret"}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			req = httptest.NewRequest(tt.method, tt.target, nil)
+			if err := doc.ValidateResponse(req, w.Code, w.Header(), w.Body.Bytes()); err != nil {
+				t.Errorf("response does not match the OpenAPI document: %v", err)
+			}
+		})
+	}
+}
+
+func TestRouter_ProblemResponsesMatchOpenAPI(t *testing.T) {
+	doc := mustLoadSpec(t)
+	router := todohttp.NewRouter(service.NewTodoService(&specTodoRepo{}), newTestAuthSvc())
+
+	tests := []struct {
+		method   string
+		target   string
+		body     string
+		wantCode int
+	}{
+		{http.MethodPost, "/api/v1/todos", `{"title":"","due_at":"soon"}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/todos/not-a-uuid", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/todos/6a0b7c8d-1e2f-4a3b-8c4d-5e6f7a8b9c0d", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/auth/login", `{"email":"a@example.com","password":"secret"}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Accept", "application/problem+json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Fatalf("expected a problem document, got Content-Type %q", ct)
+			}
+			req = httptest.NewRequest(tt.method, tt.target, nil)
+			if err := doc.ValidateResponse(req, w.Code, w.Header(), w.Body.Bytes()); err != nil {
+				t.Errorf("response does not match the OpenAPI document: %v", err)
+			}
+		})
+	}
+}
@@ -2,26 +2,301 @@ package http
 
 import (
 	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
 
+	"github.com/jaekwang-park/todo-api/internal/graphql"
+	"github.com/jaekwang-park/todo-api/internal/health"
 	"github.com/jaekwang-park/todo-api/internal/http/handler"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/openapi"
 	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/stream"
 )
 
-func NewRouter(todoSvc *service.TodoService, authSvc *service.AuthService) http.Handler {
+// routerConfig holds the optional services whose routes are only registered
+// when the service is provided, and optional middleware.
+type routerConfig struct {
+	timeSvc     *service.TimeService
+	webhookSvc  *service.WebhookService
+	broker      *stream.Broker
+	syncSvc     *service.SyncService
+	graphql     *graphql.Executor
+	idempotency *middleware.Idempotency
+	rateLimiter *middleware.RateLimiter
+	metrics     http.Handler
+	health      *health.Checker
+	details     bool
+	validation  *openapi.Document
+	compress    bool
+	minSize     int
+	cors        *middleware.CORSConfig
+	security    *middleware.SecurityHeadersConfig
+}
+
+// RouterOption registers an optional feature's routes or middleware.
+type RouterOption func(*routerConfig)
+
+// WithTimeService registers the /api/v1/time/ time tracking routes.
+func WithTimeService(svc *service.TimeService) RouterOption {
+	return func(c *routerConfig) {
+		c.timeSvc = svc
+	}
+}
+
+// WithWebhookService registers the /api/v1/webhooks routes.
+func WithWebhookService(svc *service.WebhookService) RouterOption {
+	return func(c *routerConfig) {
+		c.webhookSvc = svc
+	}
+}
+
+// WithEventStream registers the /api/v1/events Server-Sent Events stream.
+func WithEventStream(broker *stream.Broker) RouterOption {
+	return func(c *routerConfig) {
+		c.broker = broker
+	}
+}
+
+// WithSyncService registers the /api/v1/sync delta sync routes.
+func WithSyncService(svc *service.SyncService) RouterOption {
+	return func(c *routerConfig) {
+		c.syncSvc = svc
+	}
+}
+
+// WithGraphQL registers the /api/v1/graphql endpoint.
+func WithGraphQL(exec *graphql.Executor) RouterOption {
+	return func(c *routerConfig) {
+		c.graphql = exec
+	}
+}
+
+// WithIdempotency honours Idempotency-Key headers on the routes the
+// middleware was configured for. It is applied by NewServer, after auth.
+func WithIdempotency(m *middleware.Idempotency) RouterOption {
+	return func(c *routerConfig) {
+		c.idempotency = m
+	}
+}
+
+// WithRateLimiter limits requests on the routes the limiter was configured
+// for. It is applied by NewServer, after auth.
+func WithRateLimiter(l *middleware.RateLimiter) RouterOption {
+	return func(c *routerConfig) {
+		c.rateLimiter = l
+	}
+}
+
+// WithMetricsHandler serves Prometheus metrics at /metrics. Leave it out
+// when metrics are served on a separate internal port.
+func WithMetricsHandler(h http.Handler) RouterOption {
+	return func(c *routerConfig) {
+		c.metrics = h
+	}
+}
+
+// WithRequestValidation rejects requests that do not match the OpenAPI
+// document. It is applied by NewServer, after auth.
+func WithRequestValidation(doc *openapi.Document) RouterOption {
+	return func(c *routerConfig) {
+		c.validation = doc
+	}
+}
+
+// WithCompression compresses response bodies of at least minSize bytes
+// for clients that accept gzip, zstd or brotli. It is applied by NewServer,
+// outside recovery.
+func WithCompression(minSize int) RouterOption {
+	return func(c *routerConfig) {
+		c.compress = true
+		c.minSize = minSize
+	}
+}
+
+// WithCORS lets browsers on the configured origins call the API and answers
+// their preflight requests. It is applied by NewServer, before auth.
+func WithCORS(cfg middleware.CORSConfig) RouterOption {
+	return func(c *routerConfig) {
+		c.cors = &cfg
+	}
+}
+
+// WithSecurityHeaders adds browser security headers to every response. It
+// is applied by NewServer, before auth.
+func WithSecurityHeaders(cfg middleware.SecurityHeadersConfig) RouterOption {
+	return func(c *routerConfig) {
+		c.security = &cfg
+	}
+}
+
+// WithHealthChecker serves readiness at /health/ready from checker, and
+// reports its draining flag at /health. With details, readiness responses
+// include each check's result.
+func WithHealthChecker(checker *health.Checker, details bool) RouterOption {
+	return func(c *routerConfig) {
+		c.health = checker
+		c.details = details
+	}
+}
+
+func NewRouter(todoSvc *service.TodoService, authSvc *service.AuthService, opts ...RouterOption) http.Handler {
+	var cfg routerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	mux := http.NewServeMux()
+	var patterns []string
+	handle := func(pattern string, h http.Handler) {
+		mux.Handle(pattern, h)
+		patterns = append(patterns, pattern)
+	}
+
+	// Health checks - intentionally outside /api/v1 for ALB health check compatibility.
+	// /health is the ALB target group's check: liveness plus draining, so a
+	// dependency outage does not pull every instance out of rotation.
+	live := handler.NewHealthHandler()
+	handle("/health/live", live)
+	if cfg.health != nil {
+		handle("/health", handler.NewDrainHandler(cfg.health))
+		handle("/health/ready", handler.NewReadinessHandler(cfg.health, cfg.details))
+	} else {
+		handle("/health", live)
+		handle("/health/ready", live)
+	}
 
-	// Health check - intentionally outside /api/v1 for ALB health check compatibility
-	health := handler.NewHealthHandler()
-	mux.Handle("/health", health)
+	// OpenAPI document
+	handle("/api/v1/openapi.json", handler.NewOpenAPIHandler(openapi.Spec()))
 
 	// Auth endpoints (no JWT middleware needed)
 	authHandler := handler.NewAuthHandler(authSvc)
-	mux.Handle("/api/v1/auth/", authHandler)
+	handle("/api/v1/auth/", authHandler)
 
 	// Todo CRUD API
 	todoHandler := handler.NewTodoHandler(todoSvc)
-	mux.Handle("/api/v1/todos", todoHandler)
-	mux.Handle("/api/v1/todos/", todoHandler)
+	handle("/api/v1/todos", todoHandler)
+	handle("/api/v1/todos/", todoHandler)
+
+	// Aggregated todo views
+	viewHandler := handler.NewViewHandler(todoSvc)
+	handle("/api/v1/views/", viewHandler)
+
+	// Time tracking
+	if cfg.timeSvc != nil {
+		timeHandler := handler.NewTimeHandler(cfg.timeSvc)
+		handle("/api/v1/time/", timeHandler)
+	}
+
+	// Outbound webhooks
+	if cfg.webhookSvc != nil {
+		webhookHandler := handler.NewWebhookHandler(cfg.webhookSvc)
+		handle("/api/v1/webhooks", webhookHandler)
+		handle("/api/v1/webhooks/", webhookHandler)
+	}
+
+	// Delta sync for offline clients
+	if cfg.syncSvc != nil {
+		handle("/api/v1/sync", handler.NewSyncHandler(cfg.syncSvc))
+	}
+
+	// GraphQL over the todo and user services
+	if cfg.graphql != nil {
+		handle("/api/v1/graphql", handler.NewGraphQLHandler(cfg.graphql))
+	}
+
+	// Live todo events
+	if cfg.broker != nil {
+		handle("/api/v1/events", handler.NewEventStreamHandler(cfg.broker, 0))
+	}
+
+	if cfg.metrics != nil {
+		handle("/metrics", cfg.metrics)
+	}
+
+	return &router{mux: mux, patterns: patterns, routes: make(map[string]bool)}
+}
+
+// maxRouteLabels bounds the route templates reported to metrics. The API
+// has far fewer; past the bound, new templates fall back to their prefix so
+// that paths made up by a client cannot grow the label set without limit.
+const maxRouteLabels = 100
+
+// router is the handler returned by NewRouter. It also names the route a
+// request is for, for metrics.
+type router struct {
+	mux *http.ServeMux
+	// patterns are the ServeMux patterns registered, in order.
+	patterns []string
+
+	mu     sync.Mutex
+	routes map[string]bool
+}
+
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+// route implements middleware.RouteFunc.
+func (rt *router) route(r *http.Request) (string, string) {
+	_, pattern := rt.mux.Handler(r)
+	route, fallback := routeTemplate(pattern, r.URL.Path)
+	if route == fallback {
+		return route, fallback
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if !rt.routes[route] {
+		if len(rt.routes) >= maxRouteLabels {
+			return fallback, fallback
+		}
+		rt.routes[route] = true
+	}
+	return route, fallback
+}
+
+var (
+	uuidSegment = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	wordSegment = regexp.MustCompile(`^[a-z][a-z-]{0,31}$`)
+)
+
+// routeTemplate turns a request path into a metrics label such as
+// /api/v1/todos/{id}/blockers. Most handlers here parse the rest of the path
+// after a ServeMux prefix pattern themselves, so the rest is templated by
+// shape: UUIDs become {id}, words are kept and anything else is {param}.
+// The fallback, for paths the handler turns out not to know, drops all but
+// the UUIDs so that made-up paths share one label.
+func routeTemplate(pattern, urlPath string) (route, fallback string) {
+	if pattern == "" || !strings.HasSuffix(pattern, "/") {
+		return pattern, pattern
+	}
+
+	prefix := strings.TrimSuffix(pattern, "/")
+	rest := strings.Trim(strings.TrimPrefix(path.Clean(urlPath), prefix), "/")
+	if rest == "" {
+		return prefix, prefix
+	}
+
+	segments := strings.Split(rest, "/")
+	onlyIDs := true
+	for i, s := range segments {
+		switch {
+		case uuidSegment.MatchString(s):
+			segments[i] = "{id}"
+		case wordSegment.MatchString(s):
+			onlyIDs = false
+		default:
+			segments[i] = "{param}"
+			onlyIDs = false
+		}
+	}
 
-	return mux
+	route = prefix + "/" + strings.Join(segments, "/")
+	if onlyIDs {
+		return route, route
+	}
+	return route, prefix + "/*"
 }
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/jaekwang-park/todo-api/internal/cognito"
+	"github.com/jaekwang-park/todo-api/internal/health"
 	todohttp "github.com/jaekwang-park/todo-api/internal/http"
 	"github.com/jaekwang-park/todo-api/internal/model"
 	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/stream"
 )
 
 // mockTodoRepo for router tests
@@ -26,12 +29,21 @@ func (m *mockTodoRepo) GetByID(ctx context.Context, userID, todoID string) (mode
 func (m *mockTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
 	return model.Todo{}, nil
 }
+func (m *mockTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	return model.Todo{}, nil
+}
 func (m *mockTodoRepo) Delete(ctx context.Context, userID, todoID string) error {
 	return nil
 }
+func (m *mockTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	return nil
+}
 func (m *mockTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
 	return model.TodoListResult{Todos: []model.Todo{}}, nil
 }
+func (m *mockTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	return nil, nil
+}
 
 // stubCognitoClient for router tests — all methods return errors (not exercised)
 type stubCognitoClient struct{}
@@ -93,6 +105,34 @@ func TestRouter_HealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestRouter_HealthProbes(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return fmt.Errorf("connection refused") })
+	router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc(), todohttp.WithHealthChecker(checker, false))
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{"/health/live", http.StatusOK},
+		{"/health/ready", http.StatusServiceUnavailable},
+		{"/health", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
 func TestRouter_TodoEndpointRegistered(t *testing.T) {
 	router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc())
 
@@ -137,3 +177,87 @@ func TestRouter_UnknownRoute(t *testing.T) {
 		t.Errorf("expected status 404, got %d", w.Code)
 	}
 }
+
+func TestRouter_TimeEndpointOptional(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []todohttp.RouterOption
+		wantStatus int
+	}{
+		{"not registered by default", nil, http.StatusNotFound},
+		{"registered with time service", []todohttp.RouterOption{
+			todohttp.WithTimeService(service.NewTimeService(nil, &mockTodoRepo{}, time.Hour)),
+		}, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc(), tt.opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/time/timer/start", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRouter_EventStreamOptional(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []todohttp.RouterOption
+		wantStatus int
+	}{
+		{"not registered by default", nil, http.StatusNotFound},
+		{"registered with broker", []todohttp.RouterOption{
+			todohttp.WithEventStream(stream.NewBroker(stream.Config{})),
+		}, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc(), tt.opts...)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/events", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRouter_GraphQLOptional(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []todohttp.RouterOption
+		wantStatus int
+	}{
+		{"not registered by default", nil, http.StatusNotFound},
+		{"registered with executor", []todohttp.RouterOption{
+			todohttp.WithGraphQL(mustGraphQL(t)),
+		}, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := todohttp.NewRouter(newTestTodoSvc(), newTestAuthSvc(), tt.opts...)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/graphql", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
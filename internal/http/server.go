@@ -7,6 +7,9 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 	"github.com/jaekwang-park/todo-api/internal/middleware"
 	"github.com/jaekwang-park/todo-api/internal/service"
 )
@@ -16,14 +19,43 @@ type Server struct {
 	logger     *slog.Logger
 }
 
-func NewServer(port string, logger *slog.Logger, todoSvc *service.TodoService, authSvc *service.AuthService, auth *middleware.Auth) *Server {
-	router := NewRouter(todoSvc, authSvc)
+func NewServer(port string, logger *slog.Logger, todoSvc *service.TodoService, authSvc *service.AuthService, auth *middleware.Auth, opts ...RouterOption) *Server {
+	var cfg routerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rt := NewRouter(todoSvc, authSvc, opts...).(*router)
+	var router http.Handler = rt
+	if cfg.validation != nil {
+		router = middleware.ValidateRequests(cfg.validation)(router)
+	}
+	if cfg.idempotency != nil {
+		router = cfg.idempotency.Middleware(router)
+	}
+	if cfg.rateLimiter != nil {
+		router = cfg.rateLimiter.Middleware(router)
+	}
+
+	router = auth.Middleware(router)
+	if cfg.cors != nil {
+		router = middleware.CORS(*cfg.cors)(router)
+	}
+	if cfg.security != nil {
+		router = middleware.SecurityHeaders(*cfg.security)(router)
+	}
+	router = middleware.Recovery(logger)(middleware.Logging(logger)(router))
+	if cfg.compress {
+		router = middleware.Compress(cfg.minSize)(router)
+	}
 
-	// Middleware chain: recovery -> logging -> auth -> router
-	chain := middleware.Recovery(logger)(
-		middleware.Logging(logger)(
-			auth.Middleware(router),
+	// Middleware chain: tracing -> request ID -> metrics -> [compression] -> recovery -> logging -> [security headers] -> [CORS] -> auth -> [rate limit] -> [idempotency] -> [validation] -> router
+	chain := otelhttp.NewHandler(
+		middleware.RequestID(
+			middleware.Metrics(rt.route)(router),
 		),
+		"http.server",
+		otelhttp.WithSpanNameFormatter(middleware.SpanName(rt.route)),
 	)
 
 	return &Server{
@@ -38,11 +70,35 @@ func NewServer(port string, logger *slog.Logger, todoSvc *service.TodoService, a
 	}
 }
 
+// NewMetricsServer serves only /metrics, for running metrics on an internal
+// port that is not exposed through the load balancer.
+func NewMetricsServer(port string, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:         fmt.Sprintf(":%s", port),
+			Handler:      mux,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
 func (s *Server) Start() error {
 	s.logger.Info("starting server", "addr", s.httpServer.Addr)
 	return s.httpServer.ListenAndServe()
 }
 
+// RegisterOnShutdown registers f to be called when Shutdown starts, so
+// long-lived streams can be told to finish.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down server")
 	return s.httpServer.Shutdown(ctx)
@@ -8,10 +8,17 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	todohttp "github.com/jaekwang-park/todo-api/internal/http"
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 	"github.com/jaekwang-park/todo-api/internal/middleware"
 )
 
@@ -126,3 +133,206 @@ func TestServer_AuthMiddlewareApplied(t *testing.T) {
 	defer cancel()
 	_ = srv.Shutdown(ctx)
 }
+
+func TestServer_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	port := freePort(t)
+	srv := todohttp.NewServer(port, logger, newTestTodoSvc(), newTestAuthSvc(), newDevAuth())
+
+	go func() {
+		if err := srv.Start(); err != nil && err != http.ErrServerClosed {
+			t.Errorf("unexpected server error: %v", err)
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+
+	addr := fmt.Sprintf("http://localhost:%s/health", port)
+	for i := 0; i < 50; i++ {
+		if resp, _ := http.Get(addr); resp != nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A caller's W3C traceparent makes our spans part of its trace.
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%s/api/v1/todos", port), nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	req.Header.Set("X-User-ID", "test-user")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+
+	server, ok := spans["GET /api/v1/todos"]
+	if !ok {
+		t.Fatalf("expected server span, got %v", spans)
+	}
+	if got := server.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("expected server span in caller's trace %s, got %s", traceID, got)
+	}
+	svc, ok := spans["TodoService.List"]
+	if !ok {
+		t.Fatalf("expected service span, got %v", spans)
+	}
+	if svc.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Error("expected service span to be a child of the server span")
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	port := freePort(t)
+	srv := todohttp.NewServer(port, logger, newTestTodoSvc(), newTestAuthSvc(), newDevAuth(),
+		todohttp.WithMetricsHandler(metrics.Handler()),
+	)
+
+	go func() {
+		if err := srv.Start(); err != nil && err != http.ErrServerClosed {
+			t.Errorf("unexpected server error: %v", err)
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+
+	base := fmt.Sprintf("http://localhost:%s", port)
+	for i := 0; i < 50; i++ {
+		if resp, _ := http.Get(base + "/health"); resp != nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, path := range []string{
+		"/api/v1/todos",
+		"/api/v1/todos/0b7e8f4a-3c1d-4e2f-9a6b-5c4d3e2f1a0b",
+		"/api/v1/views/made-up",
+		"/no/such/route",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, base+path, nil)
+		req.Header.Set("X-User-ID", "test-user")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// Scraping needs no credentials.
+	resp, err := http.Get(base + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		`route="/api/v1/todos"`,
+		`route="/api/v1/todos/{id}"`,
+		`route="/api/v1/views/*"`,
+		`route="unmatched"`,
+		"todo_api_http_request_duration_seconds_bucket",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected metrics to contain %s", want)
+		}
+	}
+	if strings.Contains(out, "0b7e8f4a-3c1d-4e2f-9a6b-5c4d3e2f1a0b") || strings.Contains(out, "/no/such/route") || strings.Contains(out, "made-up") {
+		t.Error("expected raw paths not to appear in labels")
+	}
+}
+
+func TestServer_CORSBeforeAuth(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	port := freePort(t)
+	srv := todohttp.NewServer(port, logger, newTestTodoSvc(), newTestAuthSvc(), newDevAuth(),
+		todohttp.WithCORS(middleware.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: time.Minute}),
+		todohttp.WithSecurityHeaders(middleware.SecurityHeadersConfig{}),
+	)
+
+	go func() {
+		if err := srv.Start(); err != nil && err != http.ErrServerClosed {
+			t.Errorf("unexpected server error: %v", err)
+		}
+	}()
+
+	addr := fmt.Sprintf("http://localhost:%s/health", port)
+	for i := 0; i < 50; i++ {
+		if resp, _ := http.Get(addr); resp != nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A preflight carries no credentials and must not be rejected by auth
+	// or by the todo handler's method check.
+	todosAddr := fmt.Sprintf("http://localhost:%s/api/v1/todos/0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11", port)
+	req, _ := http.NewRequest(http.MethodOptions, todosAddr, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204 for preflight, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin: got %q", got)
+	}
+	if got := resp.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options: got %q", got)
+	}
+
+	// Error responses from auth still carry CORS headers so the SPA can read them.
+	req, _ = http.NewRequest(http.MethodGet, todosAddr, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without auth, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin on 401: got %q", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
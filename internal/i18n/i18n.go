@@ -0,0 +1,118 @@
+// Package i18n holds the Korean and English text of error and system
+// messages and picks the language of each response.
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Supported lists the response languages, default first.
+var Supported = []language.Tag{language.English, language.Korean}
+
+var matcher = language.NewMatcher(Supported)
+
+// Negotiate picks the response language: the best match for the
+// Accept-Language header, then the user's stored preference, then English.
+func Negotiate(acceptLanguage, preferred string) language.Tag {
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+			if _, i, conf := matcher.Match(tags...); conf != language.No {
+				return Supported[i]
+			}
+		}
+	}
+	if tag, ok := Parse(preferred); ok {
+		return tag
+	}
+	return language.English
+}
+
+// Parse maps a language tag such as "ko", "ko-KR" or "en_US" to a supported
+// language. It reports false when s names none of them.
+func Parse(s string) (language.Tag, bool) {
+	if s == "" {
+		return language.Und, false
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return language.Und, false
+	}
+	if _, i, conf := matcher.Match(tag); conf != language.No {
+		return Supported[i], true
+	}
+	return language.Und, false
+}
+
+type text struct {
+	en string
+	ko string
+}
+
+// catalog is keyed by error code, or for system messages by a key in the
+// same style.
+var catalog = map[string]text{
+	// Generic request errors.
+	"INVALID_INPUT":          {"invalid input", "입력값이 올바르지 않습니다"},
+	"INVALID_JSON":           {"invalid request body", "요청 본문이 올바른 JSON이 아닙니다"},
+	"INVALID_BODY":           {"failed to read request body", "요청 본문을 읽지 못했습니다"},
+	"REQUEST_TOO_LARGE":      {"request body too large", "요청 본문이 너무 큽니다"},
+	"UNSUPPORTED_MEDIA_TYPE": {"unsupported media type", "지원하지 않는 미디어 타입입니다"},
+	"VALIDATION_FAILED":      {"request does not match the API description", "요청이 API 명세와 맞지 않습니다"},
+	"METHOD_NOT_ALLOWED":     {"method not allowed", "허용되지 않는 메서드입니다"},
+	"NOT_FOUND":              {"resource not found", "리소스를 찾을 수 없습니다"},
+	"FORBIDDEN":              {"access denied", "접근 권한이 없습니다"},
+	"CONFLICT":               {"the request conflicts with the current state", "현재 상태와 충돌하는 요청입니다"},
+	"UNAUTHORIZED":           {"authentication required", "인증이 필요합니다"},
+	"TOO_MANY_REQUESTS":      {"too many requests, please try again later", "요청이 너무 많습니다. 잠시 후 다시 시도해 주세요"},
+	"INTERNAL_ERROR":         {"internal server error", "서버 내부 오류가 발생했습니다"},
+
+	// Query parameters.
+	"INVALID_STATUS":   {"status must be 'pending' or 'completed'", "status는 'pending' 또는 'completed'여야 합니다"},
+	"INVALID_PRIORITY": {"priority must be one of none, low, medium, high, urgent", "priority는 none, low, medium, high, urgent 중 하나여야 합니다"},
+	"INVALID_SORT":     {"sort must be 'created_at' or 'priority'", "sort는 'created_at' 또는 'priority'여야 합니다"},
+	"INVALID_BLOCKED":  {"blocked must be 'true' or 'false'", "blocked는 'true' 또는 'false'여야 합니다"},
+
+	// Idempotency keys.
+	"INVALID_IDEMPOTENCY_KEY": {"invalid idempotency key", "멱등성 키가 올바르지 않습니다"},
+	"IDEMPOTENCY_KEY_REUSED":  {"idempotency key was used with a different request", "다른 요청에 이미 사용된 멱등성 키입니다"},
+	"REQUEST_IN_PROGRESS":     {"a request with this idempotency key is in progress", "같은 멱등성 키의 요청이 처리 중입니다"},
+
+	// Cognito.
+	"USER_ALREADY_EXISTS":     {"a user with this email already exists", "이미 가입된 이메일입니다"},
+	"USER_NOT_FOUND":          {"user not found", "사용자를 찾을 수 없습니다"},
+	"USER_NOT_CONFIRMED":      {"email address not confirmed", "이메일 인증이 완료되지 않았습니다"},
+	"INVALID_PASSWORD":        {"password does not meet requirements", "비밀번호가 요구 사항을 충족하지 않습니다"},
+	"INVALID_CODE":            {"invalid verification code", "인증 코드가 올바르지 않습니다"},
+	"CODE_EXPIRED":            {"verification code has expired", "인증 코드가 만료되었습니다"},
+	"NOT_AUTHORIZED":          {"incorrect email or password", "이메일 또는 비밀번호가 올바르지 않습니다"},
+	"LIMIT_EXCEEDED":          {"attempt limit exceeded, please try again later", "시도 횟수를 초과했습니다. 잠시 후 다시 시도해 주세요"},
+	"PASSWORD_RESET_REQUIRED": {"password reset is required", "비밀번호를 재설정해야 합니다"},
+	"INVALID_PARAMETER":       {"invalid request parameter", "요청 값이 올바르지 않습니다"},
+
+	// System messages.
+	"EMAIL_CONFIRMED":          {"email confirmed", "이메일 인증이 완료되었습니다"},
+	"CONFIRMATION_CODE_RESENT": {"confirmation code resent", "인증 코드를 다시 보냈습니다"},
+	"PASSWORD_RESET_CODE_SENT": {"password reset code sent", "비밀번호 재설정 코드를 보냈습니다"},
+	"PASSWORD_RESET_CONFIRMED": {"password reset confirmed", "비밀번호가 재설정되었습니다"},
+	"PASSWORD_CHANGED":         {"password changed", "비밀번호가 변경되었습니다"},
+	"SIGNED_OUT":               {"signed out", "로그아웃되었습니다"},
+}
+
+// Message returns the text for key in lang, falling back to English for
+// unsupported languages. It reports false when key is not in the catalog.
+func Message(lang language.Tag, key string) (string, bool) {
+	t, ok := catalog[key]
+	if !ok {
+		return "", false
+	}
+	if lang == language.Korean && t.ko != "" {
+		return t.ko, true
+	}
+	return t.en, true
+}
+
+// Translated reports whether key has text in every supported language.
+func Translated(key string) bool {
+	t, ok := catalog[key]
+	return ok && t.en != "" && t.ko != ""
+}
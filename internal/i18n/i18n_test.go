@@ -0,0 +1,71 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/jaekwang-park/todo-api/internal/i18n"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		preferred      string
+		want           language.Tag
+	}{
+		{"default", "", "", language.English},
+		{"header", "ko-KR,ko;q=0.9", "", language.Korean},
+		{"header wins over preference", "en-US", "ko", language.English},
+		{"quality order", "en;q=0.5, ko;q=0.8", "", language.Korean},
+		{"preference", "", "ko", language.Korean},
+		{"unsupported header falls back to preference", "fr-FR", "ko", language.Korean},
+		{"malformed header", "!!", "ko", language.Korean},
+		{"unsupported everything", "de", "fr", language.English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := i18n.Negotiate(tt.acceptLanguage, tt.preferred); got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %v, want %v", tt.acceptLanguage, tt.preferred, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   language.Tag
+		wantOK bool
+	}{
+		{"ko", language.Korean, true},
+		{"ko-KR", language.Korean, true},
+		{"en_US", language.English, true},
+		{"fr", language.Und, false},
+		{"", language.Und, false},
+		{"not a tag", language.Und, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := i18n.Parse(tt.in)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Parse(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	if msg, _ := i18n.Message(language.Korean, "NOT_FOUND"); msg != "리소스를 찾을 수 없습니다" {
+		t.Errorf("Korean NOT_FOUND: got %q", msg)
+	}
+	if msg, _ := i18n.Message(language.English, "NOT_FOUND"); msg != "resource not found" {
+		t.Errorf("English NOT_FOUND: got %q", msg)
+	}
+	if _, ok := i18n.Message(language.English, "NO_SUCH_CODE"); ok {
+		t.Error("expected unknown key to be reported")
+	}
+}
@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -16,10 +15,17 @@ import (
 // ErrUserNotFound is returned by UserResolver when no user matches the given Cognito sub.
 var ErrUserNotFound = errors.New("user not found")
 
-// UserResolver resolves a Cognito sub claim to a database user ID.
+// ResolvedUser is the database user behind a token.
+type ResolvedUser struct {
+	ID string
+	// Language is the user's preferred language for messages, or "".
+	Language string
+}
+
+// UserResolver resolves a Cognito sub claim to a database user.
 // Implementations must return ErrUserNotFound (or a wrapped form) when the user does not exist.
 type UserResolver interface {
-	ResolveUserID(ctx context.Context, cognitoSub string) (string, error)
+	ResolveUser(ctx context.Context, cognitoSub string) (ResolvedUser, error)
 }
 
 type AuthConfig struct {
@@ -46,11 +52,17 @@ func NewAuth(cfg AuthConfig) (*Auth, error) {
 	return &Auth{cfg: cfg}, nil
 }
 
+// isHealthPath reports whether cleanPath is /health, /health/live or
+// /health/ready, which load balancers and orchestrators probe.
+func isHealthPath(cleanPath string) bool {
+	return cleanPath == "/health" || strings.HasPrefix(cleanPath, "/health/")
+}
+
 func (a *Auth) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for health check and auth endpoints
+		// Skip auth for health check, metrics scrape, API document and auth endpoints
 		cleanPath := path.Clean(r.URL.Path)
-		if cleanPath == "/health" || strings.HasPrefix(cleanPath, "/api/v1/auth/") {
+		if isHealthPath(cleanPath) || cleanPath == "/metrics" || cleanPath == "/api/v1/openapi.json" || strings.HasPrefix(cleanPath, "/api/v1/auth/") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -65,26 +77,66 @@ func (a *Auth) Middleware(next http.Handler) http.Handler {
 }
 
 func (a *Auth) handleDevMode(w http.ResponseWriter, r *http.Request, next http.Handler) {
-	userID := r.Header.Get("X-User-ID")
-	if userID == "" {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "X-User-ID header required in dev mode")
+	ctx, err := a.AuthenticateDevMode(r.Context(), r.Header.Get("X-User-ID"))
+	if err != nil {
+		WriteError(w, r, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
 		return
 	}
 
-	ctx := SetUserID(r.Context(), userID)
+	shareUser(ctx)
 	next.ServeHTTP(w, r.WithContext(ctx))
 }
 
 func (a *Auth) handleJWT(w http.ResponseWriter, r *http.Request, next http.Handler) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authorization header required")
+	ctx, err := a.AuthenticateToken(r.Context(), r.Header.Get("Authorization"))
+	if err != nil {
+		var authErr *AuthError
+		if errors.As(err, &authErr) {
+			WriteError(w, r, http.StatusUnauthorized, "UNAUTHORIZED", authErr.Message)
+		} else {
+			slog.ErrorContext(r.Context(), "user resolution failed", "error", err)
+			WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+		}
 		return
 	}
 
+	shareUser(ctx)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// AuthError is a request without valid credentials.
+type AuthError struct {
+	Message string
+}
+
+func (e *AuthError) Error() string { return e.Message }
+
+// DevMode reports whether requests are authenticated with AuthenticateDevMode
+// rather than AuthenticateToken.
+func (a *Auth) DevMode() bool {
+	return a.cfg.DevMode
+}
+
+// AuthenticateDevMode returns ctx with userID, the X-User-ID header, as the
+// user. The error is an *AuthError when userID is empty.
+func (a *Auth) AuthenticateDevMode(ctx context.Context, userID string) (context.Context, error) {
+	if userID == "" {
+		return nil, &AuthError{Message: "X-User-ID header required in dev mode"}
+	}
+	return SetUserID(ctx, userID), nil
+}
+
+// AuthenticateToken verifies the Cognito access token in authHeader, an
+// Authorization header, and returns ctx with the user it belongs to. The
+// error is an *AuthError for missing or invalid credentials, and any other
+// error when the user could not be resolved.
+func (a *Auth) AuthenticateToken(ctx context.Context, authHeader string) (context.Context, error) {
+	if authHeader == "" {
+		return nil, &AuthError{Message: "authorization header required"}
+	}
+
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid authorization header format")
-		return
+		return nil, &AuthError{Message: "invalid authorization header format"}
 	}
 
 	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
@@ -107,46 +159,30 @@ func (a *Auth) handleJWT(w http.ResponseWriter, r *http.Request, next http.Handl
 	)
 
 	if err != nil || !token.Valid {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired token")
-		return
+		return nil, &AuthError{Message: "invalid or expired token"}
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid token claims")
-		return
+		return nil, &AuthError{Message: "invalid token claims"}
 	}
 
 	sub, ok := claims["sub"].(string)
 	if !ok || sub == "" {
-		writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "sub claim not found")
-		return
+		return nil, &AuthError{Message: "sub claim not found"}
 	}
 
-	userID, err := a.cfg.UserResolver.ResolveUserID(r.Context(), sub)
+	user, err := a.cfg.UserResolver.ResolveUser(ctx, sub)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
-			writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", "user not found")
-		} else {
-			slog.ErrorContext(r.Context(), "user resolution failed", "error", err)
-			writeAuthError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+			return nil, &AuthError{Message: "user not found"}
 		}
-		return
+		return nil, err
 	}
 
-	ctx := SetUserID(r.Context(), userID)
-	next.ServeHTTP(w, r.WithContext(ctx))
-}
-
-func writeAuthError(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"error": map[string]string{
-			"code":    code,
-			"message": message,
-		},
-	})
+	ctx = SetUserID(ctx, user.ID)
+	ctx = SetUserLanguage(ctx, user.Language)
+	return ctx, nil
 }
 
 // CognitoJWKSURL returns the JWKS URL for the given Cognito User Pool.
@@ -65,13 +65,14 @@ func generateKey(t *testing.T) *rsa.PrivateKey {
 
 type mockUserResolver struct {
 	userID     string
+	language   string
 	err        error
 	calledWith string
 }
 
-func (m *mockUserResolver) ResolveUserID(_ context.Context, cognitoSub string) (string, error) {
+func (m *mockUserResolver) ResolveUser(_ context.Context, cognitoSub string) (middleware.ResolvedUser, error) {
 	m.calledWith = cognitoSub
-	return m.userID, m.err
+	return middleware.ResolvedUser{ID: m.userID, Language: m.language}, m.err
 }
 
 func mustNewAuth(t *testing.T, cfg middleware.AuthConfig) *middleware.Auth {
@@ -153,6 +154,28 @@ func TestAuth_DevMode_SkipsHealthCheck(t *testing.T) {
 	}
 }
 
+func TestAuth_SkipsProbes(t *testing.T) {
+	resolver := &mockUserResolver{userID: "unused"}
+	auth := mustNewAuth(t, jwtAuthConfig("http://unused", resolver))
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, p := range []string{"/health", "/health/live", "/health/ready"} {
+		t.Run(p, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, p, nil)
+			w := httptest.NewRecorder()
+
+			auth.Middleware(inner).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected 200 for %s, got %d", p, w.Code)
+			}
+		})
+	}
+}
+
 func TestAuth_SkipsAuthEndpoints(t *testing.T) {
 	resolver := &mockUserResolver{userID: "unused"}
 
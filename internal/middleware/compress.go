@@ -0,0 +1,292 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressMinSize is the smallest response body worth compressing.
+// Below it the encoding overhead outweighs the saving.
+const DefaultCompressMinSize = 1024
+
+// encoder is the part of gzip.Writer, zstd.Encoder and brotli.Writer the
+// middleware uses.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+type encoding struct {
+	name string
+	pool *sync.Pool
+}
+
+func encoderPool(newEncoder func() encoder) *sync.Pool {
+	return &sync.Pool{New: func() any { return newEncoder() }}
+}
+
+// encodings lists the supported content codings in server preference order,
+// which breaks ties between equal Accept-Encoding weights.
+var encodings = []encoding{
+	{"zstd", encoderPool(func() encoder {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return enc
+	})},
+	{"br", encoderPool(func() encoder { return brotli.NewWriterLevel(nil, 4) })},
+	{"gzip", encoderPool(func() encoder { return gzip.NewWriter(nil) })},
+}
+
+// Compress compresses response bodies of at least minSize bytes with the
+// best coding the client accepts. Bodies are buffered until minSize is
+// reached or the handler flushes, so streams such as server-sent events are
+// compressed from their first flush. Responses that are already encoded,
+// partial or of a compressed media type pass through unchanged.
+//
+// It should run outside Recovery so that error responses written there are
+// compressed as well.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			enc, ok := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if !ok || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: enc, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the supported coding with the highest weight in an
+// Accept-Encoding header. "*" stands for every coding not listed.
+func negotiateEncoding(header string) (encoding, bool) {
+	if header == "" {
+		return encoding{}, false
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if !ok || !strings.EqualFold(k, "q") {
+				continue
+			}
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			} else {
+				q = 0
+			}
+		}
+		weights[name] = q
+	}
+
+	var best encoding
+	bestQ := 0.0
+	for _, enc := range encodings {
+		q, ok := weights[enc.name]
+		if !ok {
+			q = weights["*"]
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// incompressibleTypes are media types whose bodies are already compressed.
+var incompressibleTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/zstd":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/x-bzip2":          true,
+	"application/pdf":              true,
+	"font/woff":                    true,
+	"font/woff2":                   true,
+}
+
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	switch {
+	case mediaType == "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return false
+	}
+	return !incompressibleTypes[mediaType]
+}
+
+// compressWriter holds back the status and the first minSize bytes of the
+// body until it knows whether the response is worth compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding encoding
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code < http.StatusOK {
+		// Informational responses such as 103 Early Hints go out as is.
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = code
+	if !cw.eligible() {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		cw.buf = append(cw.buf, b...)
+		if len(cw.buf) < cw.minSize {
+			return len(b), nil
+		}
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// FlushError commits to compressing, as a flushing handler is streaming and
+// its final size is unknown, and pushes everything written so far to the
+// client.
+func (cw *compressWriter) FlushError() error {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if err := cw.start(true); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		if err := cw.enc.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressWriter) Flush() {
+	_ = cw.FlushError()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// eligible reports whether the response may be compressed, given what the
+// handler has set so far.
+func (cw *compressWriter) eligible() bool {
+	switch cw.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	if ct := h.Get("Content-Type"); ct != "" && !compressibleType(ct) {
+		return false
+	}
+	return true
+}
+
+// start writes the held-back status and body, compressed if compress is set
+// and the response is eligible.
+func (cw *compressWriter) start(compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		// Sniff now: once the body is compressed net/http would sniff the
+		// compressed bytes instead.
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+
+	if compress && cw.eligible() {
+		h.Set("Content-Encoding", cw.encoding.name)
+		h.Del("Content-Length")
+		cw.enc = cw.encoding.pool.Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends a response that never reached minSize uncompressed and
+// finishes the compressed stream otherwise.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if cw.status == 0 {
+			// Nothing was written; let net/http send its implicit 200.
+			return
+		}
+		_ = cw.start(false)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		cw.enc.Reset(nil)
+		cw.encoding.pool.Put(cw.enc)
+		cw.enc = nil
+	}
+}
@@ -0,0 +1,237 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+func decompress(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		r = gr
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("zstd: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "":
+		return string(body)
+	default:
+		t.Fatalf("unexpected encoding %q", encoding)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to decompress %s: %v", encoding, err)
+	}
+	return string(out)
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	body := strings.Repeat(`{"title":"Buy milk"},`, 100)
+	tests := []struct {
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"br;q=0, gzip;q=0.1", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.5, zstd;q=0", "br"},
+		{"GZIP", "gzip"},
+		{"gzip;q=0", ""},
+		{"compress", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			handler := middleware.Compress(middleware.DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", "2100")
+				io.WriteString(w, body)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding: got %q, want %q", got, tt.wantEncoding)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary: got %q", got)
+			}
+			if tt.wantEncoding != "" && w.Header().Get("Content-Length") != "" {
+				t.Error("Content-Length must be dropped from compressed responses")
+			}
+			if got := decompress(t, tt.wantEncoding, w.Body.Bytes()); got != body {
+				t.Errorf("body mismatch after decompression: got %d bytes", len(got))
+			}
+		})
+	}
+}
+
+func TestCompress_Skips(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+	tests := []struct {
+		name        string
+		method      string
+		status      int
+		contentType string
+		encoding    string
+		body        string
+	}{
+		{"below threshold", http.MethodGet, http.StatusOK, "application/json", "", `{"ok":true}`},
+		{"image", http.MethodGet, http.StatusOK, "image/png", "", large},
+		{"zip", http.MethodGet, http.StatusOK, "application/zip", "", large},
+		{"already encoded", http.MethodGet, http.StatusOK, "application/json", "identity", large},
+		{"partial content", http.MethodGet, http.StatusPartialContent, "text/plain", "", large},
+		{"no content", http.MethodDelete, http.StatusNoContent, "", "", ""},
+		{"head", http.MethodHead, http.StatusOK, "application/json", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.Compress(middleware.DefaultCompressMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip, zstd, br")
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status: got %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Content-Encoding: got %q, want %q", got, tt.encoding)
+			}
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("body: got %d bytes, want %d unchanged", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompress_SniffsContentType(t *testing.T) {
+	handler := middleware.Compress(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<!DOCTYPE html><html><body>"+strings.Repeat("x", 64)+"</body></html>")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type: got %q, want the type of the uncompressed body", got)
+	}
+}
+
+// TestCompress_FlushThroughChain streams through Recovery and Logging, whose
+// writers only expose the compressing writer through Unwrap, as the events
+// handler does with http.ResponseController.
+func TestCompress_FlushThroughChain(t *testing.T) {
+	flushed := make(chan struct{})
+	release := make(chan struct{})
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		rc := http.NewResponseController(w)
+		io.WriteString(w, "data: hello\n\n")
+		if err := rc.Flush(); err != nil {
+			t.Errorf("Flush through the chain: %v", err)
+		}
+		close(flushed)
+		<-release
+		io.WriteString(w, "data: bye\n\n")
+	})
+	logger, _ := newTestLogger()
+	handler := middleware.Compress(middleware.DefaultCompressMinSize)(
+		middleware.Recovery(logger)(middleware.Logging(logger)(inner)),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	<-flushed
+	if !w.Flushed {
+		t.Fatal("expected the underlying writer to be flushed")
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("a flushed stream below the threshold should still be compressed, got %q", got)
+	}
+	// The bytes flushed so far must decode to the first event on their own.
+	gr, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	first := make([]byte, len("data: hello\n\n"))
+	if _, err := io.ReadFull(gr, first); err != nil || string(first) != "data: hello\n\n" {
+		t.Fatalf("first event: got %q, %v", first, err)
+	}
+
+	close(release)
+	<-done
+	if got := decompress(t, "gzip", w.Body.Bytes()); got != "data: hello\n\ndata: bye\n\n" {
+		t.Errorf("stream: got %q", got)
+	}
+}
+
+func TestCompress_RecoveredPanic(t *testing.T) {
+	logger, _ := newTestLogger()
+	handler := middleware.Compress(0)(
+		middleware.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})),
+	)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	if got := decompress(t, w.Header().Get("Content-Encoding"), w.Body.Bytes()); !strings.Contains(got, "INTERNAL_ERROR") {
+		t.Errorf("body: got %q", got)
+	}
+}
@@ -3,17 +3,100 @@ package middleware
 import (
 	"context"
 	"net/http"
+
+	"golang.org/x/text/language"
+
+	"github.com/jaekwang-park/todo-api/internal/i18n"
 )
 
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey       contextKey = "user_id"
+	userLanguageKey contextKey = "user_language"
+	requestInfoKey  contextKey = "request_info"
+)
+
+// requestInfo identifies a request in logs. It is shared by pointer so that
+// the user set by Auth is also seen by middleware further out, such as
+// Logging, whose request context was created before Auth ran. Only
+// shareUser writes to it after RequestID creates it.
+type requestInfo struct {
+	requestID    string
+	traceID      string
+	userID       string
+	userLanguage string
+}
 
+// SetUserID returns a copy of ctx with userID as the authenticated user.
+// ctx itself is left unchanged.
 func SetUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
 
 func GetUserID(r *http.Request) string {
-	v, _ := r.Context().Value(userIDKey).(string)
-	return v
+	return UserIDFromContext(r.Context())
+}
+
+// UserIDFromContext returns the authenticated user ID, or "" if the request
+// was not authenticated.
+func UserIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(userIDKey).(string); ok {
+		return v
+	}
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.userID
+	}
+	return ""
+}
+
+// SetUserLanguage returns a copy of ctx with lang as the authenticated
+// user's preferred language.
+func SetUserLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, userLanguageKey, lang)
+}
+
+// shareUser copies the user set on ctx into the request's requestInfo, for
+// middleware further out. Auth calls it before passing the request on; no
+// one else reads requestInfo until then, so it must not be called once the
+// handler is running.
+func shareUser(ctx context.Context) {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		info.userID = UserIDFromContext(ctx)
+		info.userLanguage = UserLanguageFromContext(ctx)
+	}
+}
+
+// UserLanguageFromContext returns the authenticated user's preferred
+// language, or "" if there is none.
+func UserLanguageFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(userLanguageKey).(string); ok {
+		return v
+	}
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.userLanguage
+	}
+	return ""
+}
+
+// Language picks the language of the response to r from its
+// Accept-Language header and the user's preference.
+func Language(r *http.Request) language.Tag {
+	return i18n.Negotiate(r.Header.Get("Accept-Language"), UserLanguageFromContext(r.Context()))
+}
+
+// RequestIDFromContext returns the ID set by the RequestID middleware.
+func RequestIDFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.requestID
+	}
+	return ""
+}
+
+// TraceIDFromContext returns the load balancer's X-Amzn-Trace-Id, if any.
+func TraceIDFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.traceID
+	}
+	return ""
 }
@@ -1,6 +1,8 @@
 package middleware_test
 
 import (
+	"context"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -23,3 +25,45 @@ func TestSetAndGetUserID(t *testing.T) {
 		t.Errorf("expected user-abc, got %q", got)
 	}
 }
+
+func TestSetUserID_LeavesParentUnchanged(t *testing.T) {
+	var parent, child context.Context
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parent = r.Context()
+		child = middleware.SetUserID(parent, "user-abc")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if got := middleware.UserIDFromContext(child); got != "user-abc" {
+		t.Errorf("expected user-abc, got %q", got)
+	}
+	if got := middleware.UserIDFromContext(parent); got != "" {
+		t.Errorf("expected the request context to have no user, got %q", got)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		stored         string
+		want           string
+	}{
+		{"default", "", "", "en"},
+		{"stored preference", "", "ko", "ko"},
+		{"header overrides preference", "en", "ko", "en"},
+		{"header", "ko-KR", "", "ko"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			req = req.WithContext(middleware.SetUserLanguage(req.Context(), tt.stored))
+
+			if got := middleware.Language(req).String(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corsAllowedMethods and corsAllowedHeaders are what browsers may use on
+// cross-origin requests. X-User-ID only matters in dev mode.
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}, ", ")
+	corsAllowedHeaders = []string{
+		"Authorization", "Content-Type", "Accept-Language", "Last-Event-ID",
+		IdempotencyKeyHeader, RequestIDHeader, "X-User-ID",
+	}
+	corsExposedHeaders = strings.Join([]string{
+		RequestIDHeader, IdempotentReplayedHeader, "Content-Language", "Retry-After",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+	}, ", ")
+)
+
+type CORSConfig struct {
+	// AllowedOrigins are exact origins such as https://app.example.com,
+	// origins with a leading subdomain wildcard such as
+	// https://*.preview.example.com, or "*" for any origin.
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and read responses to
+	// credentialed requests. It cannot be combined with "*".
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// CORS answers preflight requests and adds Access-Control headers to
+// responses for allowed origins. It must run before auth, as browsers send
+// preflights without credentials.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	allowedHeaders := make(map[string]bool, len(corsAllowedHeaders))
+	for _, h := range corsAllowedHeaders {
+		allowedHeaders[strings.ToLower(h)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			allowed := origin != "" && originAllowed(cfg.AllowedOrigins, origin)
+			if allowed {
+				if cfg.AllowCredentials || !anyOrigin {
+					h.Set("Access-Control-Allow-Origin", origin)
+				} else {
+					h.Set("Access-Control-Allow-Origin", "*")
+				}
+				if cfg.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			if !preflight {
+				if allowed {
+					h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// A preflight never reaches the handlers. Without the allow
+			// headers below the browser blocks the actual request.
+			if allowed && requestHeadersAllowed(allowedHeaders, r.Header.Get("Access-Control-Request-Headers")) {
+				h.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				h.Set("Access-Control-Allow-Headers", strings.Join(corsAllowedHeaders, ", "))
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+		// https://*.example.com matches https://app.example.com but not
+		// https://example.com or https://evil-example.com.
+		if scheme, host, ok := strings.Cut(a, "://*."); ok {
+			prefix := scheme + "://"
+			if len(origin) > len(prefix) && strings.EqualFold(origin[:len(prefix)], prefix) &&
+				strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func requestHeadersAllowed(allowed map[string]bool, requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		if h = strings.TrimSpace(h); h != "" && !allowed[strings.ToLower(h)] {
+			return false
+		}
+	}
+	return true
+}
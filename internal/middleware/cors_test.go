@@ -0,0 +1,158 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+func TestCORS_Preflight(t *testing.T) {
+	cfg := middleware.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.preview.example.com"},
+		MaxAge:         10 * time.Minute,
+	}
+
+	tests := []struct {
+		name           string
+		origin         string
+		requestHeaders string
+		wantAllowed    bool
+	}{
+		{"allowed origin", "https://app.example.com", "authorization, content-type", true},
+		{"subdomain wildcard", "https://pr-12.preview.example.com", "Idempotency-Key", true},
+		{"wildcard needs a subdomain", "https://preview.example.com", "", false},
+		{"wildcard checks the suffix", "https://evil-preview.example.com", "", false},
+		{"scheme must match", "http://app.example.com", "", false},
+		{"unknown origin", "https://evil.example.com", "", false},
+		{"header not allowed", "https://app.example.com", "X-Forwarded-For", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := middleware.CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/todos", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			if tt.requestHeaders != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeaders)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if reached {
+				t.Error("preflight must not reach the next handler")
+			}
+			if w.Code != http.StatusNoContent {
+				t.Errorf("expected status 204, got %d", w.Code)
+			}
+			gotMethods := w.Header().Get("Access-Control-Allow-Methods")
+			if tt.wantAllowed {
+				if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.origin {
+					t.Errorf("Access-Control-Allow-Origin: got %q, want %q", got, tt.origin)
+				}
+				if gotMethods == "" {
+					t.Error("expected Access-Control-Allow-Methods")
+				}
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+					t.Errorf("Access-Control-Max-Age: got %q, want 600", got)
+				}
+			} else if gotMethods != "" {
+				t.Errorf("unexpected Access-Control-Allow-Methods %q", gotMethods)
+			}
+		})
+	}
+}
+
+func TestCORS_ActualRequest(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             middleware.CORSConfig
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{
+			name:       "allowed origin",
+			cfg:        middleware.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			origin:     "https://app.example.com",
+			wantOrigin: "https://app.example.com",
+		},
+		{
+			name:            "credentials echo the origin",
+			cfg:             middleware.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true},
+			origin:          "https://app.example.com",
+			wantOrigin:      "https://app.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:       "any origin",
+			cfg:        middleware.CORSConfig{AllowedOrigins: []string{"*"}},
+			origin:     "https://anywhere.example.org",
+			wantOrigin: "*",
+		},
+		{
+			name:   "unknown origin",
+			cfg:    middleware.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			origin: "https://evil.example.com",
+		},
+		{
+			name: "same-origin request",
+			cfg:  middleware.CORSConfig{AllowedOrigins: []string{"*"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.CORS(tt.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusTeapot {
+				t.Errorf("expected the next handler to answer, got %d", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin: got %q, want %q", got, tt.wantOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials: got %q, want %q", got, tt.wantCredentials)
+			}
+			exposed := w.Header().Get("Access-Control-Expose-Headers")
+			if (tt.wantOrigin != "") != (exposed != "") {
+				t.Errorf("Access-Control-Expose-Headers: got %q", exposed)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary: got %q, want Origin", got)
+			}
+		})
+	}
+}
+
+// TestCORS_PlainOptions leaves OPTIONS requests that are not preflights to
+// the handlers.
+func TestCORS_PlainOptions(t *testing.T) {
+	handler := middleware.CORS(middleware.CORSConfig{AllowedOrigins: []string{"*"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/todos", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize caps both the request body hashed and the
+	// response body stored. Larger responses are passed through unstored.
+	maxIdempotentBodySize = 1 << 20
+
+	// anonymousScope holds keys sent to unauthenticated endpoints. Replays
+	// still require an identical request body.
+	anonymousScope = "anonymous"
+)
+
+// IdempotencyStore persists idempotency keys and their responses.
+type IdempotencyStore interface {
+	Claim(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (model.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record model.IdempotencyRecord) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyConfig struct {
+	Store  IdempotencyStore
+	Logger *slog.Logger
+	// Routes lists the "METHOD /path" pairs that honour Idempotency-Key.
+	Routes []string
+	// TTL is how long a completed response is replayed. Defaults to 24h.
+	TTL time.Duration
+	// Lock is how long an unfinished request holds its key before a retry
+	// may take over, e.g. after a crash. Defaults to one minute.
+	Lock time.Duration
+}
+
+// Idempotency replays the stored response when a request is retried with
+// the same Idempotency-Key, so retries of non-idempotent requests run once.
+type Idempotency struct {
+	cfg    IdempotencyConfig
+	routes map[string]bool
+}
+
+func NewIdempotency(cfg IdempotencyConfig) (*Idempotency, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("middleware: Store is required")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.Lock <= 0 {
+		cfg.Lock = time.Minute
+	}
+
+	routes := make(map[string]bool, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes[route] = true
+	}
+	return &Idempotency{cfg: cfg, routes: routes}, nil
+}
+
+// Middleware must run after Auth so that keys are scoped to the user.
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || !i.routes[r.Method+" "+path.Clean(r.URL.Path)] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY",
+				fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			WriteError(w, r, http.StatusBadRequest, "INVALID_BODY", "failed to read request body")
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			WriteError(w, r, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := GetUserID(r)
+		if scope == "" {
+			scope = anonymousScope
+		}
+		hash := requestHash(r, body)
+
+		record, claimed, err := i.cfg.Store.Claim(r.Context(), scope, key, hash, i.cfg.Lock, i.cfg.TTL)
+		if err != nil {
+			i.cfg.Logger.Error("failed to claim idempotency key", "error", err)
+			WriteError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+			return
+		}
+
+		if !claimed {
+			switch {
+			case record.RequestHash != hash:
+				WriteError(w, r, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+					"Idempotency-Key was already used for a different request")
+			case !record.Completed():
+				WriteError(w, r, http.StatusConflict, "REQUEST_IN_PROGRESS",
+					"a request with this Idempotency-Key is still being processed")
+			default:
+				replay(w, record)
+			}
+			return
+		}
+
+		i.serveAndStore(w, r, next, record)
+	})
+}
+
+// serveAndStore runs the request and stores its response. Server errors and
+// panics release the key instead, so the client can retry them.
+func (i *Idempotency) serveAndStore(w http.ResponseWriter, r *http.Request, next http.Handler, record model.IdempotencyRecord) {
+	// Only the handler's own headers are stored. Those set by outer
+	// middleware, such as CORS and request IDs, belong to each request.
+	rec := &idempotencyRecorder{ResponseWriter: w, before: w.Header().Clone()}
+	// Store even if the client has gone away; its retry will want the result.
+	ctx := context.WithoutCancel(r.Context())
+
+	stored := false
+	defer func() {
+		if stored {
+			return
+		}
+		if err := i.cfg.Store.Release(ctx, record.Scope, record.Key); err != nil {
+			i.cfg.Logger.Error("failed to release idempotency key", "error", err)
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if rec.status >= http.StatusInternalServerError || rec.overflow {
+		return
+	}
+
+	record.StatusCode = rec.status
+	record.Header = rec.header
+	record.Body = rec.body.Bytes()
+	if err := i.cfg.Store.Complete(ctx, record); err != nil {
+		i.cfg.Logger.Error("failed to store idempotent response", "error", err)
+		return
+	}
+	stored = true
+}
+
+// RunCleanup deletes expired keys every interval until ctx is cancelled.
+func (i *Idempotency) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := i.cfg.Store.DeleteExpired(ctx)
+			if err != nil {
+				i.cfg.Logger.Error("failed to delete expired idempotency keys", "error", err)
+				continue
+			}
+			if n > 0 {
+				i.cfg.Logger.Info("deleted expired idempotency keys", "count", n)
+			}
+		}
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, path.Clean(r.URL.Path))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes the stored response. Headers already set for the retry, such
+// as its own request ID and CORS headers, are kept; the body keeps the
+// original's request ID.
+func replay(w http.ResponseWriter, record model.IdempotencyRecord) {
+	for name, values := range record.Header {
+		if _, ok := w.Header()[name]; !ok {
+			w.Header()[name] = values
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// idempotencyRecorder passes the response through while keeping a copy.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	// before holds the headers set before the handler ran; header holds
+	// only those the handler added or changed.
+	before   http.Header
+	header   http.Header
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *idempotencyRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+		r.header = http.Header{}
+		for name, values := range r.ResponseWriter.Header() {
+			if !slices.Equal(values, r.before[name]) {
+				r.header[name] = slices.Clone(values)
+			}
+		}
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.body.Len()+len(b) > maxIdempotentBodySize {
+		r.overflow = true
+	} else if !r.overflow {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
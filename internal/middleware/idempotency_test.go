@@ -0,0 +1,267 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type memIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]model.IdempotencyRecord
+}
+
+func newMemIdempotencyStore() *memIdempotencyStore {
+	return &memIdempotencyStore{records: make(map[string]model.IdempotencyRecord)}
+}
+
+func (s *memIdempotencyStore) Claim(ctx context.Context, scope, key, hash string, lock, ttl time.Duration) (model.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.records[scope+"/"+key]; ok {
+		return r, false, nil
+	}
+	r := model.IdempotencyRecord{Scope: scope, Key: key, RequestHash: hash}
+	s.records[scope+"/"+key] = r
+	return r, true, nil
+}
+
+func (s *memIdempotencyStore) Complete(ctx context.Context, r model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[r.Scope+"/"+r.Key] = r
+	return nil
+}
+
+func (s *memIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, scope+"/"+key)
+	return nil
+}
+
+func (s *memIdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// countingCreate simulates POST /api/v1/todos, numbering each todo it creates.
+func countingCreate(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"todo-%d"}`, n)
+	})
+}
+
+func newIdempotency(t *testing.T, store middleware.IdempotencyStore) *middleware.Idempotency {
+	t.Helper()
+	m, err := middleware.NewIdempotency(middleware.IdempotencyConfig{
+		Store:  store,
+		Routes: []string{"POST /api/v1/todos", "POST /api/v1/auth/signup"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func idempotentRequest(path, userID, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	if userID != "" {
+		req = req.WithContext(middleware.SetUserID(req.Context(), userID))
+	}
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotency(t, newMemIdempotencyStore()).Middleware(countingCreate(&calls))
+
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, idempotentRequest("/api/v1/todos", "user-1", "key-1", `{"title":"a"}`))
+
+	retry := httptest.NewRecorder()
+	h.ServeHTTP(retry, idempotentRequest("/api/v1/todos", "user-1", "key-1", `{"title":"a"}`))
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls.Load())
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("expected replay of %d %s, got %d %s", first.Code, first.Body, retry.Code, retry.Body)
+	}
+	if retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected stored headers replayed, got %v", retry.Header())
+	}
+	if retry.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Error("expected replay to be marked")
+	}
+	if first.Header().Get(middleware.IdempotentReplayedHeader) != "" {
+		t.Error("expected original response not to be marked as replayed")
+	}
+}
+
+func TestIdempotency_ReplayKeepsRetryHeaders(t *testing.T) {
+	var calls atomic.Int32
+	cors := middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins: []string{"https://a.example.com", "https://b.example.com"},
+	})
+	h := middleware.RequestID(cors(newIdempotency(t, newMemIdempotencyStore()).Middleware(countingCreate(&calls))))
+
+	send := func(origin string) *httptest.ResponseRecorder {
+		req := idempotentRequest("/api/v1/todos", "user-1", "key-1", `{"title":"a"}`)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	first := send("https://a.example.com")
+	retry := send("https://b.example.com")
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls.Load())
+	}
+	if got := retry.Header().Values("Access-Control-Allow-Origin"); !slices.Equal(got, []string{"https://b.example.com"}) {
+		t.Errorf("expected the retry's own CORS origin, got %v", got)
+	}
+	if got := retry.Header().Values(middleware.RequestIDHeader); len(got) != 1 || got[0] == first.Header().Get(middleware.RequestIDHeader) {
+		t.Errorf("expected the retry's own request ID, got %v", got)
+	}
+	if retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected the handler's headers replayed, got %v", retry.Header())
+	}
+}
+
+func TestIdempotency_Scoping(t *testing.T) {
+	tests := []struct {
+		name      string
+		second    *http.Request
+		wantCalls int32
+		wantCode  int
+	}{
+		{"different body", idempotentRequest("/api/v1/todos", "user-1", "key-1", `{"title":"b"}`), 1, http.StatusUnprocessableEntity},
+		{"different user", idempotentRequest("/api/v1/todos", "user-2", "key-1", `{"title":"a"}`), 2, http.StatusCreated},
+		{"different key", idempotentRequest("/api/v1/todos", "user-1", "key-2", `{"title":"a"}`), 2, http.StatusCreated},
+		{"no key", idempotentRequest("/api/v1/todos", "user-1", "", `{"title":"a"}`), 2, http.StatusCreated},
+		{"route not covered", idempotentRequest("/api/v1/webhooks", "user-1", "key-1", `{"title":"a"}`), 2, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			h := newIdempotency(t, newMemIdempotencyStore()).Middleware(countingCreate(&calls))
+
+			h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/api/v1/todos", "user-1", "key-1", `{"title":"a"}`))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.second)
+
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.wantCalls, calls.Load())
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := newIdempotency(t, newMemIdempotencyStore()).Middleware(slow)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/api/v1/todos", "user-1", "key-1", `{}`))
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, idempotentRequest("/api/v1/todos", "user-1", "key-1", `{}`))
+	close(finish)
+	<-done
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409 while original is in flight, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	var calls atomic.Int32
+	flaky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := newIdempotency(t, newMemIdempotencyStore()).Middleware(flaky)
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/api/v1/todos", "user-1", "key-1", `{}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, idempotentRequest("/api/v1/todos", "user-1", "key-1", `{}`))
+
+	if calls.Load() != 2 || w.Code != http.StatusCreated {
+		t.Errorf("expected retry after 500 to run again, calls=%d status=%d", calls.Load(), w.Code)
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	store := newMemIdempotencyStore()
+	h := newIdempotency(t, store).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	func() {
+		defer func() { _ = recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/api/v1/todos", "user-1", "key-1", `{}`))
+	}()
+
+	if len(store.records) != 0 {
+		t.Errorf("expected key released after panic, got %v", store.records)
+	}
+}
+
+func TestIdempotency_AnonymousSignUp(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotency(t, newMemIdempotencyStore()).Middleware(countingCreate(&calls))
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest("/api/v1/auth/signup", "", "key-1", `{"email":"a@example.com"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, idempotentRequest("/api/v1/auth/signup", "", "key-1", `{"email":"a@example.com"}`))
+
+	if calls.Load() != 1 || w.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected sign-up retry to be replayed, calls=%d", calls.Load())
+	}
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotency(t, newMemIdempotencyStore()).Middleware(countingCreate(&calls))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, idempotentRequest("/api/v1/todos", "user-1", string(bytes.Repeat([]byte("k"), 256)), `{}`))
+
+	if w.Code != http.StatusBadRequest || calls.Load() != 0 {
+		t.Errorf("expected 400 without running handler, got %d (calls=%d)", w.Code, calls.Load())
+	}
+}
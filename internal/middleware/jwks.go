@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
@@ -9,6 +10,10 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 )
 
 type jwksKey struct {
@@ -37,7 +42,8 @@ func NewJWKSClient(url string) *JWKSClient {
 		url:  url,
 		keys: make(map[string]*rsa.PublicKey),
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
@@ -61,9 +67,11 @@ func (c *JWKSClient) GetKey(kid string) (*rsa.PublicKey, error) {
 	}
 
 	// Cache miss — fetch and retry
-	if err := c.refresh(); err != nil {
+	if err := c.refresh(context.Background()); err != nil {
+		metrics.JWKSRefreshes.WithLabelValues("failure").Inc()
 		return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
 	}
+	metrics.JWKSRefreshes.WithLabelValues("success").Inc()
 
 	c.mu.RLock()
 	key, ok = c.keys[kid]
@@ -75,8 +83,37 @@ func (c *JWKSClient) GetKey(kid string) (*rsa.PublicKey, error) {
 	return key, nil
 }
 
-func (c *JWKSClient) refresh() error {
-	resp, err := c.httpClient.Get(c.url)
+// Ready reports whether signing keys are available, fetching them if none
+// have been fetched yet. It is used as a readiness check.
+func (c *JWKSClient) Ready(ctx context.Context) error {
+	c.mu.RLock()
+	n := len(c.keys)
+	c.mu.RUnlock()
+	if n > 0 {
+		return nil
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		metrics.JWKSRefreshes.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to refresh JWKS: %w", err)
+	}
+	metrics.JWKSRefreshes.WithLabelValues("success").Inc()
+
+	c.mu.RLock()
+	n = len(c.keys)
+	c.mu.RUnlock()
+	if n == 0 {
+		return fmt.Errorf("JWKS has no RSA signing keys")
+	}
+	return nil
+}
+
+func (c *JWKSClient) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
@@ -1,6 +1,7 @@
 package middleware_test
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
@@ -10,6 +11,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 	"github.com/jaekwang-park/todo-api/internal/middleware"
 )
 
@@ -152,3 +156,65 @@ func TestJWKSClient_FetchError(t *testing.T) {
 		t.Fatal("expected error on server error, got nil")
 	}
 }
+
+func TestJWKSClient_RefreshMetrics(t *testing.T) {
+	jwksData, _ := generateTestJWKS(t, "test-kid-1")
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(jwksData)
+	}))
+	defer server.Close()
+
+	failures := testutil.ToFloat64(metrics.JWKSRefreshes.WithLabelValues("failure"))
+	successes := testutil.ToFloat64(metrics.JWKSRefreshes.WithLabelValues("success"))
+
+	_, _ = middleware.NewJWKSClient(server.URL).GetKey("test-kid-1")
+	fail = false
+	_, _ = middleware.NewJWKSClient(server.URL).GetKey("test-kid-1")
+
+	if got := testutil.ToFloat64(metrics.JWKSRefreshes.WithLabelValues("failure")) - failures; got != 1 {
+		t.Errorf("expected 1 failed refresh, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.JWKSRefreshes.WithLabelValues("success")) - successes; got != 1 {
+		t.Errorf("expected 1 successful refresh, got %v", got)
+	}
+}
+
+func TestJWKSClient_Ready(t *testing.T) {
+	jwksData, _ := generateTestJWKS(t, "test-kid-1")
+	fail := true
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(jwksData)
+	}))
+	defer server.Close()
+
+	client := middleware.NewJWKSClient(server.URL)
+
+	if err := client.Ready(context.Background()); err == nil {
+		t.Fatal("expected error while JWKS endpoint fails, got nil")
+	}
+
+	// A failed fetch does not hold off the next readiness check
+	fail = false
+	if err := client.Ready(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Keys are cached once fetched
+	if err := client.Ready(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if callCount != 2 {
+		t.Errorf("expected 2 fetch calls, got %d", callCount)
+	}
+}
@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	dec := json.NewDecoder(buf)
+	for {
+		var line map[string]any
+		if err := dec.Decode(&line); err == io.EOF {
+			return lines
+		} else if err != nil {
+			t.Fatalf("failed to decode log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestContextHandler_AddsRequestAndUserID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(middleware.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// As a service or repository would log deep inside the request.
+		logger.ErrorContext(r.Context(), "query failed")
+	})
+	auth := mustNewAuth(t, middleware.AuthConfig{DevMode: true})
+	h := middleware.RequestID(middleware.Logging(logger)(auth.Middleware(inner)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	req.Header.Set("X-User-ID", "user-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	// The access log line is written outside Auth but still sees the user.
+	for _, line := range lines {
+		if line["request_id"] != "req-1" || line["user_id"] != "user-1" {
+			t.Errorf("expected request_id and user_id on %q, got %v", line["msg"], line)
+		}
+	}
+}
+
+func TestContextHandler_WithoutRequestContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(middleware.NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "dispatcher")
+
+	logger.InfoContext(context.Background(), "round finished")
+
+	out := buf.String()
+	if strings.Contains(out, "request_id") || strings.Contains(out, "user_id") {
+		t.Errorf("expected no request attributes, got %s", out)
+	}
+	if !strings.Contains(out, `"component":"dispatcher"`) {
+		t.Errorf("expected attributes from With to be kept, got %s", out)
+	}
+}
+
+func TestRecovery_LogsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(middleware.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	h := middleware.RequestID(middleware.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-panic")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 || lines[0]["request_id"] != "req-panic" {
+		t.Errorf("expected panic log with request_id, got %v", lines)
+	}
+	if !strings.Contains(w.Body.String(), `"request_id":"req-panic"`) {
+		t.Errorf("expected request_id in error body, got %s", w.Body.String())
+	}
+}
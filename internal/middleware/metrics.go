@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
+)
+
+// unmatchedRoute labels requests no route matched.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method outside the standard set, so
+// clients cannot create a label per made-up method.
+const otherMethod = "OTHER"
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// RouteFunc returns the route template for a request, such as
+// /api/v1/todos/{id}. fallback is used instead if the handler does not
+// recognise the path, so that made-up paths do not each become a label.
+type RouteFunc func(r *http.Request) (route, fallback string)
+
+// Metrics records request counts and latencies by route template. It should
+// run outside Recovery so that recovered panics are counted as 500s.
+func Metrics(routeOf RouteFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			defer func() {
+				route, fallback := routeOf(r)
+				if rec.statusCode == http.StatusNotFound || rec.statusCode == http.StatusMethodNotAllowed {
+					route = fallback
+				}
+				route, method := routeLabel(route), methodLabel(r.Method)
+				status := strconv.Itoa(rec.statusCode)
+				metrics.HTTPRequests.WithLabelValues(route, method, status).Inc()
+				metrics.HTTPDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+				// The span was named before the status was known.
+				trace.SpanFromContext(r.Context()).SetName(method + " " + route)
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// SpanName returns an otelhttp span name formatter that names server spans
+// like the metrics labels, such as "GET /api/v1/todos/{id}". Metrics renames
+// the span to the fallback route if the handler does not know the path.
+func SpanName(routeOf RouteFunc) func(string, *http.Request) string {
+	return func(_ string, r *http.Request) string {
+		route, _ := routeOf(r)
+		return methodLabel(r.Method) + " " + routeLabel(route)
+	}
+}
+
+func routeLabel(route string) string {
+	if route == "" {
+		return unmatchedRoute
+	}
+	return route
+}
+
+func methodLabel(method string) string {
+	if !standardMethods[method] {
+		return otherMethod
+	}
+	return method
+}
@@ -0,0 +1,126 @@
+package middleware_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+func fixedRoute(route, fallback string) middleware.RouteFunc {
+	return func(r *http.Request) (string, string) {
+		return route, fallback
+	}
+}
+
+func TestMetrics_Labels(t *testing.T) {
+	tests := []struct {
+		name      string
+		route     string
+		fallback  string
+		status    int
+		wantRoute string
+	}{
+		{"matched route", "/api/v1/todos/{id}", "/api/v1/todos/{id}", http.StatusOK, "/api/v1/todos/{id}"},
+		{"unknown path under a route uses fallback", "/api/v1/todos/{id}/nonsense", "/api/v1/todos/*", http.StatusNotFound, "/api/v1/todos/*"},
+		{"no route matched", "", "", http.StatusNotFound, "unmatched"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(tt.wantRoute, http.MethodGet, strconv.Itoa(tt.status)))
+
+			h := middleware.Metrics(fixedRoute(tt.route, tt.fallback))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/anything", nil))
+
+			after := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(tt.wantRoute, http.MethodGet, strconv.Itoa(tt.status)))
+			if after-before != 1 {
+				t.Errorf("expected request counted under route %q", tt.wantRoute)
+			}
+		})
+	}
+}
+
+func TestMetrics_NonStandardMethod(t *testing.T) {
+	before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("/api/v1/todos", "OTHER", "405"))
+
+	h := middleware.Metrics(fixedRoute("/api/v1/todos", "/api/v1/todos"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("XYZZY1234", "/api/v1/todos", nil))
+
+	if got := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("/api/v1/todos", "OTHER", "405")) - before; got != 1 {
+		t.Errorf("expected request counted under method OTHER, got %v", got)
+	}
+}
+
+func TestMetrics_CountsRecoveredPanics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	panics := testutil.ToFloat64(metrics.Panics)
+	errors := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("/panic", http.MethodGet, "500"))
+
+	h := middleware.Metrics(fixedRoute("/panic", "/panic"))(middleware.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if got := testutil.ToFloat64(metrics.Panics) - panics; got != 1 {
+		t.Errorf("expected 1 panic counted, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("/panic", http.MethodGet, "500")) - errors; got != 1 {
+		t.Errorf("expected the panic counted as a 500, got %v", got)
+	}
+}
+
+func TestSpanName(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		want   string
+	}{
+		{"matched route", http.MethodPatch, "/api/v1/todos/{id}", "PATCH /api/v1/todos/{id}"},
+		{"no route matched", http.MethodGet, "", "GET unmatched"},
+		{"non-standard method", "XYZZY1234", "/api/v1/todos", "OTHER /api/v1/todos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := middleware.SpanName(fixedRoute(tt.route, tt.route))("", httptest.NewRequest(tt.method, "/anything", nil))
+			if name != tt.want {
+				t.Errorf("got %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetrics_RenamesSpanForUnknownPath(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	route := fixedRoute("/api/v1/todos/{id}/nonsense", "/api/v1/todos/*")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos/0b7e8f4a-3c1d-4e2f-9a6b-5c4d3e2f1a0b/nonsense", nil)
+	ctx, span := tracer.Start(req.Context(), middleware.SpanName(route)("", req))
+
+	h := middleware.Metrics(route)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "GET /api/v1/todos/*" {
+		t.Errorf("expected span named after the fallback route, got %v", spans)
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jaekwang-park/todo-api/internal/openapi"
+)
+
+// ValidateRequests rejects requests whose parameters or body do not match
+// the OpenAPI document with 400 VALIDATION_FAILED. Requests the document
+// does not describe are passed on for the router to answer.
+func ValidateRequests(doc *openapi.Document) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := doc.ValidateRequest(r); err != nil && !errors.Is(err, openapi.ErrUnknownOperation) {
+				WriteError(w, r, http.StatusBadRequest, "VALIDATION_FAILED", err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
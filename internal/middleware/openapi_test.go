@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/openapi"
+)
+
+func TestValidateRequests(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantCalled bool
+		wantCode   int
+	}{
+		{"valid", http.MethodPost, "/api/v1/todos", `{"title":"Buy milk"}`, true, http.StatusOK},
+		{"invalid body", http.MethodPost, "/api/v1/todos", `{"title":1}`, false, http.StatusBadRequest},
+		{"invalid query", http.MethodGet, "/api/v1/todos?sort=random", "", false, http.StatusBadRequest},
+		{"not in document", http.MethodGet, "/api/v1/unknown", "", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			var gotBody string
+			inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			middleware.ValidateRequests(doc)(inner).ServeHTTP(w, req)
+
+			if called != tt.wantCalled {
+				t.Errorf("expected handler called = %v", tt.wantCalled)
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if called && gotBody != tt.body {
+				t.Errorf("expected handler to read body %q, got %q", tt.body, gotBody)
+			}
+			if !called {
+				var resp struct {
+					Error struct {
+						Code    string `json:"code"`
+						Message string `json:"message"`
+					} `json:"error"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error.Code != "VALIDATION_FAILED" || resp.Error.Message == "" {
+					t.Errorf("expected VALIDATION_FAILED with a message, got %+v", resp.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/jaekwang-park/todo-api/internal/i18n"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// ProblemContentType is the RFC 7807 media type. Clients that list it in
+// Accept get problem documents; everyone else gets ErrorResponse.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix starts every problem type URI. The URIs identify error
+// codes and are not meant to be dereferenced.
+const problemTypePrefix = "urn:todo-api:problem:"
+
+// problemTitles gives each error code the fixed, human-readable summary RFC
+// 7807 calls the title. Codes missing here fall back to the status text.
+var problemTitles = map[string]string{
+	// Generic request errors.
+	"INVALID_INPUT":          "Invalid input",
+	"INVALID_JSON":           "Malformed JSON body",
+	"INVALID_BODY":           "Unreadable request body",
+	"REQUEST_TOO_LARGE":      "Request body too large",
+	"UNSUPPORTED_MEDIA_TYPE": "Unsupported media type",
+	"VALIDATION_FAILED":      "Request does not match the API description",
+	"METHOD_NOT_ALLOWED":     "Method not allowed",
+	"NOT_FOUND":              "Resource not found",
+	"FORBIDDEN":              "Access denied",
+	"CONFLICT":               "Conflict with the current state",
+	"UNAUTHORIZED":           "Authentication required",
+	"TOO_MANY_REQUESTS":      "Too many requests",
+	"INTERNAL_ERROR":         "Internal server error",
+
+	// Query parameters.
+	"INVALID_STATUS":   "Invalid status filter",
+	"INVALID_PRIORITY": "Invalid priority filter",
+	"INVALID_SORT":     "Invalid sort order",
+	"INVALID_BLOCKED":  "Invalid blocked filter",
+
+	// Idempotency keys.
+	"INVALID_IDEMPOTENCY_KEY": "Invalid idempotency key",
+	"IDEMPOTENCY_KEY_REUSED":  "Idempotency key reused with a different request",
+	"REQUEST_IN_PROGRESS":     "Request with this idempotency key in progress",
+
+	// Cognito.
+	"USER_ALREADY_EXISTS":     "User already exists",
+	"USER_NOT_FOUND":          "User not found",
+	"USER_NOT_CONFIRMED":      "User not confirmed",
+	"INVALID_PASSWORD":        "Password does not meet requirements",
+	"INVALID_CODE":            "Invalid verification code",
+	"CODE_EXPIRED":            "Verification code expired",
+	"NOT_AUTHORIZED":          "Incorrect email or password",
+	"LIMIT_EXCEEDED":          "Attempt limit exceeded",
+	"PASSWORD_RESET_REQUIRED": "Password reset required",
+	"INVALID_PARAMETER":       "Invalid request parameter",
+}
+
+// ProblemType returns the type URI for an error code, e.g.
+// urn:todo-api:problem:invalid-input for INVALID_INPUT.
+func ProblemType(code string) string {
+	return problemTypePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// ProblemTitle returns the title for an error code and whether it is
+// registered.
+func ProblemTitle(code string) (string, bool) {
+	title, ok := problemTitles[code]
+	return title, ok
+}
+
+// Problem is an RFC 7807 problem document. Code, RequestID and Fields are
+// extension members carrying what ErrorBody carries.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code"`
+	RequestID string                `json:"request_id,omitempty"`
+	Fields    []validate.FieldError `json:"fields,omitempty"`
+}
+
+type ErrorBody struct {
+	Code      string                `json:"code"`
+	Message   string                `json:"message"`
+	Fields    []validate.FieldError `json:"fields,omitempty"`
+	RequestID string                `json:"request_id,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// WriteError writes an error response in the format the client accepts.
+// Every error the API returns goes through here.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	writeError(w, r, status, ErrorBody{Code: code, Message: message})
+}
+
+// WriteFieldErrors writes a 400 INVALID_INPUT response listing every field
+// that failed validation.
+func WriteFieldErrors(w http.ResponseWriter, r *http.Request, errs validate.Errors) {
+	writeError(w, r, http.StatusBadRequest, ErrorBody{Code: "INVALID_INPUT", Message: errs.Error(), Fields: errs})
+}
+
+// writeError localizes the message for the client. Callers pass English
+// text, which may be more specific than the catalog entry for the code, so
+// it is kept for English and replaced by the entry for other languages.
+func writeError(w http.ResponseWriter, r *http.Request, status int, body ErrorBody) {
+	body.RequestID = w.Header().Get(RequestIDHeader)
+	lang := Language(r)
+	if lang != language.English || body.Message == "" {
+		if msg, ok := i18n.Message(lang, body.Code); ok {
+			body.Message = msg
+		}
+	}
+	w.Header().Set("Content-Language", lang.String())
+
+	var doc any = ErrorResponse{Error: body}
+	contentType := "application/json"
+	if acceptsProblem(r.Header.Get("Accept")) {
+		title, ok := ProblemTitle(body.Code)
+		if !ok {
+			title = http.StatusText(status)
+		}
+		doc = Problem{
+			Type:      ProblemType(body.Code),
+			Title:     title,
+			Status:    status,
+			Detail:    body.Message,
+			Instance:  r.URL.Path,
+			Code:      body.Code,
+			RequestID: body.RequestID,
+			Fields:    body.Fields,
+		}
+		contentType = ProblemContentType
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode error response", "error", err)
+	}
+}
+
+// acceptsProblem reports whether the Accept header lists the problem media
+// type with a non-zero quality. Wildcards do not count, since clients
+// sending */* have been getting ErrorResponse all along.
+func acceptsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err != nil || v <= 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
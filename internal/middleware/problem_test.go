@@ -0,0 +1,124 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/cognito"
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+func TestWriteError_Negotiation(t *testing.T) {
+	tests := []struct {
+		accept      string
+		wantProblem bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"*/*", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json;q=0.9", true},
+		{"application/problem+json;q=0", false},
+		{"text/html, application/problem+json; charset=utf-8", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/todos/abc", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			w.Header().Set(middleware.RequestIDHeader, "req-1")
+
+			middleware.WriteError(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("expected status 404, got %d", w.Code)
+			}
+			ct := w.Header().Get("Content-Type")
+			if !tt.wantProblem {
+				if ct != "application/json" {
+					t.Errorf("Content-Type: got %q", ct)
+				}
+				var resp middleware.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				if resp.Error.Code != "NOT_FOUND" || resp.Error.RequestID != "req-1" {
+					t.Errorf("unexpected body: %+v", resp)
+				}
+				return
+			}
+
+			if ct != middleware.ProblemContentType {
+				t.Errorf("Content-Type: got %q", ct)
+			}
+			var p middleware.Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			want := middleware.Problem{
+				Type:      "urn:todo-api:problem:not-found",
+				Title:     "Resource not found",
+				Status:    http.StatusNotFound,
+				Detail:    "resource not found",
+				Instance:  "/api/v1/todos/abc",
+				Code:      "NOT_FOUND",
+				RequestID: "req-1",
+			}
+			if p.Type != want.Type || p.Title != want.Title || p.Status != want.Status || p.Detail != want.Detail ||
+				p.Instance != want.Instance || p.Code != want.Code || p.RequestID != want.RequestID {
+				t.Errorf("got %+v, want %+v", p, want)
+			}
+		})
+	}
+}
+
+func TestWriteFieldErrors_Problem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/todos", nil)
+	r.Header.Set("Accept", middleware.ProblemContentType)
+	w := httptest.NewRecorder()
+
+	middleware.WriteFieldErrors(w, r, validate.Errors{{Field: "title", Reason: validate.ReasonRequired}})
+
+	var p middleware.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if p.Type != "urn:todo-api:problem:invalid-input" || p.Status != http.StatusBadRequest {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+	if len(p.Fields) != 1 || p.Fields[0].Field != "title" {
+		t.Errorf("fields: got %+v", p.Fields)
+	}
+}
+
+func TestProblemTitle_CoversErrorCodes(t *testing.T) {
+	codes := append(cognito.ErrorCodes(),
+		// handleServiceError, decoding and routing.
+		"NOT_FOUND", "INVALID_INPUT", "FORBIDDEN", "CONFLICT", "INTERNAL_ERROR",
+		"INVALID_JSON", "REQUEST_TOO_LARGE", "UNSUPPORTED_MEDIA_TYPE", "METHOD_NOT_ALLOWED",
+		"INVALID_STATUS", "INVALID_PRIORITY", "INVALID_SORT", "INVALID_BLOCKED",
+		// Middleware.
+		"UNAUTHORIZED", "TOO_MANY_REQUESTS", "VALIDATION_FAILED", "INVALID_BODY",
+		"INVALID_IDEMPOTENCY_KEY", "IDEMPOTENCY_KEY_REUSED", "REQUEST_IN_PROGRESS",
+	)
+
+	types := make(map[string]string)
+	for _, code := range codes {
+		if _, ok := middleware.ProblemTitle(code); !ok {
+			t.Errorf("%s has no problem title", code)
+		}
+		typ := middleware.ProblemType(code)
+		if !strings.HasPrefix(typ, "urn:todo-api:problem:") {
+			t.Errorf("%s: unexpected type %q", code, typ)
+		}
+		if other, ok := types[typ]; ok && other != code {
+			t.Errorf("%s and %s share type %q", code, other, typ)
+		}
+		types[typ] = code
+	}
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// defaultRateLimitRoute keys the bucket shared by routes without their own limit.
+const defaultRateLimitRoute = "*"
+
+// RateLimitStore holds the token buckets. Use MemoryRateLimitStore for a
+// single instance and a shared store when limits must hold across instances.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitDecision, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type RateLimitConfig struct {
+	Store  RateLimitStore
+	Logger *slog.Logger
+	// Routes maps "METHOD /path" pairs to their limit.
+	Routes map[string]model.RateLimit
+	// Default is shared by all other routes. The zero value leaves them
+	// unlimited.
+	Default model.RateLimit
+	// DefaultStore holds the Default buckets; Store when nil. Every request
+	// takes from them, so a cheaper per-instance store is usually enough.
+	DefaultStore RateLimitStore
+	// TrustedProxies lists the addresses or CIDR ranges of proxies, such as
+	// the load balancer, whose X-Forwarded-For header is believed.
+	TrustedProxies []string
+}
+
+// RateLimiter limits requests per route by authenticated user, or by client
+// IP address for anonymous requests such as login.
+type RateLimiter struct {
+	cfg     RateLimitConfig
+	proxies []netip.Prefix
+}
+
+func NewRateLimiter(cfg RateLimitConfig) (*RateLimiter, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("middleware: Store is required")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.DefaultStore == nil {
+		cfg.DefaultStore = cfg.Store
+	}
+	for route, limit := range cfg.Routes {
+		if limit.Limit <= 0 || limit.Window <= 0 {
+			return nil, fmt.Errorf("middleware: invalid rate limit for %q", route)
+		}
+	}
+	if cfg.Default != (model.RateLimit{}) && (cfg.Default.Limit <= 0 || cfg.Default.Window <= 0) {
+		return nil, fmt.Errorf("middleware: invalid default rate limit")
+	}
+
+	proxies, err := ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("middleware: %w", err)
+	}
+	return &RateLimiter{cfg: cfg, proxies: proxies}, nil
+}
+
+// ParseTrustedProxies parses addresses such as "10.0.0.1" and CIDR ranges
+// such as "10.0.0.0/16".
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", v)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", v)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Middleware must run after Auth so that signed-in users are limited by
+// user ID rather than by the address they share with others.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cleanPath := path.Clean(r.URL.Path)
+		route := r.Method + " " + cleanPath
+		store := l.cfg.Store
+		limit, ok := l.cfg.Routes[route]
+		if !ok {
+			// Load balancer health checks are never limited.
+			if isHealthPath(cleanPath) || l.cfg.Default == (model.RateLimit{}) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			route, limit, store = defaultRateLimitRoute, l.cfg.Default, l.cfg.DefaultStore
+		}
+
+		client := "ip:" + l.clientIP(r).String()
+		if userID := GetUserID(r); userID != "" {
+			client = "user:" + userID
+		}
+
+		decision, err := store.Take(r.Context(), route+"|"+client, limit)
+		if err != nil {
+			// Fail open: an unavailable store should not take the API down.
+			l.cfg.Logger.ErrorContext(r.Context(), "rate limit check failed", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Limit, ceilSeconds(limit.Window)))
+
+		if !decision.Allowed {
+			h.Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			WriteError(w, r, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "rate limit exceeded, retry later")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the address of the client. X-Forwarded-For is read from
+// the right, skipping trusted proxies, so a client cannot spoof its address
+// by sending the header itself.
+func (l *RateLimiter) clientIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.IPv4Unspecified()
+	}
+	addr = addr.Unmap()
+	if !l.trusted(addr) {
+		return addr
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Anything further left was written by an untrusted party.
+			return addr
+		}
+		addr = hop.Unmap()
+		if !l.trusted(addr) {
+			return addr
+		}
+	}
+	return addr
+}
+
+func (l *RateLimiter) trusted(addr netip.Addr) bool {
+	for _, p := range l.proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunCleanup deletes refilled buckets every interval until ctx is cancelled.
+func (l *RateLimiter) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.deleteExpired(ctx, l.cfg.Store)
+			if l.cfg.DefaultStore != l.cfg.Store {
+				l.deleteExpired(ctx, l.cfg.DefaultStore)
+			}
+		}
+	}
+}
+
+func (l *RateLimiter) deleteExpired(ctx context.Context, store RateLimitStore) {
+	n, err := store.DeleteExpired(ctx)
+	if err != nil {
+		l.cfg.Logger.Error("failed to delete expired rate limit buckets", "error", err)
+		return
+	}
+	if n > 0 {
+		l.cfg.Logger.Debug("deleted expired rate limit buckets", "count", n)
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// MemoryRateLimitStore keeps buckets in this process. Each instance then
+// enforces its limits separately.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+}
+
+type memoryBucket struct {
+	model.TokenBucket
+	expiresAt time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]memoryBucket)}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, decision := limit.Take(s.buckets[key].TokenBucket, now)
+	s.buckets[key] = memoryBucket{TokenBucket: bucket, expiresAt: now.Add(decision.Reset)}
+	return decision, nil
+}
+
+func (s *MemoryRateLimitStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var n int64
+	for key, b := range s.buckets {
+		if b.expiresAt.Before(now) {
+			delete(s.buckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+var _ RateLimitStore = (*MemoryRateLimitStore)(nil)
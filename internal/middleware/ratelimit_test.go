@@ -0,0 +1,268 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func newRateLimiter(t *testing.T, cfg middleware.RateLimitConfig) *middleware.RateLimiter {
+	t.Helper()
+	if cfg.Store == nil {
+		cfg.Store = middleware.NewMemoryRateLimitStore()
+	}
+	l, err := middleware.NewRateLimiter(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return l
+}
+
+func rateLimitedRequest(method, path, remoteAddr, userID string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if userID != "" {
+		req = req.WithContext(middleware.SetUserID(req.Context(), userID))
+	}
+	return req
+}
+
+func TestRateLimiter_LimitsRoute(t *testing.T) {
+	h := newRateLimiter(t, middleware.RateLimitConfig{
+		Routes: map[string]model.RateLimit{
+			"POST /api/v1/auth/login": {Limit: 2, Window: time.Minute},
+		},
+	}).Middleware(okHandler)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""))
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: got RateLimit-Remaining %s, want %s", i+1, got, wantRemaining)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	for header, want := range map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "60",
+		"RateLimit-Policy":    "2;w=60",
+		"Retry-After":         "30",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("got %s %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestRateLimiter_Keys(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       *http.Request
+		second      *http.Request
+		wantLimited bool
+	}{
+		{
+			"same ip",
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""),
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:5678", ""),
+			true,
+		},
+		{
+			"different ip",
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""),
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.2:1234", ""),
+			false,
+		},
+		{
+			"same user from different ips",
+			rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-1"),
+			rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.2:1234", "user-1"),
+			true,
+		},
+		{
+			"different users from same ip",
+			rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-1"),
+			rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-2"),
+			false,
+		},
+		{
+			"routes limited separately",
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""),
+			rateLimitedRequest(http.MethodPost, "/api/v1/auth/forgot-password", "203.0.113.1:1234", ""),
+			false,
+		},
+		{
+			"default shared by other routes",
+			rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-1"),
+			rateLimitedRequest(http.MethodPost, "/api/v1/todos", "203.0.113.1:1234", "user-1"),
+			true,
+		},
+		{
+			"health check exempt",
+			rateLimitedRequest(http.MethodGet, "/health", "203.0.113.1:1234", ""),
+			rateLimitedRequest(http.MethodGet, "/health", "203.0.113.1:1234", ""),
+			false,
+		},
+		{
+			"probes exempt",
+			rateLimitedRequest(http.MethodGet, "/health/live", "203.0.113.1:1234", ""),
+			rateLimitedRequest(http.MethodGet, "/health/ready", "203.0.113.1:1234", ""),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newRateLimiter(t, middleware.RateLimitConfig{
+				Routes: map[string]model.RateLimit{
+					"POST /api/v1/auth/login":           {Limit: 1, Window: time.Minute},
+					"POST /api/v1/auth/forgot-password": {Limit: 1, Window: time.Minute},
+				},
+				Default: model.RateLimit{Limit: 1, Window: time.Minute},
+			}).Middleware(okHandler)
+
+			h.ServeHTTP(httptest.NewRecorder(), tt.first)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.second)
+
+			if limited := w.Code == http.StatusTooManyRequests; limited != tt.wantLimited {
+				t.Errorf("expected limited=%v, got status %d", tt.wantLimited, w.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ClientIP(t *testing.T) {
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  string
+		wantSameAsRef bool
+	}{
+		{"forwarded by trusted proxy", "10.0.1.5:443", "198.51.100.7", true},
+		{"through several trusted proxies", "10.0.1.5:443", "198.51.100.7, 10.0.2.9", true},
+		{"spoofed entry left of client", "10.0.1.5:443", "192.0.2.1, 198.51.100.7", true},
+		{"header from untrusted peer ignored", "192.0.2.50:1234", "198.51.100.7", false},
+		{"different client behind proxy", "10.0.1.5:443", "198.51.100.8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newRateLimiter(t, middleware.RateLimitConfig{
+				Default:        model.RateLimit{Limit: 1, Window: time.Minute},
+				TrustedProxies: []string{"10.0.0.0/16"},
+			}).Middleware(okHandler)
+
+			// Use up the bucket of client 198.51.100.7.
+			ref := rateLimitedRequest(http.MethodGet, "/api/v1/todos", "198.51.100.7:1234", "")
+			h.ServeHTTP(httptest.NewRecorder(), ref)
+
+			req := rateLimitedRequest(http.MethodGet, "/api/v1/todos", tt.remoteAddr, "")
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if same := w.Code == http.StatusTooManyRequests; same != tt.wantSameAsRef {
+				t.Errorf("expected same client=%v, got status %d", tt.wantSameAsRef, w.Code)
+			}
+		})
+	}
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitDecision, error) {
+	return model.RateLimitDecision{}, errors.New("database unavailable")
+}
+
+func (failingRateLimitStore) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestRateLimiter_StoreErrorFailsOpen(t *testing.T) {
+	h := newRateLimiter(t, middleware.RateLimitConfig{
+		Store:   failingRateLimitStore{},
+		Default: model.RateLimit{Limit: 1, Window: time.Minute},
+	}).Middleware(okHandler)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-1"))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected request allowed when store fails, got %d", w.Code)
+	}
+}
+
+func TestRateLimiter_DefaultStore(t *testing.T) {
+	h := newRateLimiter(t, middleware.RateLimitConfig{
+		Store:        failingRateLimitStore{},
+		DefaultStore: middleware.NewMemoryRateLimitStore(),
+		Routes: map[string]model.RateLimit{
+			"POST /api/v1/auth/login": {Limit: 1, Window: time.Minute},
+		},
+		Default: model.RateLimit{Limit: 1, Window: time.Minute},
+	}).Middleware(okHandler)
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, rateLimitedRequest(http.MethodGet, "/api/v1/todos", "203.0.113.1:1234", "user-1"))
+		if w.Code != want {
+			t.Errorf("default route request %d: expected %d, got %d", i+1, want, w.Code)
+		}
+	}
+
+	// Listed routes still use Store, which fails open here.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, rateLimitedRequest(http.MethodPost, "/api/v1/auth/login", "203.0.113.1:1234", ""))
+	if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("expected login to use Store, got %d with RateLimit-Limit %q", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestNewRateLimiter_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  middleware.RateLimitConfig
+	}{
+		{"missing store", middleware.RateLimitConfig{}},
+		{"zero route limit", middleware.RateLimitConfig{
+			Store:  middleware.NewMemoryRateLimitStore(),
+			Routes: map[string]model.RateLimit{"POST /api/v1/auth/login": {Window: time.Minute}},
+		}},
+		{"invalid default", middleware.RateLimitConfig{
+			Store:   middleware.NewMemoryRateLimitStore(),
+			Default: model.RateLimit{Limit: 10},
+		}},
+		{"invalid proxy", middleware.RateLimitConfig{
+			Store:          middleware.NewMemoryRateLimitStore(),
+			TrustedProxies: []string{"alb.internal"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := middleware.NewRateLimiter(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package middleware
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+
+	"github.com/jaekwang-park/todo-api/internal/metrics"
 )
 
 type recoveryWriter struct {
@@ -33,7 +34,8 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Error("panic recovered",
+					metrics.Panics.Inc()
+					logger.ErrorContext(r.Context(), "panic recovered",
 						"error", err,
 						"method", r.Method,
 						"path", r.URL.Path,
@@ -44,16 +46,7 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 						return
 					}
 
-					rw.Header().Set("Content-Type", "application/json")
-					rw.WriteHeader(http.StatusInternalServerError)
-					if encErr := json.NewEncoder(rw).Encode(map[string]any{
-						"error": map[string]string{
-							"code":    "INTERNAL_ERROR",
-							"message": "internal server error",
-						},
-					}); encErr != nil {
-						logger.Error("failed to write recovery response", "error", encErr)
-					}
+					WriteError(rw, r, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
 				}
 			}()
 
@@ -93,3 +93,29 @@ func TestRecovery_PanicAfterHeaderWritten(t *testing.T) {
 		t.Error("expected panic to be logged")
 	}
 }
+
+func TestRecovery_Problem(t *testing.T) {
+	logger, _ := newTestLogger()
+	h := middleware.Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	r.Header.Set("Accept", middleware.ProblemContentType)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != middleware.ProblemContentType {
+		t.Errorf("Content-Type: got %q", ct)
+	}
+	var p middleware.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if p.Code != "INTERNAL_ERROR" || p.Instance != "/api/v1/todos" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// AmznTraceIDHeader is added by the ALB to every request it forwards.
+	AmznTraceIDHeader = "X-Amzn-Trace-Id"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID accepts the caller's X-Request-ID or generates one, stores it
+// and the ALB trace ID in the request context and echoes the request ID in
+// the response. It should be the outermost middleware so that every log
+// line written for the request carries the ID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		traceID := r.Header.Get(AmznTraceIDHeader)
+		if !validRequestID(traceID) {
+			traceID = ""
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request_id", id))
+		ctx := context.WithValue(r.Context(), requestInfoKey, &requestInfo{requestID: id, traceID: traceID})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID accepts IDs of printable ASCII only, so a caller cannot
+// inject anything into logs or response headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,100 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{"generated when missing", "", false},
+		{"caller's ID kept", "client-req-42", true},
+		{"too long replaced", strings.Repeat("a", 129), false},
+		{"control characters replaced", "abc\r\nX-Injected: 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = middleware.RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			got := w.Header().Get(middleware.RequestIDHeader)
+			if got == "" || got != fromContext {
+				t.Fatalf("expected echoed ID to match context, got header %q context %q", got, fromContext)
+			}
+			if kept := got == tt.header; kept != tt.wantKept {
+				t.Errorf("expected kept=%v, got %q", tt.wantKept, got)
+			}
+		})
+	}
+}
+
+func TestRequestID_Unique(t *testing.T) {
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		id := w.Header().Get(middleware.RequestIDHeader)
+		if seen[id] {
+			t.Fatalf("duplicate request ID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRequestID_TraceID(t *testing.T) {
+	var traceID string
+	h := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = middleware.TraceIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.AmznTraceIDHeader, "Root=1-67891233-abcdef012345678912345678")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if traceID != "Root=1-67891233-abcdef012345678912345678" {
+		t.Errorf("expected trace ID from ALB header, got %q", traceID)
+	}
+}
+
+func TestRequestID_InErrorBody(t *testing.T) {
+	auth, _ := middleware.NewAuth(middleware.AuthConfig{DevMode: true})
+	h := middleware.RequestID(auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-401")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var body struct {
+		Error struct {
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if body.Error.RequestID != "req-401" {
+		t.Errorf("expected request_id=req-401 in error body, got %q", body.Error.RequestID)
+	}
+}
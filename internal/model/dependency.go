@@ -0,0 +1,14 @@
+package model
+
+// TodoDependency records that TodoID cannot be completed before BlockerID.
+type TodoDependency struct {
+	TodoID    string `json:"todo_id"`
+	BlockerID string `json:"blocker_id"`
+}
+
+// DependencyGraph is a user's dependency graph. Todos are in topological
+// order: every blocker appears before the todos it blocks.
+type DependencyGraph struct {
+	Todos []Todo           `json:"todos"`
+	Edges []TodoDependency `json:"edges"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type EventType string
+
+const (
+	EventTodoCreated   EventType = "todo.created"
+	EventTodoUpdated   EventType = "todo.updated"
+	EventTodoCompleted EventType = "todo.completed"
+	EventTodoReopened  EventType = "todo.reopened"
+	EventTodoDeleted   EventType = "todo.deleted"
+)
+
+// EventTypes lists every event type a subscriber may ask for.
+var EventTypes = []EventType{
+	EventTodoCreated, EventTodoUpdated, EventTodoCompleted, EventTodoReopened, EventTodoDeleted,
+}
+
+func (t EventType) IsValid() bool {
+	for _, et := range EventTypes {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain event raised by a todo mutation. Todo holds the state
+// after the change; for deletions only TodoID is set.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	UserID     string    `json:"user_id"`
+	TodoID     string    `json:"todo_id"`
+	Todo       *Todo     `json:"todo,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "net/http"
+
+// IdempotencyRecord is the stored outcome of a request made with an
+// Idempotency-Key. StatusCode is zero while the request is still in flight.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+}
+
+// Completed reports whether the original request has finished.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit allows Limit requests per Window. It is enforced as a token
+// bucket holding up to Limit tokens and refilling at Limit per Window, so a
+// client may burst up to Limit requests and then continue at the average rate.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// TokenBucket is the state of one client's bucket at UpdatedAt.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket.
+// Reset is how long until the bucket is full again; RetryAfter is how long
+// until the next request would be allowed and is zero when one is allowed.
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Take refills bucket up to now and takes one token from it if one is
+// available. A zero bucket is treated as full.
+func (l RateLimit) Take(bucket TokenBucket, now time.Time) (TokenBucket, RateLimitDecision) {
+	capacity := float64(l.Limit)
+	perSecond := capacity / l.Window.Seconds()
+
+	tokens := capacity
+	if !bucket.UpdatedAt.IsZero() {
+		elapsed := max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+		tokens = min(capacity, bucket.Tokens+elapsed*perSecond)
+	}
+
+	decision := RateLimitDecision{Limit: l.Limit}
+	if tokens >= 1 {
+		tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+	}
+	decision.Remaining = int(math.Floor(tokens))
+	decision.Reset = secondsToDuration((capacity - tokens) / perSecond)
+
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, decision
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
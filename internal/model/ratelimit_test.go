@@ -0,0 +1,48 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+func TestRateLimit_Take(t *testing.T) {
+	limit := model.RateLimit{Limit: 5, Window: time.Minute}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var bucket model.TokenBucket
+	var d model.RateLimitDecision
+	for i := 0; i < 5; i++ {
+		bucket, d = limit.Take(bucket, start)
+		if !d.Allowed {
+			t.Fatalf("request %d: expected allowed", i+1)
+		}
+		if d.Remaining != 4-i {
+			t.Errorf("request %d: got remaining %d, want %d", i+1, d.Remaining, 4-i)
+		}
+	}
+
+	bucket, d = limit.Take(bucket, start)
+	if d.Allowed {
+		t.Fatal("expected sixth request to be limited")
+	}
+	if d.RetryAfter != 12*time.Second {
+		t.Errorf("got RetryAfter %v, want 12s", d.RetryAfter)
+	}
+	if d.Reset != time.Minute {
+		t.Errorf("got Reset %v, want 1m", d.Reset)
+	}
+
+	// One token refills every 12 seconds.
+	bucket, d = limit.Take(bucket, start.Add(12*time.Second))
+	if !d.Allowed || d.Remaining != 0 {
+		t.Errorf("expected allowed with none remaining after refill, got %+v", d)
+	}
+
+	// The bucket never holds more than Limit tokens.
+	_, d = limit.Take(bucket, start.Add(time.Hour))
+	if !d.Allowed || d.Remaining != 4 {
+		t.Errorf("expected a full bucket after an hour, got %+v", d)
+	}
+}
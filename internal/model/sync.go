@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+// SyncPosition is a point in a user's change feed. Seq is a change_seq
+// value; TodoID is set when a page ended part way through the changes at Seq
+// and is empty once the client has caught up to Seq.
+type SyncPosition struct {
+	Seq    uint64
+	TodoID string
+}
+
+// IsZero reports whether the position is the start of the feed.
+func (p SyncPosition) IsZero() bool {
+	return p.Seq == 0 && p.TodoID == ""
+}
+
+// Tombstone records a deleted todo.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TodoChanges is one page of a user's change feed.
+type TodoChanges struct {
+	Todos   []Todo
+	Deleted []Tombstone
+	Next    SyncPosition
+	HasMore bool
+}
+
+// SyncResult is the client-facing page of changes, with the position
+// encoded as an opaque token.
+type SyncResult struct {
+	Todos     []Todo      `json:"todos"`
+	Deleted   []Tombstone `json:"deleted"`
+	NextToken string      `json:"next_token"`
+	HasMore   bool        `json:"has_more"`
+}
+
+// SyncOp is the kind of change a client mutation makes.
+type SyncOp string
+
+const (
+	SyncOpCreate SyncOp = "create"
+	SyncOpUpdate SyncOp = "update"
+	SyncOpDelete SyncOp = "delete"
+)
+
+// ConflictStrategy decides what happens when a mutation's todo changed on
+// the server after the client last saw it.
+type ConflictStrategy string
+
+const (
+	// ConflictMerge is last-writer-wins by field: each field the client
+	// changed is applied if the client changed it after the server last
+	// changed that field. Fields the client did not change keep the server
+	// value. A mutation that applies no field is a conflict.
+	ConflictMerge ConflictStrategy = "merge"
+	// ConflictReject leaves the todo untouched and returns the server copy.
+	ConflictReject ConflictStrategy = "reject"
+)
+
+func (s ConflictStrategy) IsValid() bool {
+	return s == ConflictMerge || s == ConflictReject
+}
+
+// MutationStatus is the outcome of one client mutation.
+type MutationStatus string
+
+const (
+	MutationApplied  MutationStatus = "applied"
+	MutationMerged   MutationStatus = "merged"
+	MutationConflict MutationStatus = "conflict"
+	MutationFailed   MutationStatus = "error"
+)
+
+type MutationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MutationResult reports the outcome of the mutation at Index in a batch.
+// Todo is the todo as now stored, or the unchanged server copy on conflict.
+type MutationResult struct {
+	Index    int            `json:"index"`
+	ClientID string         `json:"client_id,omitempty"`
+	ID       string         `json:"id,omitempty"`
+	Status   MutationStatus `json:"status"`
+	Todo     *Todo          `json:"todo,omitempty"`
+	Error    *MutationError `json:"error,omitempty"`
+}
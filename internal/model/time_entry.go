@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// TimeEntry is a span of time tracked against a todo. A nil EndedAt marks a
+// running timer.
+type TimeEntry struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	TodoID    string     `json:"todo_id"`
+	Note      string     `json:"note"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Running reports whether the entry is an active timer.
+func (e TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Duration returns the tracked time, measuring running timers up to now.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+// DailyTime is the tracked time for one calendar day in the report's time
+// zone.
+type DailyTime struct {
+	Date           string `json:"date"` // YYYY-MM-DD
+	TrackedMinutes int    `json:"tracked_minutes"`
+}
+
+// TodoTime compares a todo's estimate with the time tracked against it.
+type TodoTime struct {
+	TodoID          string `json:"todo_id"`
+	Title           string `json:"title"`
+	EstimateMinutes *int   `json:"estimate_minutes,omitempty"`
+	TrackedMinutes  int    `json:"tracked_minutes"`
+}
+
+// TimeReport breaks tracked time down by day and by todo. Todos have no tags
+// or projects, so there are no breakdowns by those.
+type TimeReport struct {
+	TimeZone        string      `json:"time_zone"`
+	From            time.Time   `json:"from"`
+	To              time.Time   `json:"to"`
+	TrackedMinutes  int         `json:"tracked_minutes"`
+	EstimateMinutes int         `json:"estimate_minutes"`
+	ByDay           []DailyTime `json:"by_day"`
+	ByTodo          []TodoTime  `json:"by_todo"`
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type TodoStatus string
 
@@ -13,25 +16,169 @@ func (s TodoStatus) IsValid() bool {
 	return s == TodoStatusPending || s == TodoStatusCompleted
 }
 
+type TodoPriority string
+
+const (
+	TodoPriorityNone   TodoPriority = "none"
+	TodoPriorityLow    TodoPriority = "low"
+	TodoPriorityMedium TodoPriority = "medium"
+	TodoPriorityHigh   TodoPriority = "high"
+	TodoPriorityUrgent TodoPriority = "urgent"
+)
+
+func (p TodoPriority) IsValid() bool {
+	switch p {
+	case TodoPriorityNone, TodoPriorityLow, TodoPriorityMedium, TodoPriorityHigh, TodoPriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// TodoSort selects the ordering of a todo list.
+type TodoSort string
+
+const (
+	TodoSortCreatedAt TodoSort = "created_at"
+	TodoSortPriority  TodoSort = "priority"
+)
+
+func (s TodoSort) IsValid() bool {
+	return s == TodoSortCreatedAt || s == TodoSortPriority
+}
+
 type Todo struct {
-	ID          string     `json:"id"`
-	UserID      string     `json:"user_id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TodoStatus `json:"status"`
-	DueAt       *time.Time `json:"due_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID              string       `json:"id"`
+	UserID          string       `json:"user_id"`
+	Title           string       `json:"title"`
+	Description     string       `json:"description"`
+	Status          TodoStatus   `json:"status"`
+	Priority        TodoPriority `json:"priority"`
+	Important       bool         `json:"important"`
+	Urgent          bool         `json:"urgent"`
+	DueAt           *time.Time   `json:"due_at,omitempty"`
+	EstimateMinutes *int         `json:"estimate_minutes,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+
+	// FieldsModifiedAt records when each field was last changed, by its
+	// JSON name. It is only populated on single-todo reads and writes.
+	FieldsModifiedAt map[string]time.Time `json:"-"`
+
+	// BlockedBy and Blocking hold dependency todo IDs. They are only
+	// populated on single-todo reads.
+	BlockedBy []string `json:"blocked_by,omitempty"`
+	Blocking  []string `json:"blocking,omitempty"`
+
+	// Owner and Counts are only populated on lists that expand them.
+	Owner  *TodoOwner  `json:"owner,omitempty"`
+	Counts *TodoCounts `json:"counts,omitempty"`
+}
+
+// FieldModifiedAt returns when the field with the given JSON name was last
+// changed. Fields without a record have not changed since the todo was
+// created.
+func (t Todo) FieldModifiedAt(field string) time.Time {
+	if at, ok := t.FieldsModifiedAt[field]; ok {
+		return at
+	}
+	return t.CreatedAt
+}
+
+// TodoOwner is the public profile of the user a todo belongs to.
+type TodoOwner struct {
+	ID              string `json:"id"`
+	Email           string `json:"email"`
+	Nickname        string `json:"nickname"`
+	ProfileImageURL string `json:"profile_image_url"`
+}
+
+// TodoCounts summarises a todo's related records.
+type TodoCounts struct {
+	BlockedBy   int `json:"blocked_by"`
+	Blocking    int `json:"blocking"`
+	TimeEntries int `json:"time_entries"`
+}
+
+// TodoFields are the todo attributes a list can be narrowed to with fields=.
+var TodoFields = []string{
+	"id", "user_id", "title", "description", "status", "priority",
+	"important", "urgent", "due_at", "estimate_minutes", "created_at", "updated_at",
+}
+
+// IsTodoField reports whether name is one of TodoFields.
+func IsTodoField(name string) bool {
+	return slices.Contains(TodoFields, name)
+}
+
+// TodoExpand names related data a list can inline with expand=.
+type TodoExpand string
+
+const (
+	TodoExpandOwner  TodoExpand = "owner"
+	TodoExpandCounts TodoExpand = "counts"
+)
+
+func (e TodoExpand) IsValid() bool {
+	return e == TodoExpandOwner || e == TodoExpandCounts
 }
 
 type TodoListParams struct {
-	UserID string
-	Status *TodoStatus
-	Cursor string
-	Limit  int
+	UserID    string
+	Status    *TodoStatus
+	Priority  *TodoPriority
+	Important *bool
+	Urgent    *bool
+	Blocked   *bool // has at least one incomplete blocker
+	Sort      TodoSort
+	Cursor    string
+	Limit     int
+
+	// Fields limits the columns read to these TodoFields; id is always
+	// read. Empty reads every field.
+	Fields []string
+	Expand []TodoExpand
+}
+
+// Expands reports whether the list should inline e.
+func (p TodoListParams) Expands(e TodoExpand) bool {
+	return slices.Contains(p.Expand, e)
 }
 
 type TodoListResult struct {
 	Todos      []Todo `json:"todos"`
 	NextCursor string `json:"next_cursor,omitempty"`
 }
+
+// EisenhowerQuadrant identifies one cell of the important/urgent matrix.
+type EisenhowerQuadrant string
+
+const (
+	QuadrantDo        EisenhowerQuadrant = "do"        // important and urgent
+	QuadrantSchedule  EisenhowerQuadrant = "schedule"  // important, not urgent
+	QuadrantDelegate  EisenhowerQuadrant = "delegate"  // urgent, not important
+	QuadrantEliminate EisenhowerQuadrant = "eliminate" // neither
+)
+
+// Quadrants lists the matrix cells in display order.
+var Quadrants = []EisenhowerQuadrant{QuadrantDo, QuadrantSchedule, QuadrantDelegate, QuadrantEliminate}
+
+// Flags returns the important/urgent values that place a todo in the quadrant.
+func (q EisenhowerQuadrant) Flags() (important, urgent bool) {
+	switch q {
+	case QuadrantDo:
+		return true, true
+	case QuadrantSchedule:
+		return true, false
+	case QuadrantDelegate:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+type MatrixResult struct {
+	Do        TodoListResult `json:"do"`
+	Schedule  TodoListResult `json:"schedule"`
+	Delegate  TodoListResult `json:"delegate"`
+	Eliminate TodoListResult `json:"eliminate"`
+}
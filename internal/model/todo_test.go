@@ -26,3 +26,49 @@ func TestTodoStatus_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestTodoPriority_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority model.TodoPriority
+		want     bool
+	}{
+		{"none", model.TodoPriorityNone, true},
+		{"low", model.TodoPriorityLow, true},
+		{"medium", model.TodoPriorityMedium, true},
+		{"high", model.TodoPriorityHigh, true},
+		{"urgent", model.TodoPriorityUrgent, true},
+		{"empty", model.TodoPriority(""), false},
+		{"invalid", model.TodoPriority("critical"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.priority.IsValid(); got != tt.want {
+				t.Errorf("TodoPriority(%q).IsValid() = %v, want %v", tt.priority, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEisenhowerQuadrant_Flags(t *testing.T) {
+	tests := []struct {
+		quadrant      model.EisenhowerQuadrant
+		wantImportant bool
+		wantUrgent    bool
+	}{
+		{model.QuadrantDo, true, true},
+		{model.QuadrantSchedule, true, false},
+		{model.QuadrantDelegate, false, true},
+		{model.QuadrantEliminate, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.quadrant), func(t *testing.T) {
+			important, urgent := tt.quadrant.Flags()
+			if important != tt.wantImportant || urgent != tt.wantUrgent {
+				t.Errorf("Flags() = (%v, %v), want (%v, %v)", important, urgent, tt.wantImportant, tt.wantUrgent)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ type User struct {
 	Email           string    `json:"email"`
 	Nickname        string    `json:"nickname"`
 	ProfileImageURL string    `json:"profile_image_url"`
+	Language        string    `json:"language"` // "en", "ko" or "" for no preference
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -0,0 +1,500 @@
+// Package openapi holds the API's OpenAPI 3.1 document and validates
+// requests and responses against it.
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// resourceURL names the document for the schema compiler, so schema
+// locations read as openapi.json#/components/schemas/Todo.
+const resourceURL = "openapi.json"
+
+// maxValidatedBodySize bounds the request bodies read for validation.
+// Larger bodies are passed on unvalidated for the handler to reject.
+const maxValidatedBodySize = 1 << 20 // 1 MB
+
+// ErrUnknownOperation is returned for requests the document does not
+// describe. The router decides how to answer those.
+var ErrUnknownOperation = errors.New("operation not in OpenAPI document")
+
+// Spec returns the OpenAPI document as JSON.
+func Spec() []byte {
+	return spec
+}
+
+// Document is the compiled OpenAPI document.
+type Document struct {
+	routes []*route
+}
+
+// Paths returns the path templates in the document, such as
+// /api/v1/todos/{id}.
+func (d *Document) Paths() []string {
+	paths := make([]string, len(d.routes))
+	for i, rt := range d.routes {
+		paths[i] = rt.template
+	}
+	return paths
+}
+
+type route struct {
+	template string
+	segments []string
+	ops      map[string]*operation
+}
+
+type operation struct {
+	params       []*parameter
+	body         *jsonschema.Schema
+	bodyRequired bool
+	// responses maps a status code, or "default", to the schema of the
+	// response body for each JSON media type. The inner map is empty for a
+	// response without a body.
+	responses map[string]map[string]*jsonschema.Schema
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   *jsonschema.Schema
+	typ      string
+}
+
+// Raw document shapes, as far as validation needs them. Refs are kept as
+// JSON pointers so that schemas compile at their location in the document.
+type rawDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]rawParameter `json:"parameters"`
+		Responses  map[string]rawResponse  `json:"responses"`
+	} `json:"components"`
+}
+
+type rawParameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   struct {
+		Ref  string `json:"$ref"`
+		Type string `json:"type"`
+	} `json:"schema"`
+}
+
+type rawOperation struct {
+	Parameters  []rawParameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                       `json:"required"`
+		Content  map[string]json.RawMessage `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]rawResponse `json:"responses"`
+}
+
+type rawResponse struct {
+	Ref     string                     `json:"$ref"`
+	Content map[string]json.RawMessage `json:"content"`
+}
+
+var methods = map[string]string{
+	"get":    http.MethodGet,
+	"put":    http.MethodPut,
+	"post":   http.MethodPost,
+	"delete": http.MethodDelete,
+	"patch":  http.MethodPatch,
+}
+
+// Load compiles the embedded document.
+func Load() (*Document, error) {
+	var raw rawDocument
+	if err := json.Unmarshal(spec, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(spec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	if err := c.AddResource(resourceURL, instance); err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+	l := loader{compiler: c, raw: &raw}
+
+	d := &Document{}
+	for template, item := range raw.Paths {
+		rt := &route{
+			template: template,
+			segments: strings.Split(strings.Trim(template, "/"), "/"),
+			ops:      make(map[string]*operation),
+		}
+
+		var shared []rawParameter
+		if p, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(p, &shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters for %s: %w", template, err)
+			}
+		}
+
+		for key, value := range item {
+			method, ok := methods[key]
+			if !ok {
+				continue
+			}
+			var rawOp rawOperation
+			if err := json.Unmarshal(value, &rawOp); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", method, template, err)
+			}
+			op, err := l.operation(pointer("paths", template, key), pointer("paths", template), shared, rawOp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", method, template, err)
+			}
+			rt.ops[method] = op
+		}
+		d.routes = append(d.routes, rt)
+	}
+
+	// Templates with more literal segments come first, so that
+	// /api/v1/time/timer/start is not taken for a parameter.
+	sort.Slice(d.routes, func(i, j int) bool {
+		li, lj := literals(d.routes[i].segments), literals(d.routes[j].segments)
+		if li != lj {
+			return li > lj
+		}
+		return d.routes[i].template < d.routes[j].template
+	})
+
+	return d, nil
+}
+
+type loader struct {
+	compiler *jsonschema.Compiler
+	raw      *rawDocument
+}
+
+func (l loader) operation(opPtr, pathPtr string, shared []rawParameter, raw rawOperation) (*operation, error) {
+	op := &operation{responses: make(map[string]map[string]*jsonschema.Schema)}
+
+	for i, p := range shared {
+		param, err := l.parameter(pointer(pathPtr, "parameters", strconv.Itoa(i)), p)
+		if err != nil {
+			return nil, err
+		}
+		op.params = append(op.params, param)
+	}
+	for i, p := range raw.Parameters {
+		param, err := l.parameter(pointer(opPtr, "parameters", strconv.Itoa(i)), p)
+		if err != nil {
+			return nil, err
+		}
+		op.params = append(op.params, param)
+	}
+
+	if raw.RequestBody != nil {
+		if _, ok := raw.RequestBody.Content["application/json"]; ok {
+			schema, err := l.compiler.Compile(pointer(opPtr, "requestBody", "content", "application/json", "schema"))
+			if err != nil {
+				return nil, err
+			}
+			op.body = schema
+			op.bodyRequired = raw.RequestBody.Required
+		}
+	}
+
+	for status, resp := range raw.Responses {
+		ptr := pointer(opPtr, "responses", status)
+		if resp.Ref != "" {
+			name := strings.TrimPrefix(resp.Ref, "#/components/responses/")
+			resp = l.raw.Components.Responses[name]
+			ptr = pointer("components", "responses", name)
+		}
+		op.responses[status] = make(map[string]*jsonschema.Schema)
+		for mediaType := range resp.Content {
+			if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+				continue
+			}
+			schema, err := l.compiler.Compile(pointer(ptr, "content", mediaType, "schema"))
+			if err != nil {
+				return nil, err
+			}
+			op.responses[status][mediaType] = schema
+		}
+	}
+
+	return op, nil
+}
+
+func (l loader) parameter(ptr string, raw rawParameter) (*parameter, error) {
+	if raw.Ref != "" {
+		name := strings.TrimPrefix(raw.Ref, "#/components/parameters/")
+		p, ok := l.raw.Components.Parameters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %s", raw.Ref)
+		}
+		raw, ptr = p, pointer("components", "parameters", name)
+	}
+
+	schema, err := l.compiler.Compile(pointer(ptr, "schema"))
+	if err != nil {
+		return nil, err
+	}
+	return &parameter{
+		name:     raw.Name,
+		in:       raw.In,
+		required: raw.Required || raw.In == "path",
+		schema:   schema,
+		typ:      raw.Schema.Type,
+	}, nil
+}
+
+// pointer builds a schema location from JSON pointer tokens, or extends one
+// when the first token already is a location.
+func pointer(tokens ...string) string {
+	var sb strings.Builder
+	if strings.HasPrefix(tokens[0], resourceURL+"#") {
+		sb.WriteString(tokens[0])
+		tokens = tokens[1:]
+	} else {
+		sb.WriteString(resourceURL + "#")
+	}
+	for _, t := range tokens {
+		t = strings.ReplaceAll(t, "~", "~0")
+		t = strings.ReplaceAll(t, "/", "~1")
+		sb.WriteString("/" + t)
+	}
+	return sb.String()
+}
+
+func literals(segments []string) int {
+	n := 0
+	for _, s := range segments {
+		if !strings.HasPrefix(s, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+// find returns the operation for the request and its path parameters.
+func (d *Document) find(method, urlPath string) (*operation, map[string]string, error) {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	for _, rt := range d.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		op, ok := rt.ops[method]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s %s", ErrUnknownOperation, method, rt.template)
+		}
+		return op, params, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s %s", ErrUnknownOperation, method, urlPath)
+}
+
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range rt.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(s, "{}")] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// ValidateRequest checks the request's parameters and JSON body against its
+// operation. It reads the body and replaces it with a copy, so handlers can
+// still read it. Requests the document does not describe yield
+// ErrUnknownOperation.
+func (d *Document) ValidateRequest(r *http.Request) error {
+	op, pathParams, err := d.find(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	for _, p := range op.params {
+		var value string
+		var present bool
+		switch p.in {
+		case "path":
+			value, present = pathParams[p.name]
+		case "query":
+			present = query.Has(p.name)
+			value = query.Get(p.name)
+		case "header":
+			value = r.Header.Get(p.name)
+			present = value != ""
+		default:
+			continue
+		}
+		if !present {
+			if p.required {
+				return fmt.Errorf("%s parameter %s is required", p.in, p.name)
+			}
+			continue
+		}
+		if err := p.validate(value); err != nil {
+			return fmt.Errorf("%s parameter %s: %w", p.in, p.name, err)
+		}
+	}
+
+	if op.body == nil || r.Body == nil || binaryBody(r.Header.Get("Content-Type")) {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBodySize+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxValidatedBodySize {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.bodyRequired {
+			return fmt.Errorf("request body is required")
+		}
+		return nil
+	}
+	if err := validateJSON(op.body, body); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	return nil
+}
+
+// binaryMediaTypes are request encodings the handlers convert to JSON
+// themselves. The document describes only the JSON bodies, which the handlers
+// check after conversion.
+var binaryMediaTypes = []string{"application/msgpack", "application/x-protobuf"}
+
+func binaryBody(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && slices.Contains(binaryMediaTypes, mediaType)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ValidateResponse checks a response to r against the operation's
+// documented responses.
+func (d *Document) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	op, _, err := d.find(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	schemas, ok := op.responses[strconv.Itoa(status)]
+	if !ok {
+		schemas, ok = op.responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+	if len(schemas) == 0 {
+		if status == http.StatusNoContent && len(body) > 0 {
+			return fmt.Errorf("status 204 must not have a body")
+		}
+		return nil
+	}
+	ct := header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(ct)
+	schema, ok := schemas[mediaType]
+	if !ok {
+		return fmt.Errorf("expected a JSON response, got Content-Type %q", ct)
+	}
+	if err := validateJSON(schema, body); err != nil {
+		return fmt.Errorf("response body: %w", err)
+	}
+	return nil
+}
+
+func (p *parameter) validate(value string) error {
+	var v any = value
+	switch p.typ {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		v = json.Number(strconv.FormatInt(n, 10))
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		v = b
+	}
+	if err := p.schema.Validate(v); err != nil {
+		return describe(err)
+	}
+	return nil
+}
+
+func validateJSON(schema *jsonschema.Schema, body []byte) error {
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid JSON")
+	}
+	if err := schema.Validate(v); err != nil {
+		return describe(err)
+	}
+	return nil
+}
+
+var printer = message.NewPrinter(language.English)
+
+// describe turns a schema validation error into one line per failed
+// keyword, such as "/title: minLength: got 0, want 1".
+func describe(err error) error {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	var msgs []string
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			msg := e.ErrorKind.LocalizedString(printer)
+			if len(e.InstanceLocation) > 0 {
+				msg = "/" + strings.Join(e.InstanceLocation, "/") + ": " + msg
+			}
+			msgs = append(msgs, msg)
+			return
+		}
+		for _, c := range e.Causes {
+			walk(c)
+		}
+	}
+	walk(verr)
+	return errors.New(strings.Join(msgs, "; "))
+}
@@ -0,0 +1,156 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/openapi"
+)
+
+func mustLoad(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+	return doc
+}
+
+func TestSpec_IsOpenAPI31(t *testing.T) {
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(openapi.Spec(), &doc); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+}
+
+func TestDocument_ValidateRequest(t *testing.T) {
+	doc := mustLoad(t)
+	const todoID = "0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11"
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		header  map[string]string
+		wantErr string
+	}{
+		{"valid create", http.MethodPost, "/api/v1/todos", `{"title":"Buy milk","priority":"high"}`, nil, ""},
+		{"missing title", http.MethodPost, "/api/v1/todos", `{"description":"no title"}`, nil, "request body"},
+		{"empty title", http.MethodPost, "/api/v1/todos", `{"title":""}`, nil, "/title"},
+		{"invalid priority", http.MethodPost, "/api/v1/todos", `{"title":"a","priority":"someday"}`, nil, "/priority"},
+		{"wrong type", http.MethodPost, "/api/v1/todos", `{"title":"a","estimate_minutes":"ten"}`, nil, "/estimate_minutes"},
+		{"malformed JSON", http.MethodPost, "/api/v1/todos", `{"title":`, nil, "invalid JSON"},
+		{"missing body", http.MethodPost, "/api/v1/todos", ``, nil, "request body is required"},
+		{"valid list query", http.MethodGet, "/api/v1/todos?status=pending&limit=10&blocked=true", "", nil, ""},
+		{"invalid status query", http.MethodGet, "/api/v1/todos?status=done", "", nil, "query parameter status"},
+		{"non-integer limit", http.MethodGet, "/api/v1/todos?limit=ten", "", nil, "must be an integer"},
+		{"non-boolean blocked", http.MethodGet, "/api/v1/todos?blocked=maybe", "", nil, "must be true or false"},
+		{"path parameters", http.MethodDelete, "/api/v1/todos/" + todoID + "/blockers/" + todoID, "", nil, ""},
+		{"literal beats parameter", http.MethodPost, "/api/v1/time/timer/stop", "", nil, ""},
+		{"status patch", http.MethodPatch, "/api/v1/todos/" + todoID + "/status", `{"status":"completed"}`, nil, ""},
+		{"idempotency key too long", http.MethodPost, "/api/v1/todos", `{"title":"a"}`,
+			map[string]string{"Idempotency-Key": strings.Repeat("k", 256)}, "header parameter Idempotency-Key"},
+		{"msgpack body left to the handler", http.MethodPost, "/api/v1/todos", "\x81\xa5title\xa1a",
+			map[string]string{"Content-Type": "application/msgpack"}, ""},
+		{"other media types are validated", http.MethodPost, "/api/v1/todos", `{"title":""}`,
+			map[string]string{"Content-Type": "text/plain"}, "/title"},
+		{"unknown path", http.MethodGet, "/api/v1/nothing", "", nil, openapi.ErrUnknownOperation.Error()},
+		{"unknown method", http.MethodPatch, "/api/v1/todos", "", nil, openapi.ErrUnknownOperation.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			err := doc.ValidateRequest(req)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateRequest_UnknownOperation(t *testing.T) {
+	doc := mustLoad(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/nothing", nil)
+
+	if err := doc.ValidateRequest(req); !errors.Is(err, openapi.ErrUnknownOperation) {
+		t.Errorf("expected ErrUnknownOperation, got %v", err)
+	}
+}
+
+func TestDocument_ValidateRequest_KeepsBody(t *testing.T) {
+	doc := mustLoad(t)
+	body := `{"title":"Buy milk"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/todos", strings.NewReader(body))
+
+	if err := doc.ValidateRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("expected body %s to be readable again, got %s", body, got)
+	}
+}
+
+func TestDocument_ValidateResponse(t *testing.T) {
+	doc := mustLoad(t)
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+	todo := `{"id":"0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11","user_id":"u","title":"a","description":"",` +
+		`"status":"pending","priority":"none","important":false,"urgent":false,` +
+		`"created_at":"2026-01-02T03:04:05Z","updated_at":"2026-01-02T03:04:05Z"}`
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		status  int
+		header  http.Header
+		body    string
+		wantErr string
+	}{
+		{"created todo", http.MethodPost, "/api/v1/todos", http.StatusCreated, jsonHeader, todo, ""},
+		{"missing field", http.MethodPost, "/api/v1/todos", http.StatusCreated, jsonHeader, `{"id":"x"}`, "response body"},
+		{"error shape", http.MethodPost, "/api/v1/todos", http.StatusBadRequest, jsonHeader,
+			`{"error":{"code":"INVALID_INPUT","message":"title is required"}}`, ""},
+		{"bad error shape", http.MethodPost, "/api/v1/todos", http.StatusBadRequest, jsonHeader, `{"message":"nope"}`, "response body"},
+		{"undocumented status", http.MethodPost, "/api/v1/todos", http.StatusTeapot, jsonHeader, `{}`, "not documented"},
+		{"no content", http.MethodDelete, "/api/v1/todos/0b5e9c4e-8f2a-4a47-9d39-5a4f0d3c1e11", http.StatusNoContent, http.Header{}, "", ""},
+		{"not JSON", http.MethodGet, "/api/v1/todos", http.StatusOK, http.Header{"Content-Type": []string{"text/plain"}}, `{}`, "Content-Type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+
+			err := doc.ValidateResponse(req, tt.status, tt.header, []byte(tt.body))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package pb converts the domain model to the Protocol Buffers messages
+// generated from proto/ into internal/pb/todov1.
+package pb
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pb/todov1"
+)
+
+// FromTodo converts t to its Protocol Buffers message.
+func FromTodo(t model.Todo) *todov1.Todo {
+	msg := &todov1.Todo{
+		Id:          t.ID,
+		UserId:      t.UserID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      string(t.Status),
+		Priority:    string(t.Priority),
+		Important:   t.Important,
+		Urgent:      t.Urgent,
+		DueAt:       timestampOrNil(t.DueAt),
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		UpdatedAt:   timestamppb.New(t.UpdatedAt),
+		BlockedBy:   t.BlockedBy,
+		Blocking:    t.Blocking,
+	}
+	if t.EstimateMinutes != nil {
+		msg.EstimateMinutes = proto.Int32(int32(*t.EstimateMinutes))
+	}
+	if t.Owner != nil {
+		msg.Owner = &todov1.TodoOwner{
+			Id:              t.Owner.ID,
+			Email:           t.Owner.Email,
+			Nickname:        t.Owner.Nickname,
+			ProfileImageUrl: t.Owner.ProfileImageURL,
+		}
+	}
+	if t.Counts != nil {
+		msg.Counts = &todov1.TodoCounts{
+			BlockedBy:   int32(t.Counts.BlockedBy),
+			Blocking:    int32(t.Counts.Blocking),
+			TimeEntries: int32(t.Counts.TimeEntries),
+		}
+	}
+	return msg
+}
+
+// FromTodoList converts l to its Protocol Buffers message.
+func FromTodoList(l model.TodoListResult) *todov1.TodoList {
+	msg := &todov1.TodoList{Todos: make([]*todov1.Todo, len(l.Todos)), NextCursor: l.NextCursor}
+	for i, t := range l.Todos {
+		msg.Todos[i] = FromTodo(t)
+	}
+	return msg
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// TopicTodoEvents carries every todo domain event.
+const TopicTodoEvents = "todo_events"
+
+// TodoGetter reloads a todo whose snapshot was too large to publish.
+type TodoGetter interface {
+	GetByID(ctx context.Context, userID, todoID string) (model.Todo, error)
+}
+
+// EventBus publishes todo events on a PubSub so that subscribers on every
+// instance see them. It implements service.EventPublisher.
+type EventBus struct {
+	ps    PubSub
+	todos TodoGetter
+}
+
+// NewEventBus creates a new EventBus.
+func NewEventBus(ps PubSub, todos TodoGetter) *EventBus {
+	return &EventBus{ps: ps, todos: todos}
+}
+
+// envelope is the wire format. The todo snapshot is dropped when the event
+// would not fit in a message; subscribers then reload it.
+type envelope struct {
+	model.Event
+	Trimmed bool `json:"trimmed,omitempty"`
+}
+
+// Publish sends the event. Failures are logged rather than returned: the
+// write that raised the event has already been committed.
+func (b *EventBus) Publish(ctx context.Context, event model.Event) {
+	payload, err := encodeEvent(event)
+	if err == nil {
+		err = b.ps.Publish(ctx, TopicTodoEvents, payload)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish event", "event_id", event.ID, "type", event.Type, "error", err)
+	}
+}
+
+// Subscribe calls handler for every todo event, from any instance.
+func (b *EventBus) Subscribe(handler func(ctx context.Context, event model.Event)) (func(), error) {
+	return b.ps.Subscribe(TopicTodoEvents, func(ctx context.Context, payload []byte) {
+		event, err := b.decodeEvent(ctx, payload)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to decode event", "error", err)
+			return
+		}
+		handler(ctx, event)
+	})
+}
+
+func encodeEvent(event model.Event) ([]byte, error) {
+	payload, err := json.Marshal(envelope{Event: event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	if len(payload) <= MaxPayloadSize || event.Todo == nil {
+		return payload, nil
+	}
+
+	event.Todo = nil
+	return json.Marshal(envelope{Event: event, Trimmed: true})
+}
+
+func (b *EventBus) decodeEvent(ctx context.Context, payload []byte) (model.Event, error) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return model.Event{}, err
+	}
+	if !env.Trimmed || b.todos == nil {
+		return env.Event, nil
+	}
+
+	// The reloaded todo may already reflect later changes.
+	todo, err := b.todos.GetByID(ctx, env.UserID, env.TodoID)
+	if err != nil {
+		return model.Event{}, fmt.Errorf("failed to reload todo %s for event %s: %w", env.TodoID, env.ID, err)
+	}
+	env.Todo = &todo
+	return env.Event, nil
+}
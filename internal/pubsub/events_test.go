@@ -0,0 +1,99 @@
+package pubsub_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/pubsub"
+)
+
+type stubTodoGetter struct {
+	todo  model.Todo
+	err   error
+	calls int
+}
+
+func (s *stubTodoGetter) GetByID(ctx context.Context, userID, todoID string) (model.Todo, error) {
+	s.calls++
+	return s.todo, s.err
+}
+
+func sampleEvent(description string) model.Event {
+	todo := model.Todo{ID: "todo-1", UserID: "user-1", Title: "Write report", Description: description}
+	return model.Event{
+		ID:         "evt_1",
+		Type:       model.EventTodoUpdated,
+		UserID:     "user-1",
+		TodoID:     "todo-1",
+		Todo:       &todo,
+		OccurredAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func collect(t *testing.T, bus *pubsub.EventBus) *[]model.Event {
+	t.Helper()
+	var got []model.Event
+	if _, err := bus.Subscribe(func(ctx context.Context, e model.Event) {
+		got = append(got, e)
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &got
+}
+
+func TestEventBus_RoundTrip(t *testing.T) {
+	getter := &stubTodoGetter{}
+	bus := pubsub.NewEventBus(pubsub.NewMemory(), getter)
+	got := collect(t, bus)
+
+	bus.Publish(context.Background(), sampleEvent("short"))
+
+	if len(*got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(*got))
+	}
+	e := (*got)[0]
+	if e.ID != "evt_1" || e.Type != model.EventTodoUpdated || e.Todo == nil || e.Todo.Description != "short" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if !e.OccurredAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected occurred_at: %v", e.OccurredAt)
+	}
+	if getter.calls != 0 {
+		t.Errorf("expected no reload for small event, got %d", getter.calls)
+	}
+}
+
+func TestEventBus_OversizedTodoIsReloaded(t *testing.T) {
+	large := strings.Repeat("x", pubsub.MaxPayloadSize)
+
+	t.Run("reloaded", func(t *testing.T) {
+		getter := &stubTodoGetter{todo: model.Todo{ID: "todo-1", Description: large}}
+		bus := pubsub.NewEventBus(pubsub.NewMemory(), getter)
+		got := collect(t, bus)
+
+		bus.Publish(context.Background(), sampleEvent(large))
+
+		if len(*got) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(*got))
+		}
+		if getter.calls != 1 || (*got)[0].Todo == nil || (*got)[0].Todo.Description != large {
+			t.Errorf("expected todo reloaded, calls=%d todo=%v", getter.calls, (*got)[0].Todo != nil)
+		}
+	})
+
+	t.Run("reload fails", func(t *testing.T) {
+		getter := &stubTodoGetter{err: sql.ErrNoRows}
+		bus := pubsub.NewEventBus(pubsub.NewMemory(), getter)
+		got := collect(t, bus)
+
+		bus.Publish(context.Background(), sampleEvent(large))
+
+		if len(*got) != 0 {
+			t.Errorf("expected event to be dropped, got %d", len(*got))
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is an in-process PubSub for single-instance deployments and tests.
+// Publish delivers synchronously, in the caller's goroutine.
+type Memory struct {
+	mu     sync.Mutex
+	subs   subscribers
+	closed bool
+}
+
+// NewMemory creates a new Memory.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(ctx context.Context, topic string, payload []byte) error {
+	if err := validate(topic, payload); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+	handlers := m.subs.get(topic)
+	m.mu.Unlock()
+
+	// Handlers outlive the publishing request on other backends; keep the
+	// same semantics here.
+	ctx = context.WithoutCancel(ctx)
+	for _, h := range handlers {
+		h(ctx, payload)
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(topic string, handler Handler) (func(), error) {
+	if err := validate(topic, nil); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	id, _ := m.subs.add(topic, handler)
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.subs.remove(topic, id)
+	}, nil
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.subs = subscribers{}
+	return nil
+}
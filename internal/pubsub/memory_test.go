@@ -0,0 +1,89 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/pubsub"
+)
+
+func TestMemory_PublishSubscribe(t *testing.T) {
+	m := pubsub.NewMemory()
+	defer m.Close()
+
+	var got []string
+	unsubscribe, err := m.Subscribe("todo_events", func(ctx context.Context, payload []byte) {
+		got = append(got, string(payload))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = m.Subscribe("other", func(ctx context.Context, payload []byte) {
+		t.Errorf("unexpected delivery to other topic: %s", payload)
+	})
+
+	_ = m.Publish(context.Background(), "todo_events", []byte("one"))
+	unsubscribe()
+	unsubscribe() // idempotent
+	_ = m.Publish(context.Background(), "todo_events", []byte("two"))
+
+	if len(got) != 1 || got[0] != "one" {
+		t.Errorf("expected [one], got %v", got)
+	}
+}
+
+func TestMemory_HandlerContextOutlivesPublisher(t *testing.T) {
+	m := pubsub.NewMemory()
+	defer m.Close()
+
+	var handlerErr error
+	_, _ = m.Subscribe("todo_events", func(ctx context.Context, payload []byte) {
+		handlerErr = ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = m.Publish(ctx, "todo_events", []byte("x"))
+
+	if handlerErr != nil {
+		t.Errorf("expected handler context to be live, got %v", handlerErr)
+	}
+}
+
+func TestMemory_Validation(t *testing.T) {
+	m := pubsub.NewMemory()
+
+	tests := []struct {
+		name    string
+		topic   string
+		payload []byte
+		wantErr error
+	}{
+		{"valid", "todo_events", []byte("{}"), nil},
+		{"max size", "todo_events", make([]byte, pubsub.MaxPayloadSize), nil},
+		{"too large", "todo_events", make([]byte, pubsub.MaxPayloadSize+1), pubsub.ErrPayloadTooLarge},
+		{"empty topic", "", nil, pubsub.ErrInvalidTopic},
+		{"uppercase topic", "TodoEvents", nil, pubsub.ErrInvalidTopic},
+		{"topic with space", "todo events", nil, pubsub.ErrInvalidTopic},
+		{"topic too long", strings.Repeat("a", 64), nil, pubsub.ErrInvalidTopic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Publish(context.Background(), tt.topic, tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	m.Close()
+	if err := m.Publish(context.Background(), "todo_events", nil); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("expected ErrClosed after close, got %v", err)
+	}
+	if _, err := m.Subscribe("todo_events", func(context.Context, []byte) {}); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("expected ErrClosed subscribing after close, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	// listenerPingInterval is how long the listener may sit idle before its
+	// connection is pinged, so a silently dropped connection is noticed and
+	// re-established rather than waiting forever.
+	listenerPingInterval = 90 * time.Second
+)
+
+// Postgres is a PubSub over Postgres LISTEN/NOTIFY. Every instance connected
+// to the same database receives every message, including its own.
+//
+// Publishing uses the shared *sql.DB; listening holds one dedicated
+// connection that is re-established with backoff after it drops. LISTEN is
+// re-issued for every subscribed topic on reconnect, but notifications sent
+// while disconnected are lost.
+type Postgres struct {
+	db       *sql.DB
+	listener *pq.Listener
+	logger   *slog.Logger
+
+	// listenMu serialises LISTEN/UNLISTEN with subscriber changes. It is
+	// separate from mu because those calls wait on the listener connection,
+	// which may itself be waiting for run to dispatch a notification.
+	listenMu sync.Mutex
+	mu       sync.Mutex
+	subs     subscribers
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPostgres creates a Postgres PubSub. dsn must point at the same database
+// as db; it is used for the dedicated listener connection.
+func NewPostgres(db *sql.DB, dsn string, logger *slog.Logger) *Postgres {
+	p := &Postgres{
+		db:     db,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.listener = pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, p.onListenerEvent)
+
+	go p.run()
+	return p
+}
+
+func (p *Postgres) Publish(ctx context.Context, topic string, payload []byte) error {
+	if err := validate(topic, payload); err != nil {
+		return err
+	}
+	if p.ctx.Err() != nil {
+		return ErrClosed
+	}
+
+	if _, err := p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", topic, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Subscribe starts listening on topic if this is its first subscriber. It
+// blocks until Postgres acknowledges the LISTEN.
+func (p *Postgres) Subscribe(topic string, handler Handler) (func(), error) {
+	if err := validate(topic, nil); err != nil {
+		return nil, err
+	}
+
+	p.listenMu.Lock()
+	defer p.listenMu.Unlock()
+	if p.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+
+	p.mu.Lock()
+	id, first := p.subs.add(topic, handler)
+	p.mu.Unlock()
+
+	if first {
+		if err := p.listener.Listen(topic); err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			p.mu.Lock()
+			p.subs.remove(topic, id)
+			p.mu.Unlock()
+			return nil, fmt.Errorf("failed to listen on %s: %w", topic, err)
+		}
+	}
+
+	return func() {
+		p.listenMu.Lock()
+		defer p.listenMu.Unlock()
+
+		p.mu.Lock()
+		last := p.subs.remove(topic, id)
+		p.mu.Unlock()
+
+		if last && p.ctx.Err() == nil {
+			if err := p.listener.Unlisten(topic); err != nil && !errors.Is(err, pq.ErrChannelNotOpen) {
+				p.logger.Warn("failed to unlisten", "topic", topic, "error", err)
+			}
+		}
+	}, nil
+}
+
+// Close stops listening and waits for in-flight handlers to return.
+func (p *Postgres) Close() error {
+	p.cancel()
+	err := p.listener.Close()
+	<-p.done
+	return err
+}
+
+func (p *Postgres) run() {
+	defer close(p.done)
+
+	ping := time.NewTimer(listenerPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case n, ok := <-p.listener.Notify:
+			if !ok {
+				return
+			}
+			// A nil notification follows a reconnect.
+			if n != nil {
+				p.dispatch(n)
+			}
+		case <-ping.C:
+			go func() {
+				if err := p.listener.Ping(); err != nil {
+					p.logger.Warn("pubsub listener ping failed", "error", err)
+				}
+			}()
+		}
+		ping.Reset(listenerPingInterval)
+	}
+}
+
+func (p *Postgres) dispatch(n *pq.Notification) {
+	p.mu.Lock()
+	handlers := p.subs.get(n.Channel)
+	p.mu.Unlock()
+
+	payload := []byte(n.Extra)
+	for _, h := range handlers {
+		h(p.ctx, payload)
+	}
+}
+
+func (p *Postgres) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		p.logger.Warn("pubsub listener disconnected", "error", err)
+	case pq.ListenerEventReconnected:
+		p.logger.Warn("pubsub listener reconnected; notifications sent while disconnected were lost")
+	case pq.ListenerEventConnectionAttemptFailed:
+		p.logger.Error("pubsub listener reconnect failed", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Package pubsub delivers messages between subsystems and, with the Postgres
+// backend, between every instance of the API sharing a database.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// MaxPayloadSize is the largest payload accepted by Publish. Postgres rejects
+// NOTIFY payloads of 8000 bytes or more; every backend enforces the same
+// limit so code that works locally keeps working in production.
+const MaxPayloadSize = 7999
+
+var (
+	ErrPayloadTooLarge = errors.New("pubsub: payload too large")
+	ErrInvalidTopic    = errors.New("pubsub: invalid topic")
+	ErrClosed          = errors.New("pubsub: closed")
+)
+
+// Handler receives a published payload. Handlers run on the backend's
+// delivery goroutine and must not block for long.
+type Handler func(ctx context.Context, payload []byte)
+
+// PubSub is a topic-based, at-most-once message bus. Subscribers only see
+// messages published after they subscribed, and messages published while a
+// backend is reconnecting may be lost.
+type PubSub interface {
+	// Publish sends payload to every subscriber of topic, including those in
+	// the publishing process.
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe calls handler for each message on topic until the returned
+	// function is called.
+	Subscribe(topic string, handler Handler) (unsubscribe func(), err error)
+	Close() error
+}
+
+// Topics double as Postgres channel names, so they are limited to lowercase
+// identifiers within Postgres's 63 byte limit.
+var topicPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+func validate(topic string, payload []byte) error {
+	if !topicPattern.MatchString(topic) {
+		return fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+	}
+	if len(payload) > MaxPayloadSize {
+		return fmt.Errorf("%w: %d bytes exceeds %d", ErrPayloadTooLarge, len(payload), MaxPayloadSize)
+	}
+	return nil
+}
+
+// subscribers tracks handlers per topic for the backends.
+type subscribers struct {
+	nextID   int
+	handlers map[string]map[int]Handler
+}
+
+func (s *subscribers) add(topic string, h Handler) (id int, first bool) {
+	if s.handlers == nil {
+		s.handlers = make(map[string]map[int]Handler)
+	}
+	if s.handlers[topic] == nil {
+		s.handlers[topic] = make(map[int]Handler)
+		first = true
+	}
+	s.nextID++
+	s.handlers[topic][s.nextID] = h
+	return s.nextID, first
+}
+
+func (s *subscribers) remove(topic string, id int) (last bool) {
+	hs, ok := s.handlers[topic]
+	if !ok {
+		return false
+	}
+	if _, ok := hs[id]; !ok {
+		return false
+	}
+	delete(hs, id)
+	if len(hs) == 0 {
+		delete(s.handlers, topic)
+		return true
+	}
+	return false
+}
+
+func (s *subscribers) get(topic string) []Handler {
+	hs := make([]Handler, 0, len(s.handlers[topic]))
+	for _, h := range s.handlers[topic] {
+		hs = append(hs, h)
+	}
+	return hs
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type TodoDependencyRepository interface {
+	// Add records dep unless check, given the user's existing dependencies,
+	// returns an error. The check and the insert are atomic per user.
+	Add(ctx context.Context, userID string, dep model.TodoDependency, check func([]model.TodoDependency) error) error
+	Remove(ctx context.Context, userID string, dep model.TodoDependency) error
+	ListBlockers(ctx context.Context, userID, todoID string) ([]model.Todo, error)
+	ListBlocking(ctx context.Context, userID, todoID string) ([]model.Todo, error)
+	ListEdges(ctx context.Context, userID string) ([]model.TodoDependency, error)
+	ListNodes(ctx context.Context, userID string) ([]model.Todo, error)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type PostgresTodoDependencyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTodoDependency(db *sql.DB) *PostgresTodoDependencyRepository {
+	return &PostgresTodoDependencyRepository{db: db}
+}
+
+// Add holds a per-user advisory lock for the transaction, so concurrent
+// adds for the same user are checked against each other's edges.
+func (r *PostgresTodoDependencyRepository) Add(ctx context.Context, userID string, dep model.TodoDependency, check func([]model.TodoDependency) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('todo_dependencies'), hashtext($1))`, userID); err != nil {
+		return fmt.Errorf("failed to lock todo dependencies: %w", err)
+	}
+
+	edges, err := listEdges(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if err := check(edges); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO todo_dependencies (todo_id, blocker_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (todo_id, blocker_id) DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, query, dep.TodoID, dep.BlockerID, userID); err != nil {
+		return fmt.Errorf("failed to add todo dependency: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit todo dependency: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTodoDependencyRepository) Remove(ctx context.Context, userID string, dep model.TodoDependency) error {
+	query := `DELETE FROM todo_dependencies WHERE todo_id = $1 AND blocker_id = $2 AND user_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, dep.TodoID, dep.BlockerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove todo dependency: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresTodoDependencyRepository) ListBlockers(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	query := `
+		SELECT ` + prefixedTodoColumns("t") + `
+		FROM todo_dependencies d
+		JOIN todos t ON t.id = d.blocker_id
+		WHERE d.todo_id = $1 AND d.user_id = $2
+		ORDER BY t.created_at`
+
+	return r.queryTodos(ctx, query, todoID, userID)
+}
+
+func (r *PostgresTodoDependencyRepository) ListBlocking(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	query := `
+		SELECT ` + prefixedTodoColumns("t") + `
+		FROM todo_dependencies d
+		JOIN todos t ON t.id = d.todo_id
+		WHERE d.blocker_id = $1 AND d.user_id = $2
+		ORDER BY t.created_at`
+
+	return r.queryTodos(ctx, query, todoID, userID)
+}
+
+func (r *PostgresTodoDependencyRepository) ListEdges(ctx context.Context, userID string) ([]model.TodoDependency, error) {
+	return listEdges(ctx, r.db, userID)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func listEdges(ctx context.Context, q queryer, userID string) ([]model.TodoDependency, error) {
+	query := `SELECT todo_id, blocker_id FROM todo_dependencies WHERE user_id = $1`
+
+	rows, err := q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list todo dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []model.TodoDependency
+	for rows.Next() {
+		var d model.TodoDependency
+		if err := rows.Scan(&d.TodoID, &d.BlockerID); err != nil {
+			return nil, fmt.Errorf("failed to scan todo dependency: %w", err)
+		}
+		deps = append(deps, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate todo dependencies: %w", err)
+	}
+
+	return deps, nil
+}
+
+// ListNodes returns every todo that appears on either side of a dependency.
+func (r *PostgresTodoDependencyRepository) ListNodes(ctx context.Context, userID string) ([]model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE user_id = $1 AND id IN (
+			SELECT todo_id FROM todo_dependencies WHERE user_id = $1
+			UNION
+			SELECT blocker_id FROM todo_dependencies WHERE user_id = $1
+		)
+		ORDER BY created_at`
+
+	return r.queryTodos(ctx, query, userID)
+}
+
+func (r *PostgresTodoDependencyRepository) queryTodos(ctx context.Context, query string, args ...any) ([]model.Todo, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query todos: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []model.Todo
+	for rows.Next() {
+		todo, err := scanTodoFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+var _ TodoDependencyRepository = (*PostgresTodoDependencyRepository)(nil)
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type IdempotencyRepository interface {
+	// Claim reserves scope/key for a new request, taking over an expired key
+	// or an in-flight one whose lock has lapsed. When the key is held it
+	// returns the existing record and claimed is false.
+	Claim(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (record model.IdempotencyRecord, claimed bool, err error)
+	// Complete stores the response for a claimed key.
+	Complete(ctx context.Context, record model.IdempotencyRecord) error
+	// Release drops a claimed key so the request can be retried from scratch.
+	Release(ctx context.Context, scope, key string) error
+	// DeleteExpired removes expired keys and returns how many were removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
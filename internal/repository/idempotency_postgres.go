@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresIdempotency(db *sql.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+func (r *PostgresIdempotencyRepository) Claim(ctx context.Context, scope, key, requestHash string, lock, ttl time.Duration) (model.IdempotencyRecord, bool, error) {
+	claim := `
+		INSERT INTO idempotency_keys (scope, key, request_hash, locked_until, expires_at)
+		VALUES ($1, $2, $3, now() + $4 * interval '1 millisecond', now() + $5 * interval '1 millisecond')
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+		    status_code = NULL, response_headers = NULL, response_body = NULL,
+		    locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at, created_at = now()
+		WHERE idempotency_keys.expires_at < now()
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until < now())
+		RETURNING scope`
+
+	// A held key can be released or expire between the two statements, so
+	// try the claim once more if the record has vanished.
+	for attempt := 0; ; attempt++ {
+		var claimedScope string
+		err := r.db.QueryRowContext(ctx, claim, scope, key, requestHash, lock.Milliseconds(), ttl.Milliseconds()).Scan(&claimedScope)
+		if err == nil {
+			return model.IdempotencyRecord{Scope: scope, Key: key, RequestHash: requestHash}, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return model.IdempotencyRecord{}, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+
+		record, err := r.get(ctx, scope, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			if attempt == 0 {
+				continue
+			}
+			return model.IdempotencyRecord{}, false, fmt.Errorf("idempotency key changed while claiming: %w", err)
+		}
+		return record, false, err
+	}
+}
+
+func (r *PostgresIdempotencyRepository) get(ctx context.Context, scope, key string) (model.IdempotencyRecord, error) {
+	query := `
+		SELECT request_hash, status_code, response_headers, response_body
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2`
+
+	record := model.IdempotencyRecord{Scope: scope, Key: key}
+	var status sql.NullInt32
+	var header []byte
+	err := r.db.QueryRowContext(ctx, query, scope, key).Scan(&record.RequestHash, &status, &header, &record.Body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.IdempotencyRecord{}, err
+	}
+	if err != nil {
+		return model.IdempotencyRecord{}, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	if status.Valid {
+		record.StatusCode = int(status.Int32)
+	}
+	if header != nil {
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return model.IdempotencyRecord{}, fmt.Errorf("failed to decode stored headers: %w", err)
+		}
+	}
+	return record, nil
+}
+
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, record model.IdempotencyRecord) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, response_headers = $2, response_body = $3
+		WHERE scope = $4 AND key = $5`
+
+	if _, err := r.db.ExecContext(ctx, query, record.StatusCode, header, record.Body, record.Scope, record.Key); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresIdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+var _ IdempotencyRepository = (*PostgresIdempotencyRepository)(nil)
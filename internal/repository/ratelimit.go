@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type RateLimitRepository interface {
+	// Take takes one token from the bucket for key, creating a full bucket
+	// if there is none.
+	Take(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitDecision, error)
+	// DeleteExpired removes buckets that have refilled and returns how many
+	// were removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
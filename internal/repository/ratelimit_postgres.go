@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type PostgresRateLimitRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRateLimit(db *sql.DB) *PostgresRateLimitRepository {
+	return &PostgresRateLimitRepository{db: db}
+}
+
+// Take locks the bucket row for the refill so concurrent requests on any
+// instance take tokens one at a time. Times come from the database clock so
+// instances with skewed clocks agree.
+func (r *PostgresRateLimitRepository) Take(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitDecision, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.RateLimitDecision{}, fmt.Errorf("failed to begin rate limit: %w", err)
+	}
+	defer tx.Rollback()
+
+	create := `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+		VALUES ($1, $2, now(), now())
+		ON CONFLICT (key) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, create, key, limit.Limit); err != nil {
+		return model.RateLimitDecision{}, fmt.Errorf("failed to create rate limit bucket: %w", err)
+	}
+
+	var bucket model.TokenBucket
+	var now time.Time
+	query := `
+		SELECT tokens, updated_at, now()
+		FROM rate_limit_buckets
+		WHERE key = $1
+		FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&bucket.Tokens, &bucket.UpdatedAt, &now); err != nil {
+		return model.RateLimitDecision{}, fmt.Errorf("failed to get rate limit bucket: %w", err)
+	}
+
+	bucket, decision := limit.Take(bucket, now)
+
+	update := `
+		UPDATE rate_limit_buckets
+		SET tokens = $1, updated_at = $2, expires_at = $2 + $3 * interval '1 millisecond'
+		WHERE key = $4`
+	if _, err := tx.ExecContext(ctx, update, bucket.Tokens, bucket.UpdatedAt, decision.Reset.Milliseconds(), key); err != nil {
+		return model.RateLimitDecision{}, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.RateLimitDecision{}, fmt.Errorf("failed to commit rate limit: %w", err)
+	}
+	return decision, nil
+}
+
+func (r *PostgresRateLimitRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE expires_at < now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit buckets: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+var _ RateLimitRepository = (*PostgresRateLimitRepository)(nil)
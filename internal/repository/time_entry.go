@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// ErrTimerRunning is returned when a user already has a running timer.
+var ErrTimerRunning = errors.New("timer already running")
+
+type TimeEntryRepository interface {
+	Create(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error)
+	GetRunning(ctx context.Context, userID string) (model.TimeEntry, error)
+	Stop(ctx context.Context, userID, entryID string, endedAt time.Time) (model.TimeEntry, error)
+	Delete(ctx context.Context, userID, entryID string) error
+	// List returns the entries that overlap [from, to).
+	List(ctx context.Context, userID, todoID string, from, to time.Time) ([]model.TimeEntry, error)
+	// StopExpired stops every timer that has run longer than maxDuration,
+	// ending it at started_at + maxDuration. It returns the number stopped.
+	StopExpired(ctx context.Context, maxDuration time.Duration) (int64, error)
+	// ReportByDay totals the time within [from, to) per calendar day in loc,
+	// splitting entries that cross midnight or a bound of the range.
+	ReportByDay(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error)
+	// ReportByTodo totals the time within [from, to) per todo.
+	ReportByTodo(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error)
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+const timeEntryColumns = `id, user_id, todo_id, note, started_at, ended_at, created_at`
+
+// overlapsRange selects the entries that overlap [$2, $3), including those
+// that start before it; running timers count up to now().
+const overlapsRange = `e.started_at < $3 AND COALESCE(e.ended_at, now()) > $2`
+
+// trackedSeconds is the length in seconds of the part of an entry within
+// [$2, $3).
+const trackedSeconds = `EXTRACT(EPOCH FROM (LEAST(COALESCE(e.ended_at, now()), $3) - GREATEST(e.started_at, $2)))`
+
+type PostgresTimeEntryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTimeEntry(db *sql.DB) *PostgresTimeEntryRepository {
+	return &PostgresTimeEntryRepository{db: db}
+}
+
+func (r *PostgresTimeEntryRepository) Create(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error) {
+	query := `
+		INSERT INTO time_entries (user_id, todo_id, note, started_at, ended_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + timeEntryColumns
+
+	row := r.db.QueryRowContext(ctx, query,
+		entry.UserID, entry.TodoID, entry.Note, entry.StartedAt, entry.EndedAt,
+	)
+
+	created, err := scanTimeEntry(row)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.TimeEntry{}, ErrTimerRunning
+		}
+		return model.TimeEntry{}, err
+	}
+	return created, nil
+}
+
+func (r *PostgresTimeEntryRepository) GetRunning(ctx context.Context, userID string) (model.TimeEntry, error) {
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries
+		WHERE user_id = $1 AND ended_at IS NULL`
+
+	row := r.db.QueryRowContext(ctx, query, userID)
+	return scanTimeEntry(row)
+}
+
+func (r *PostgresTimeEntryRepository) Stop(ctx context.Context, userID, entryID string, endedAt time.Time) (model.TimeEntry, error) {
+	query := `
+		UPDATE time_entries
+		SET ended_at = GREATEST($1, started_at)
+		WHERE id = $2 AND user_id = $3 AND ended_at IS NULL
+		RETURNING ` + timeEntryColumns
+
+	row := r.db.QueryRowContext(ctx, query, endedAt, entryID, userID)
+	return scanTimeEntry(row)
+}
+
+func (r *PostgresTimeEntryRepository) Delete(ctx context.Context, userID, entryID string) error {
+	query := `DELETE FROM time_entries WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, entryID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresTimeEntryRepository) List(ctx context.Context, userID, todoID string, from, to time.Time) ([]model.TimeEntry, error) {
+	args := []any{userID, from, to}
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries e
+		WHERE e.user_id = $1 AND ` + overlapsRange
+
+	if todoID != "" {
+		query += " AND todo_id = $4"
+		args = append(args, todoID)
+	}
+	query += " ORDER BY started_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.TimeEntry{}
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *PostgresTimeEntryRepository) StopExpired(ctx context.Context, maxDuration time.Duration) (int64, error) {
+	query := `
+		UPDATE time_entries
+		SET ended_at = started_at + make_interval(secs => $1)
+		WHERE ended_at IS NULL AND started_at < now() - make_interval(secs => $1)`
+
+	result, err := r.db.ExecContext(ctx, query, maxDuration.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to stop expired timers: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *PostgresTimeEntryRepository) ReportByDay(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error) {
+	// Each entry is clamped to the range, then joined with the local days it
+	// overlaps and contributes the part of its span that falls within each one.
+	query := `
+		SELECT to_char(d.day, 'YYYY-MM-DD'),
+		       COALESCE(SUM(EXTRACT(EPOCH FROM LEAST(e.ended, d.next) - GREATEST(e.started, d.start))), 0)::BIGINT / 60
+		FROM (
+			SELECT GREATEST(e.started_at, $2) AS started, LEAST(COALESCE(e.ended_at, now()), $3) AS ended
+			FROM time_entries e
+			WHERE e.user_id = $1 AND ` + overlapsRange + `
+		) e
+		CROSS JOIN LATERAL (
+			SELECT day, day AT TIME ZONE $4 AS start, (day + interval '1 day') AT TIME ZONE $4 AS next
+			FROM generate_series(date_trunc('day', e.started AT TIME ZONE $4), e.ended AT TIME ZONE $4, interval '1 day') AS day
+		) d
+		WHERE LEAST(e.ended, d.next) > GREATEST(e.started, d.start)
+		GROUP BY d.day
+		ORDER BY d.day`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to, loc.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to report time by day: %w", err)
+	}
+	defer rows.Close()
+
+	days := []model.DailyTime{}
+	for rows.Next() {
+		var d model.DailyTime
+		if err := rows.Scan(&d.Date, &d.TrackedMinutes); err != nil {
+			return nil, fmt.Errorf("failed to scan daily time: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate daily time: %w", err)
+	}
+
+	return days, nil
+}
+
+func (r *PostgresTimeEntryRepository) ReportByTodo(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error) {
+	query := `
+		SELECT t.id, t.title, t.estimate_minutes,
+		       COALESCE(SUM(` + trackedSeconds + `), 0)::BIGINT / 60 AS tracked
+		FROM time_entries e
+		JOIN todos t ON t.id = e.todo_id
+		WHERE e.user_id = $1 AND ` + overlapsRange + `
+		GROUP BY t.id, t.title, t.estimate_minutes
+		ORDER BY tracked DESC, t.title`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report time by todo: %w", err)
+	}
+	defer rows.Close()
+
+	todos := []model.TodoTime{}
+	for rows.Next() {
+		var tt model.TodoTime
+		if err := rows.Scan(&tt.TodoID, &tt.Title, &tt.EstimateMinutes, &tt.TrackedMinutes); err != nil {
+			return nil, fmt.Errorf("failed to scan todo time: %w", err)
+		}
+		todos = append(todos, tt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate todo time: %w", err)
+	}
+
+	return todos, nil
+}
+
+func scanTimeEntry(row scannable) (model.TimeEntry, error) {
+	var e model.TimeEntry
+	err := row.Scan(
+		&e.ID, &e.UserID, &e.TodoID, &e.Note,
+		&e.StartedAt, &e.EndedAt, &e.CreatedAt,
+	)
+	if err != nil {
+		return model.TimeEntry{}, fmt.Errorf("failed to scan time entry: %w", err)
+	}
+	return e, nil
+}
+
+var _ TimeEntryRepository = (*PostgresTimeEntryRepository)(nil)
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jaekwang-park/todo-api/internal/model"
 )
@@ -9,7 +10,20 @@ import (
 type TodoRepository interface {
 	Create(ctx context.Context, todo model.Todo) (model.Todo, error)
 	GetByID(ctx context.Context, userID, todoID string) (model.Todo, error)
+	// Update saves todo. The times in todo.FieldsModifiedAt are merged into
+	// the stored ones.
 	Update(ctx context.Context, todo model.Todo) (model.Todo, error)
+	// UpdateIfUnchanged is Update, made only while the stored updated_at
+	// still equals todo.UpdatedAt. It returns sql.ErrNoRows if the todo has
+	// changed since or is gone.
+	UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error)
 	Delete(ctx context.Context, userID, todoID string) error
+	// DeleteIfUnchanged is Delete, made only while the stored updated_at
+	// still equals updatedAt. It returns sql.ErrNoRows if the todo has
+	// changed since or is gone.
+	DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error
 	List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error)
+	// ListByIDs returns the user's todos with the given IDs, in no
+	// particular order. IDs that match no todo are left out.
+	ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error)
 }
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// ErrSyncPositionPruned is returned when tombstones a sync position still
+// needed have been pruned, so the feed can no longer report every deletion
+// since it.
+var ErrSyncPositionPruned = errors.New("sync position predates pruned tombstones")
+
+// TodoChangeRepository reads a user's todo change feed for delta sync.
+type TodoChangeRepository interface {
+	// Changes returns up to limit todos and tombstones changed after since,
+	// in change order. The returned Next position resumes the feed.
+	Changes(ctx context.Context, userID string, since model.SyncPosition, limit int) (model.TodoChanges, error)
+	// PruneTombstones deletes tombstones for todos deleted before cutoff and
+	// returns how many were deleted. Changes then returns
+	// ErrSyncPositionPruned for positions that needed them.
+	PruneTombstones(ctx context.Context, cutoff time.Time) (int64, error)
+}
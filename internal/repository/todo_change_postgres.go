@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// change is one entry of the merged todo and tombstone feed.
+type change struct {
+	seq       uint64
+	id        string
+	todo      *model.Todo
+	tombstone *model.Tombstone
+}
+
+func (c change) before(o change) bool {
+	if c.seq != o.seq {
+		return c.seq < o.seq
+	}
+	return c.id < o.id
+}
+
+// Changes reads the feed inside a read-only repeatable-read transaction so
+// the horizon and the rows come from the same snapshot. Only changes below
+// the horizon, the oldest transaction still running, are returned; anything
+// at or above it may yet be joined by a change that commits later.
+func (r *PostgresTodoRepository) Changes(ctx context.Context, userID string, since model.SyncPosition, limit int) (model.TodoChanges, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return model.TodoChanges{}, fmt.Errorf("failed to begin sync read: %w", err)
+	}
+	defer tx.Rollback()
+
+	var horizonText string
+	if err := tx.QueryRowContext(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text`).Scan(&horizonText); err != nil {
+		return model.TodoChanges{}, fmt.Errorf("failed to read sync horizon: %w", err)
+	}
+	horizon, err := strconv.ParseUint(horizonText, 10, 64)
+	if err != nil {
+		return model.TodoChanges{}, fmt.Errorf("failed to parse sync horizon %q: %w", horizonText, err)
+	}
+
+	todos, err := r.changedTodos(ctx, tx, userID, since, horizon, limit+1)
+	if err != nil {
+		return model.TodoChanges{}, err
+	}
+
+	// A client syncing from scratch has nothing to delete.
+	var tombstones []change
+	if !since.IsZero() {
+		if err := checkSyncFloor(ctx, tx, userID, since); err != nil {
+			return model.TodoChanges{}, err
+		}
+		tombstones, err = r.changedTombstones(ctx, tx, userID, since, horizon, limit+1)
+		if err != nil {
+			return model.TodoChanges{}, err
+		}
+	}
+
+	// Merge the two ordered lists, keeping the first limit changes.
+	merged := make([]change, 0, limit+1)
+	for len(merged) <= limit && (len(todos) > 0 || len(tombstones) > 0) {
+		if len(tombstones) == 0 || (len(todos) > 0 && todos[0].before(tombstones[0])) {
+			merged = append(merged, todos[0])
+			todos = todos[1:]
+		} else {
+			merged = append(merged, tombstones[0])
+			tombstones = tombstones[1:]
+		}
+	}
+
+	result := model.TodoChanges{
+		Todos:   []model.Todo{},
+		Deleted: []model.Tombstone{},
+		Next:    model.SyncPosition{Seq: max(horizon, since.Seq)},
+	}
+	if len(merged) > limit {
+		merged = merged[:limit]
+		last := merged[limit-1]
+		result.HasMore = true
+		result.Next = model.SyncPosition{Seq: last.seq, TodoID: last.id}
+	}
+	for _, c := range merged {
+		if c.todo != nil {
+			result.Todos = append(result.Todos, *c.todo)
+		} else {
+			result.Deleted = append(result.Deleted, *c.tombstone)
+		}
+	}
+
+	return result, nil
+}
+
+// checkSyncFloor returns ErrSyncPositionPruned if tombstones at or after
+// since have been pruned.
+func checkSyncFloor(ctx context.Context, tx *sql.Tx, userID string, since model.SyncPosition) error {
+	var prunedText string
+	err := tx.QueryRowContext(ctx, `SELECT pruned_seq::text FROM todo_sync_floors WHERE user_id = $1`, userID).Scan(&prunedText)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read sync floor: %w", err)
+	}
+	pruned, err := strconv.ParseUint(prunedText, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse pruned_seq %q: %w", prunedText, err)
+	}
+	if since.Seq <= pruned {
+		return ErrSyncPositionPruned
+	}
+	return nil
+}
+
+// PruneTombstones deletes old tombstones and raises each affected user's
+// sync floor to the newest change_seq deleted, in one statement so a
+// concurrent sync sees either both or neither.
+func (r *PostgresTodoRepository) PruneTombstones(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		WITH pruned AS (
+			DELETE FROM todo_tombstones
+			WHERE deleted_at < $1
+			RETURNING user_id, change_seq
+		), floors AS (
+			INSERT INTO todo_sync_floors (user_id, pruned_seq)
+			SELECT user_id, max(change_seq) FROM pruned GROUP BY user_id
+			ON CONFLICT (user_id) DO UPDATE
+				SET pruned_seq = GREATEST(todo_sync_floors.pruned_seq, EXCLUDED.pruned_seq)
+		)
+		SELECT count(*) FROM pruned`
+
+	var n int64
+	if err := r.db.QueryRowContext(ctx, query, cutoff).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to prune tombstones: %w", err)
+	}
+	return n, nil
+}
+
+// changeRange returns the WHERE clause selecting changes after since and
+// below horizon, with its arguments numbered from $2.
+func changeRange(idColumn string, since model.SyncPosition, horizon uint64) (string, []any) {
+	args := []any{strconv.FormatUint(horizon, 10), strconv.FormatUint(since.Seq, 10)}
+	clause := " AND change_seq < $2::xid8"
+	if since.TodoID == "" {
+		clause += " AND change_seq >= $3::xid8"
+	} else {
+		clause += fmt.Sprintf(" AND (change_seq, %s) > ($3::xid8, $4::uuid)", idColumn)
+		args = append(args, since.TodoID)
+	}
+	return clause, args
+}
+
+func (r *PostgresTodoRepository) changedTodos(ctx context.Context, tx *sql.Tx, userID string, since model.SyncPosition, horizon uint64, limit int) ([]change, error) {
+	clause, rangeArgs := changeRange("id", since, horizon)
+	args := append([]any{userID}, rangeArgs...)
+	query := `
+		SELECT ` + todoColumns + `, change_seq::text
+		FROM todos
+		WHERE user_id = $1` + clause + fmt.Sprintf(`
+		ORDER BY change_seq, id
+		LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed todos: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []change
+	for rows.Next() {
+		var seqText string
+		todo, err := scanTodoFromRows(rows, &seqText)
+		if err != nil {
+			return nil, err
+		}
+		seq, err := strconv.ParseUint(seqText, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse change_seq %q: %w", seqText, err)
+		}
+		changes = append(changes, change{seq: seq, id: todo.ID, todo: &todo})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate changed todos: %w", err)
+	}
+	return changes, nil
+}
+
+func (r *PostgresTodoRepository) changedTombstones(ctx context.Context, tx *sql.Tx, userID string, since model.SyncPosition, horizon uint64, limit int) ([]change, error) {
+	clause, rangeArgs := changeRange("todo_id", since, horizon)
+	args := append([]any{userID}, rangeArgs...)
+	query := `
+		SELECT todo_id, deleted_at, change_seq::text
+		FROM todo_tombstones
+		WHERE user_id = $1` + clause + fmt.Sprintf(`
+		ORDER BY change_seq, todo_id
+		LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []change
+	for rows.Next() {
+		var t model.Tombstone
+		var seqText string
+		if err := rows.Scan(&t.ID, &t.DeletedAt, &seqText); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		seq, err := strconv.ParseUint(seqText, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse change_seq %q: %w", seqText, err)
+		}
+		changes = append(changes, change{seq: seq, id: t.ID, tombstone: &t})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tombstones: %w", err)
+	}
+	return changes, nil
+}
+
+var _ TodoChangeRepository = (*PostgresTodoRepository)(nil)
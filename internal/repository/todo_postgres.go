@@ -3,11 +3,67 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/jaekwang-park/todo-api/internal/model"
 )
 
+// todoColumnNames is the column list shared by every query that scans a model.Todo.
+var todoColumnNames = []string{
+	"id", "user_id", "title", "description", "status", "priority",
+	"important", "urgent", "due_at", "estimate_minutes", "created_at", "updated_at",
+}
+
+var todoColumns = strings.Join(todoColumnNames, ", ")
+
+// todoRecordColumns are the columns scanTodo reads: the todo columns and
+// when each field was last changed.
+var todoRecordColumns = todoColumns + ", field_modified_at"
+
+// prefixedTodoColumns qualifies the todo columns with a table alias for joins.
+func prefixedTodoColumns(alias string) string {
+	cols := make([]string, len(todoColumnNames))
+	for i, c := range todoColumnNames {
+		cols[i] = alias + "." + c
+	}
+	return strings.Join(cols, ", ")
+}
+
+// listedTodoColumns returns the todo columns a list with the given fields
+// reads: all of them without fields, otherwise the requested ones plus id,
+// which the cursor needs.
+func listedTodoColumns(fields []string) []string {
+	if len(fields) == 0 {
+		return todoColumnNames
+	}
+	var cols []string
+	for _, c := range todoColumnNames {
+		if c == "id" || slices.Contains(fields, c) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// Expansions are correlated subqueries so List stays a single query.
+const (
+	todoOwnerColumn = `(SELECT json_build_object(
+			'id', u.id, 'email', u.email, 'nickname', u.nickname, 'profile_image_url', u.profile_image_url)
+		FROM users u WHERE u.id = todos.user_id)`
+	todoCountsColumns = `(SELECT count(*) FROM todo_dependencies d WHERE d.todo_id = todos.id),
+		(SELECT count(*) FROM todo_dependencies d WHERE d.blocker_id = todos.id),
+		(SELECT count(*) FROM time_entries e WHERE e.todo_id = todos.id)`
+)
+
+// priorityRank orders priorities from most to least pressing for sort=priority.
+const priorityRank = `CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END`
+
 type PostgresTodoRepository struct {
 	db *sql.DB
 }
@@ -18,12 +74,13 @@ func NewPostgresTodo(db *sql.DB) *PostgresTodoRepository {
 
 func (r *PostgresTodoRepository) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
 	query := `
-		INSERT INTO todos (user_id, title, description, status, due_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, title, description, status, due_at, created_at, updated_at`
+		INSERT INTO todos (user_id, title, description, status, priority, important, urgent, due_at, estimate_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + todoRecordColumns
 
 	row := r.db.QueryRowContext(ctx, query,
-		todo.UserID, todo.Title, todo.Description, todo.Status, todo.DueAt,
+		todo.UserID, todo.Title, todo.Description, todo.Status,
+		todo.Priority, todo.Important, todo.Urgent, todo.DueAt, todo.EstimateMinutes,
 	)
 
 	return scanTodo(row)
@@ -31,7 +88,7 @@ func (r *PostgresTodoRepository) Create(ctx context.Context, todo model.Todo) (m
 
 func (r *PostgresTodoRepository) GetByID(ctx context.Context, userID, todoID string) (model.Todo, error) {
 	query := `
-		SELECT id, user_id, title, description, status, due_at, created_at, updated_at
+		SELECT ` + todoRecordColumns + `
 		FROM todos
 		WHERE id = $1 AND user_id = $2`
 
@@ -42,21 +99,65 @@ func (r *PostgresTodoRepository) GetByID(ctx context.Context, userID, todoID str
 func (r *PostgresTodoRepository) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
 	query := `
 		UPDATE todos
-		SET title = $1, description = $2, status = $3, due_at = $4, updated_at = now()
-		WHERE id = $5 AND user_id = $6
-		RETURNING id, user_id, title, description, status, due_at, created_at, updated_at`
+		SET title = $1, description = $2, status = $3, priority = $4,
+		    important = $5, urgent = $6, due_at = $7, estimate_minutes = $8, updated_at = now(),
+		    field_modified_at = field_modified_at || $11::jsonb, change_seq = pg_current_xact_id()
+		WHERE id = $9 AND user_id = $10
+		RETURNING ` + todoRecordColumns
+
+	modified, err := fieldsModifiedAt(todo)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	row := r.db.QueryRowContext(ctx, query,
+		todo.Title, todo.Description, todo.Status, todo.Priority,
+		todo.Important, todo.Urgent, todo.DueAt, todo.EstimateMinutes, todo.ID, todo.UserID, modified,
+	)
+
+	return scanTodo(row)
+}
+
+func (r *PostgresTodoRepository) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	query := `
+		UPDATE todos
+		SET title = $1, description = $2, status = $3, priority = $4,
+		    important = $5, urgent = $6, due_at = $7, estimate_minutes = $8, updated_at = now(),
+		    field_modified_at = field_modified_at || $12::jsonb, change_seq = pg_current_xact_id()
+		WHERE id = $9 AND user_id = $10 AND updated_at = $11
+		RETURNING ` + todoRecordColumns
 
+	modified, err := fieldsModifiedAt(todo)
+	if err != nil {
+		return model.Todo{}, err
+	}
 	row := r.db.QueryRowContext(ctx, query,
-		todo.Title, todo.Description, todo.Status, todo.DueAt, todo.ID, todo.UserID,
+		todo.Title, todo.Description, todo.Status, todo.Priority,
+		todo.Important, todo.Urgent, todo.DueAt, todo.EstimateMinutes, todo.ID, todo.UserID, todo.UpdatedAt, modified,
 	)
 
 	return scanTodo(row)
 }
 
 func (r *PostgresTodoRepository) Delete(ctx context.Context, userID, todoID string) error {
-	query := `DELETE FROM todos WHERE id = $1 AND user_id = $2`
+	return r.delete(ctx, "", todoID, userID)
+}
+
+func (r *PostgresTodoRepository) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	return r.delete(ctx, " AND updated_at = $3", todoID, userID, updatedAt)
+}
 
-	result, err := r.db.ExecContext(ctx, query, todoID, userID)
+// delete deletes the todo with ID $1 of user $2 if it also matches cond.
+func (r *PostgresTodoRepository) delete(ctx context.Context, cond string, args ...any) error {
+	// Leave a tombstone in the same statement so syncing clients see the deletion.
+	query := `
+		WITH deleted AS (
+			DELETE FROM todos WHERE id = $1 AND user_id = $2` + cond + `
+			RETURNING id, user_id
+		)
+		INSERT INTO todo_tombstones (todo_id, user_id)
+		SELECT id, user_id FROM deleted`
+
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
@@ -84,8 +185,22 @@ func (r *PostgresTodoRepository) List(ctx context.Context, params model.TodoList
 	args := []any{params.UserID}
 	argIdx := 2
 
+	cols := listedTodoColumns(params.Fields)
+	selected := strings.Join(cols, ", ")
+	var owner []byte
+	var counts model.TodoCounts
+	var extra []any
+	if params.Expands(model.TodoExpandOwner) {
+		selected += ", " + todoOwnerColumn
+		extra = append(extra, &owner)
+	}
+	if params.Expands(model.TodoExpandCounts) {
+		selected += ", " + todoCountsColumns
+		extra = append(extra, &counts.BlockedBy, &counts.Blocking, &counts.TimeEntries)
+	}
+
 	query := `
-		SELECT id, user_id, title, description, status, due_at, created_at, updated_at
+		SELECT ` + selected + `
 		FROM todos
 		WHERE user_id = $1`
 
@@ -95,13 +210,53 @@ func (r *PostgresTodoRepository) List(ctx context.Context, params model.TodoList
 		argIdx++
 	}
 
+	if params.Priority != nil {
+		query += fmt.Sprintf(" AND priority = $%d", argIdx)
+		args = append(args, string(*params.Priority))
+		argIdx++
+	}
+
+	if params.Important != nil {
+		query += fmt.Sprintf(" AND important = $%d", argIdx)
+		args = append(args, *params.Important)
+		argIdx++
+	}
+
+	if params.Urgent != nil {
+		query += fmt.Sprintf(" AND urgent = $%d", argIdx)
+		args = append(args, *params.Urgent)
+		argIdx++
+	}
+
+	if params.Blocked != nil {
+		openBlockers := `EXISTS (
+			SELECT 1 FROM todo_dependencies d
+			JOIN todos b ON b.id = d.blocker_id
+			WHERE d.todo_id = todos.id AND b.status <> 'completed')`
+		if *params.Blocked {
+			query += " AND " + openBlockers
+		} else {
+			query += " AND NOT " + openBlockers
+		}
+	}
+
 	if params.Cursor != "" {
-		query += fmt.Sprintf(" AND created_at < (SELECT created_at FROM todos WHERE id = $%d)", argIdx)
+		if params.Sort == model.TodoSortPriority {
+			query += fmt.Sprintf(" AND (%s, created_at) < (SELECT %s, created_at FROM todos WHERE id = $%d)",
+				priorityRank, priorityRank, argIdx)
+		} else {
+			query += fmt.Sprintf(" AND created_at < (SELECT created_at FROM todos WHERE id = $%d)", argIdx)
+		}
 		args = append(args, params.Cursor)
 		argIdx++
 	}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", argIdx)
+	if params.Sort == model.TodoSortPriority {
+		query += " ORDER BY " + priorityRank + " DESC, created_at DESC"
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+	query += fmt.Sprintf(" LIMIT $%d", argIdx)
 	args = append(args, fetchLimit)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -112,10 +267,20 @@ func (r *PostgresTodoRepository) List(ctx context.Context, params model.TodoList
 
 	var todos []model.Todo
 	for rows.Next() {
-		todo, err := scanTodoFromRows(rows)
+		todo, err := scanTodoColumns(rows, cols, extra...)
 		if err != nil {
 			return model.TodoListResult{}, err
 		}
+		if params.Expands(model.TodoExpandOwner) {
+			todo.Owner = new(model.TodoOwner)
+			if err := json.Unmarshal(owner, todo.Owner); err != nil {
+				return model.TodoListResult{}, fmt.Errorf("failed to decode todo owner: %w", err)
+			}
+		}
+		if params.Expands(model.TodoExpandCounts) {
+			c := counts
+			todo.Counts = &c
+		}
 		todos = append(todos, todo)
 	}
 	if err := rows.Err(); err != nil {
@@ -138,35 +303,98 @@ func (r *PostgresTodoRepository) List(ctx context.Context, params model.TodoList
 	}, nil
 }
 
+func (r *PostgresTodoRepository) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE user_id = $1 AND id = ANY($2::uuid[])`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list todos by id: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []model.Todo
+	for rows.Next() {
+		todo, err := scanTodoFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate todos: %w", err)
+	}
+	return todos, nil
+}
+
 type scannable interface {
 	Scan(dest ...any) error
 }
 
+// scanTodo scans todoRecordColumns.
 func scanTodo(row scannable) (model.Todo, error) {
 	var t model.Todo
+	var modified []byte
 	err := row.Scan(
 		&t.ID, &t.UserID, &t.Title, &t.Description,
-		&t.Status, &t.DueAt, &t.CreatedAt, &t.UpdatedAt,
+		&t.Status, &t.Priority, &t.Important, &t.Urgent,
+		&t.DueAt, &t.EstimateMinutes, &t.CreatedAt, &t.UpdatedAt, &modified,
 	)
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("failed to scan todo: %w", err)
 	}
+	if err := json.Unmarshal(modified, &t.FieldsModifiedAt); err != nil {
+		return model.Todo{}, fmt.Errorf("failed to decode field modification times: %w", err)
+	}
 	return t, nil
 }
 
-func scanTodoFromRows(rows *sql.Rows) (model.Todo, error) {
+// fieldsModifiedAt encodes todo.FieldsModifiedAt for an update, which
+// merges it into the stored times.
+func fieldsModifiedAt(todo model.Todo) (string, error) {
+	if todo.FieldsModifiedAt == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(todo.FieldsModifiedAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field modification times: %w", err)
+	}
+	return string(b), nil
+}
+
+// scanTodoFromRows scans the todo columns, followed by any extra columns
+// the query selected after them into extra.
+func scanTodoFromRows(rows *sql.Rows, extra ...any) (model.Todo, error) {
+	return scanTodoColumns(rows, todoColumnNames, extra...)
+}
+
+// scanTodoColumns scans the named todo columns, in order, followed by extra.
+func scanTodoColumns(rows *sql.Rows, cols []string, extra ...any) (model.Todo, error) {
 	var t model.Todo
 	var dueAt sql.NullTime
-	err := rows.Scan(
-		&t.ID, &t.UserID, &t.Title, &t.Description,
-		&t.Status, &dueAt, &t.CreatedAt, &t.UpdatedAt,
-	)
+	var estimate sql.NullInt32
+	fields := map[string]any{
+		"id": &t.ID, "user_id": &t.UserID, "title": &t.Title, "description": &t.Description,
+		"status": &t.Status, "priority": &t.Priority, "important": &t.Important, "urgent": &t.Urgent,
+		"due_at": &dueAt, "estimate_minutes": &estimate, "created_at": &t.CreatedAt, "updated_at": &t.UpdatedAt,
+	}
+	dest := make([]any, 0, len(cols)+len(extra))
+	for _, c := range cols {
+		dest = append(dest, fields[c])
+	}
+	err := rows.Scan(append(dest, extra...)...)
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("failed to scan todo row: %w", err)
 	}
 	if dueAt.Valid {
 		t.DueAt = &dueAt.Time
 	}
+	if estimate.Valid {
+		minutes := int(estimate.Int32)
+		t.EstimateMinutes = &minutes
+	}
 	return t, nil
 }
 
@@ -10,4 +10,7 @@ type UserRepository interface {
 	GetOrCreate(ctx context.Context, cognitoSub, email string) (model.User, error)
 	GetByCognitoSub(ctx context.Context, cognitoSub string) (model.User, error)
 	Update(ctx context.Context, user model.User) (model.User, error)
+	// ListByIDs returns the users with the given IDs, in no particular
+	// order. IDs that match no user are left out.
+	ListByIDs(ctx context.Context, ids []string) ([]model.User, error)
 }
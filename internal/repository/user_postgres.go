@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/jaekwang-park/todo-api/internal/model"
 )
 
@@ -21,7 +23,7 @@ func (r *PostgresUserRepository) GetOrCreate(ctx context.Context, cognitoSub, em
 		INSERT INTO users (cognito_sub, email)
 		VALUES ($1, $2)
 		ON CONFLICT (cognito_sub) DO UPDATE SET email = EXCLUDED.email
-		RETURNING id, cognito_sub, email, nickname, profile_image_url, created_at, updated_at`
+		RETURNING id, cognito_sub, email, nickname, profile_image_url, language, created_at, updated_at`
 
 	row := r.db.QueryRowContext(ctx, query, cognitoSub, email)
 	return scanUser(row)
@@ -29,7 +31,7 @@ func (r *PostgresUserRepository) GetOrCreate(ctx context.Context, cognitoSub, em
 
 func (r *PostgresUserRepository) GetByCognitoSub(ctx context.Context, cognitoSub string) (model.User, error) {
 	query := `
-		SELECT id, cognito_sub, email, nickname, profile_image_url, created_at, updated_at
+		SELECT id, cognito_sub, email, nickname, profile_image_url, language, created_at, updated_at
 		FROM users
 		WHERE cognito_sub = $1`
 
@@ -40,19 +42,45 @@ func (r *PostgresUserRepository) GetByCognitoSub(ctx context.Context, cognitoSub
 func (r *PostgresUserRepository) Update(ctx context.Context, user model.User) (model.User, error) {
 	query := `
 		UPDATE users
-		SET nickname = $1, profile_image_url = $2, updated_at = now()
-		WHERE id = $3
-		RETURNING id, cognito_sub, email, nickname, profile_image_url, created_at, updated_at`
+		SET nickname = $1, profile_image_url = $2, language = $3, updated_at = now()
+		WHERE id = $4
+		RETURNING id, cognito_sub, email, nickname, profile_image_url, language, created_at, updated_at`
 
-	row := r.db.QueryRowContext(ctx, query, user.Nickname, user.ProfileImageURL, user.ID)
+	row := r.db.QueryRowContext(ctx, query, user.Nickname, user.ProfileImageURL, user.Language, user.ID)
 	return scanUser(row)
 }
 
+func (r *PostgresUserRepository) ListByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	query := `
+		SELECT id, cognito_sub, email, nickname, profile_image_url, language, created_at, updated_at
+		FROM users
+		WHERE id = ANY($1::uuid[])`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users by id: %w", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+	return users, nil
+}
+
 func scanUser(row scannable) (model.User, error) {
 	var u model.User
 	err := row.Scan(
 		&u.ID, &u.CognitoSub, &u.Email, &u.Nickname,
-		&u.ProfileImageURL, &u.CreatedAt, &u.UpdatedAt,
+		&u.ProfileImageURL, &u.Language, &u.CreatedAt, &u.UpdatedAt,
 	)
 	if err != nil {
 		return model.User{}, fmt.Errorf("failed to scan user: %w", err)
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	GetByID(ctx context.Context, userID, webhookID string) (model.Webhook, error)
+	// Get loads a webhook regardless of owner, for the delivery worker.
+	Get(ctx context.Context, webhookID string) (model.Webhook, error)
+	List(ctx context.Context, userID string) ([]model.Webhook, error)
+	Update(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	Delete(ctx context.Context, userID, webhookID string) error
+	ListSubscribed(ctx context.Context, userID string, eventType model.EventType) ([]model.Webhook, error)
+	ResetFailures(ctx context.Context, webhookID string) error
+	// RecordFailure increments the consecutive failure count and deactivates
+	// the webhook once it reaches maxFailures. It reports whether the webhook
+	// was disabled by this call.
+	RecordFailure(ctx context.Context, webhookID string, maxFailures int) (bool, error)
+
+	CreateDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, userID, deliveryID string) (model.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]model.WebhookDelivery, error)
+	// ClaimDueDeliveries returns pending deliveries whose next attempt is due
+	// and pushes their next attempt back by lease so no other worker picks
+	// them up meanwhile.
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+const webhookColumns = `id, user_id, url, secret, event_types, active, failure_count, disabled_at, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, user_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, response_status, last_error, created_at, delivered_at`
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhook(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+func (r *PostgresWebhookRepository) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, event_types, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + webhookColumns
+
+	row := r.db.QueryRowContext(ctx, query,
+		webhook.UserID, webhook.URL, webhook.Secret, pq.Array(eventTypeStrings(webhook.EventTypes)), webhook.Active,
+	)
+	return scanWebhook(row)
+}
+
+func (r *PostgresWebhookRepository) GetByID(ctx context.Context, userID, webhookID string) (model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	row := r.db.QueryRowContext(ctx, query, webhookID, userID)
+	return scanWebhook(row)
+}
+
+func (r *PostgresWebhookRepository) Get(ctx context.Context, webhookID string) (model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	row := r.db.QueryRowContext(ctx, query, webhookID)
+	return scanWebhook(row)
+}
+
+func (r *PostgresWebhookRepository) List(ctx context.Context, userID string) ([]model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at`
+
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+func (r *PostgresWebhookRepository) Update(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	// Re-activating a webhook clears the failure streak that disabled it.
+	query := `
+		UPDATE webhooks
+		SET url = $1, event_types = $2,
+		    failure_count = CASE WHEN $3 AND NOT active THEN 0 ELSE failure_count END,
+		    disabled_at = CASE WHEN $3 THEN NULL ELSE disabled_at END,
+		    active = $3, updated_at = now()
+		WHERE id = $4 AND user_id = $5
+		RETURNING ` + webhookColumns
+
+	row := r.db.QueryRowContext(ctx, query,
+		webhook.URL, pq.Array(eventTypeStrings(webhook.EventTypes)), webhook.Active, webhook.ID, webhook.UserID,
+	)
+	return scanWebhook(row)
+}
+
+func (r *PostgresWebhookRepository) Delete(ctx context.Context, userID, webhookID string) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) ListSubscribed(ctx context.Context, userID string, eventType model.EventType) ([]model.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND active AND $2 = ANY(event_types)`
+
+	return r.queryWebhooks(ctx, query, userID, string(eventType))
+}
+
+func (r *PostgresWebhookRepository) ResetFailures(ctx context.Context, webhookID string) error {
+	query := `UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0`
+
+	if _, err := r.db.ExecContext(ctx, query, webhookID); err != nil {
+		return fmt.Errorf("failed to reset webhook failures: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepository) RecordFailure(ctx context.Context, webhookID string, maxFailures int) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET failure_count = failure_count + 1,
+		    disabled_at = CASE WHEN active AND failure_count + 1 >= $2 THEN now() ELSE disabled_at END,
+		    active = active AND failure_count + 1 < $2
+		WHERE id = $1
+		RETURNING NOT active AND failure_count = $2`
+
+	var disabled bool
+	if err := r.db.QueryRowContext(ctx, query, webhookID, maxFailures).Scan(&disabled); err != nil {
+		return false, fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	return disabled, nil
+}
+
+func (r *PostgresWebhookRepository) CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, user_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + deliveryColumns
+
+	row := r.db.QueryRowContext(ctx, query,
+		d.WebhookID, d.UserID, d.EventID, string(d.EventType), []byte(d.Payload), string(d.Status), d.NextAttemptAt,
+	)
+	return scanDelivery(row)
+}
+
+func (r *PostgresWebhookRepository) GetDelivery(ctx context.Context, userID, deliveryID string) (model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1 AND user_id = $2`
+
+	row := r.db.QueryRowContext(ctx, query, deliveryID, userID)
+	return scanDelivery(row)
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	return r.queryDeliveries(ctx, query, webhookID, userID, limit)
+}
+
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	return r.queryDeliveries(ctx, query, limit, lease.Seconds())
+}
+
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, response_status = $4,
+		    last_error = $5, delivered_at = $6
+		WHERE id = $7`
+
+	_, err := r.db.ExecContext(ctx, query,
+		string(d.Status), d.Attempts, d.NextAttemptAt, d.ResponseStatus, d.LastError, d.DeliveredAt, d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]model.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []model.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhook(row scannable) (model.Webhook, error) {
+	var w model.Webhook
+	var eventTypes []string
+	err := row.Scan(
+		&w.ID, &w.UserID, &w.URL, &w.Secret, pq.Array(&eventTypes),
+		&w.Active, &w.FailureCount, &w.DisabledAt, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+	w.EventTypes = make([]model.EventType, len(eventTypes))
+	for i, et := range eventTypes {
+		w.EventTypes[i] = model.EventType(et)
+	}
+	return w, nil
+}
+
+func scanDelivery(row scannable) (model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	var payload []byte
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.UserID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt,
+	)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+	d.Payload = payload
+	return d, nil
+}
+
+func eventTypeStrings(types []model.EventType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+var _ WebhookRepository = (*PostgresWebhookRepository)(nil)
@@ -5,9 +5,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/jaekwang-park/todo-api/internal/cognito"
+	"github.com/jaekwang-park/todo-api/internal/i18n"
 	"github.com/jaekwang-park/todo-api/internal/repository"
 )
 
@@ -30,6 +32,7 @@ func NewAuthService(cognitoClient cognito.Client, userRepo repository.UserReposi
 type SignUpInput struct {
 	Email    string
 	Password string
+	Language string // optional preferred language, "en" or "ko"
 }
 
 type SignUpOutput struct {
@@ -95,16 +98,17 @@ type LogoutInput struct {
 // --- Service methods ---
 
 func (s *AuthService) SignUp(ctx context.Context, input SignUpInput) (SignUpOutput, error) {
-	if input.Email == "" {
-		return SignUpOutput{}, fmt.Errorf("%w: email is required", ErrInvalidInput)
-	}
-	if input.Password == "" {
-		return SignUpOutput{}, fmt.Errorf("%w: password is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.SignUp")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return SignUpOutput{}, err
 	}
 
 	out, err := s.cognitoClient.SignUp(ctx, cognito.SignUpInput{
 		Email:    input.Email,
 		Password: input.Password,
+		Locale:   input.Language,
 	})
 	if err != nil {
 		return SignUpOutput{}, err
@@ -118,11 +122,11 @@ func (s *AuthService) SignUp(ctx context.Context, input SignUpInput) (SignUpOutp
 }
 
 func (s *AuthService) ConfirmSignUp(ctx context.Context, input ConfirmSignUpInput) error {
-	if input.Email == "" {
-		return fmt.Errorf("%w: email is required", ErrInvalidInput)
-	}
-	if input.Code == "" {
-		return fmt.Errorf("%w: code is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.ConfirmSignUp")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.ConfirmSignUp(ctx, cognito.ConfirmSignUpInput{
@@ -132,8 +136,11 @@ func (s *AuthService) ConfirmSignUp(ctx context.Context, input ConfirmSignUpInpu
 }
 
 func (s *AuthService) ResendCode(ctx context.Context, input ResendCodeInput) error {
-	if input.Email == "" {
-		return fmt.Errorf("%w: email is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.ResendCode")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.ResendConfirmationCode(ctx, cognito.ResendCodeInput{
@@ -142,11 +149,11 @@ func (s *AuthService) ResendCode(ctx context.Context, input ResendCodeInput) err
 }
 
 func (s *AuthService) Login(ctx context.Context, input LoginInput) (LoginOutput, error) {
-	if input.Email == "" {
-		return LoginOutput{}, fmt.Errorf("%w: email is required", ErrInvalidInput)
-	}
-	if input.Password == "" {
-		return LoginOutput{}, fmt.Errorf("%w: password is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return LoginOutput{}, err
 	}
 
 	authOut, err := s.cognitoClient.Login(ctx, cognito.LoginInput{
@@ -157,17 +164,28 @@ func (s *AuthService) Login(ctx context.Context, input LoginInput) (LoginOutput,
 		return LoginOutput{}, err
 	}
 
-	// Extract sub from ID token payload (no signature verification needed — just issued by Cognito)
-	sub, err := extractSub(authOut.IDToken)
+	// Extract claims from ID token payload (no signature verification needed — just issued by Cognito)
+	claims, err := extractClaims(authOut.IDToken)
 	if err != nil {
-		return LoginOutput{}, fmt.Errorf("failed to extract sub from id token: %w", err)
+		return LoginOutput{}, fmt.Errorf("failed to extract claims from id token: %w", err)
 	}
 
 	// Create or update user in DB
-	if _, err := s.userRepo.GetOrCreate(ctx, sub, input.Email); err != nil {
+	user, err := s.userRepo.GetOrCreate(ctx, claims.Sub, input.Email)
+	if err != nil {
 		return LoginOutput{}, fmt.Errorf("failed to get or create user: %w", err)
 	}
 
+	// Keep the stored language preference in step with the Cognito locale.
+	// It is only a preference, so failing to save it does not fail the login;
+	// the next login tries again.
+	if tag, ok := i18n.Parse(claims.Locale); ok && tag.String() != user.Language {
+		user.Language = tag.String()
+		if _, err := s.userRepo.Update(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "failed to update user language", "user_id", user.ID, "error", err)
+		}
+	}
+
 	return LoginOutput{
 		IDToken:      authOut.IDToken,
 		AccessToken:  authOut.AccessToken,
@@ -178,11 +196,11 @@ func (s *AuthService) Login(ctx context.Context, input LoginInput) (LoginOutput,
 }
 
 func (s *AuthService) Refresh(ctx context.Context, input RefreshInput) (RefreshOutput, error) {
-	if input.Email == "" {
-		return RefreshOutput{}, fmt.Errorf("%w: email is required", ErrInvalidInput)
-	}
-	if input.RefreshToken == "" {
-		return RefreshOutput{}, fmt.Errorf("%w: refresh_token is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.Refresh")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return RefreshOutput{}, err
 	}
 
 	authOut, err := s.cognitoClient.RefreshTokens(ctx, cognito.RefreshInput{
@@ -202,8 +220,11 @@ func (s *AuthService) Refresh(ctx context.Context, input RefreshInput) (RefreshO
 }
 
 func (s *AuthService) ForgotPassword(ctx context.Context, input ForgotPasswordInput) error {
-	if input.Email == "" {
-		return fmt.Errorf("%w: email is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.ForgotPassword")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.ForgotPassword(ctx, cognito.ForgotPasswordInput{
@@ -212,14 +233,11 @@ func (s *AuthService) ForgotPassword(ctx context.Context, input ForgotPasswordIn
 }
 
 func (s *AuthService) ConfirmForgotPassword(ctx context.Context, input ConfirmForgotPasswordInput) error {
-	if input.Email == "" {
-		return fmt.Errorf("%w: email is required", ErrInvalidInput)
-	}
-	if input.Code == "" {
-		return fmt.Errorf("%w: code is required", ErrInvalidInput)
-	}
-	if input.NewPassword == "" {
-		return fmt.Errorf("%w: new_password is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.ConfirmForgotPassword")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.ConfirmForgotPassword(ctx, cognito.ConfirmForgotPasswordInput{
@@ -230,14 +248,11 @@ func (s *AuthService) ConfirmForgotPassword(ctx context.Context, input ConfirmFo
 }
 
 func (s *AuthService) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
-	if input.AccessToken == "" {
-		return fmt.Errorf("%w: access_token is required", ErrInvalidInput)
-	}
-	if input.PreviousPassword == "" {
-		return fmt.Errorf("%w: previous_password is required", ErrInvalidInput)
-	}
-	if input.NewPassword == "" {
-		return fmt.Errorf("%w: new_password is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.ChangePassword(ctx, cognito.ChangePasswordInput{
@@ -248,8 +263,11 @@ func (s *AuthService) ChangePassword(ctx context.Context, input ChangePasswordIn
 }
 
 func (s *AuthService) Logout(ctx context.Context, input LogoutInput) error {
-	if input.AccessToken == "" {
-		return fmt.Errorf("%w: access_token is required", ErrInvalidInput)
+	ctx, span := tracer.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return err
 	}
 
 	return s.cognitoClient.GlobalSignOut(ctx, cognito.GlobalSignOutInput{
@@ -257,27 +275,30 @@ func (s *AuthService) Logout(ctx context.Context, input LogoutInput) error {
 	})
 }
 
-// extractSub decodes the JWT payload (without verifying signature) and extracts the "sub" claim.
-func extractSub(idToken string) (string, error) {
+type idTokenClaims struct {
+	Sub    string `json:"sub"`
+	Locale string `json:"locale"`
+}
+
+// extractClaims decodes the JWT payload (without verifying signature) and extracts the "sub" and "locale" claims.
+func extractClaims(idToken string) (idTokenClaims, error) {
 	parts := strings.Split(idToken, ".")
 	if len(parts) != 3 {
-		return "", fmt.Errorf("invalid JWT format")
+		return idTokenClaims{}, fmt.Errorf("invalid JWT format")
 	}
 
 	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return "", fmt.Errorf("failed to decode JWT payload: %w", err)
+		return idTokenClaims{}, fmt.Errorf("failed to decode JWT payload: %w", err)
 	}
 
-	var claims struct {
-		Sub string `json:"sub"`
-	}
+	var claims idTokenClaims
 	if err := json.Unmarshal(payload, &claims); err != nil {
-		return "", fmt.Errorf("failed to parse JWT claims: %w", err)
+		return idTokenClaims{}, fmt.Errorf("failed to parse JWT claims: %w", err)
 	}
 	if claims.Sub == "" {
-		return "", fmt.Errorf("sub claim not found in JWT")
+		return idTokenClaims{}, fmt.Errorf("sub claim not found in JWT")
 	}
 
-	return claims.Sub, nil
+	return claims, nil
 }
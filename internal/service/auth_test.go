@@ -59,6 +59,7 @@ type mockUserRepo struct {
 	getOrCreateFn     func(ctx context.Context, cognitoSub, email string) (model.User, error)
 	getByCognitoSubFn func(ctx context.Context, cognitoSub string) (model.User, error)
 	updateFn          func(ctx context.Context, user model.User) (model.User, error)
+	listByIDsFn       func(ctx context.Context, ids []string) ([]model.User, error)
 }
 
 func (m *mockUserRepo) GetOrCreate(ctx context.Context, cognitoSub, email string) (model.User, error) {
@@ -70,6 +71,9 @@ func (m *mockUserRepo) GetByCognitoSub(ctx context.Context, cognitoSub string) (
 func (m *mockUserRepo) Update(ctx context.Context, user model.User) (model.User, error) {
 	return m.updateFn(ctx, user)
 }
+func (m *mockUserRepo) ListByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	return m.listByIDsFn(ctx, ids)
+}
 
 // fakeIDToken creates a JWT-like string with a base64url-encoded payload
 // containing the given sub and email claims.
@@ -393,6 +397,78 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestAuthService_Login_StoresLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		locale     string
+		stored     string
+		wantUpdate string
+	}{
+		{name: "new locale", locale: "ko-KR", wantUpdate: "ko"},
+		{name: "unchanged", locale: "ko", stored: "ko"},
+		{name: "unsupported", locale: "fr"},
+		{name: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9"
+			payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"sub-1","locale":"` + tt.locale + `"}`))
+			mock := &mockCognitoClient{
+				loginFn: func(ctx context.Context, input cognito.LoginInput) (cognito.AuthOutput, error) {
+					return cognito.AuthOutput{IDToken: header + "." + payload + ".fakesig"}, nil
+				},
+			}
+
+			var updated string
+			userRepo := &mockUserRepo{
+				getOrCreateFn: func(ctx context.Context, cognitoSub, email string) (model.User, error) {
+					return model.User{ID: "user-1", CognitoSub: cognitoSub, Email: email, Language: tt.stored}, nil
+				},
+				updateFn: func(ctx context.Context, user model.User) (model.User, error) {
+					updated = user.Language
+					return user, nil
+				},
+			}
+
+			svc := service.NewAuthService(mock, userRepo)
+			if _, err := svc.Login(context.Background(), service.LoginInput{Email: "test@example.com", Password: "Password1!"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated != tt.wantUpdate {
+				t.Errorf("stored language: got %q, want %q", updated, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestAuthService_Login_LocaleSaveFailure(t *testing.T) {
+	header := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9"
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"sub-1","locale":"ko"}`))
+	mock := &mockCognitoClient{
+		loginFn: func(ctx context.Context, input cognito.LoginInput) (cognito.AuthOutput, error) {
+			return cognito.AuthOutput{IDToken: header + "." + payload + ".fakesig", AccessToken: "access"}, nil
+		},
+	}
+	userRepo := &mockUserRepo{
+		getOrCreateFn: func(ctx context.Context, cognitoSub, email string) (model.User, error) {
+			return model.User{ID: "user-1", CognitoSub: cognitoSub, Email: email}, nil
+		},
+		updateFn: func(ctx context.Context, user model.User) (model.User, error) {
+			return model.User{}, errors.New("database unavailable")
+		},
+	}
+
+	svc := service.NewAuthService(mock, userRepo)
+	out, err := svc.Login(context.Background(), service.LoginInput{Email: "test@example.com", Password: "Password1!"})
+	if err != nil {
+		t.Fatalf("expected login to succeed when the locale cannot be saved, got %v", err)
+	}
+	if out.AccessToken != "access" {
+		t.Errorf("expected tokens returned, got %+v", out)
+	}
+}
+
 func TestAuthService_Refresh(t *testing.T) {
 	tests := []struct {
 		name      string
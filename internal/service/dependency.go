@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// errDependenciesDisabled is returned when no dependency repository is
+// configured. The endpoints then behave as if they did not exist.
+var errDependenciesDisabled = fmt.Errorf("%w: dependency tracking is not configured", ErrNotFound)
+
+// AddBlocker records that todoID cannot be completed before blockerID.
+// Dependencies that would close a cycle are rejected with ErrConflict.
+func (s *TodoService) AddBlocker(ctx context.Context, userID, todoID, blockerID string) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.AddBlocker")
+	defer span.End()
+
+	if s.deps == nil {
+		return model.Todo{}, errDependenciesDisabled
+	}
+	if err := invalidInput(validate.Check(validate.Required("blocker_id", blockerID))); err != nil {
+		return model.Todo{}, err
+	}
+	if todoID == blockerID {
+		return model.Todo{}, fmt.Errorf("%w: a todo cannot block itself", ErrInvalidInput)
+	}
+
+	for _, id := range []string{todoID, blockerID} {
+		if _, err := s.repo.GetByID(ctx, userID, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.Todo{}, ErrNotFound
+			}
+			return model.Todo{}, fmt.Errorf("failed to get todo for dependency: %w", err)
+		}
+	}
+
+	dep := model.TodoDependency{TodoID: todoID, BlockerID: blockerID}
+	err := s.deps.Add(ctx, userID, dep, func(edges []model.TodoDependency) error {
+		// The new edge closes a cycle if todoID is already reachable from blockerID.
+		if reachable(edges, blockerID, todoID) {
+			return fmt.Errorf("%w: dependency would create a cycle", ErrConflict)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrConflict) {
+		return model.Todo{}, err
+	}
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	return s.GetByID(ctx, userID, todoID)
+}
+
+// RemoveBlocker deletes the dependency of todoID on blockerID.
+func (s *TodoService) RemoveBlocker(ctx context.Context, userID, todoID, blockerID string) error {
+	ctx, span := tracer.Start(ctx, "TodoService.RemoveBlocker")
+	defer span.End()
+
+	if s.deps == nil {
+		return errDependenciesDisabled
+	}
+
+	dep := model.TodoDependency{TodoID: todoID, BlockerID: blockerID}
+	if err := s.deps.Remove(ctx, userID, dep); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	return nil
+}
+
+// DependencyGraph returns the user's todos that take part in a dependency,
+// with blockers ordered before the todos they block. Dependencies never
+// cross users. Todos are not grouped into projects, so a plan is the part
+// of the graph connected to one of its todos: with todoID, only the todos
+// linked to it through a chain of dependencies, in either direction, are
+// returned. Without it, the user's whole graph is.
+func (s *TodoService) DependencyGraph(ctx context.Context, userID, todoID string) (model.DependencyGraph, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.DependencyGraph")
+	defer span.End()
+
+	if s.deps == nil {
+		return model.DependencyGraph{}, errDependenciesDisabled
+	}
+
+	var todo model.Todo
+	if todoID != "" {
+		var err error
+		if todo, err = s.repo.GetByID(ctx, userID, todoID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.DependencyGraph{}, ErrNotFound
+			}
+			return model.DependencyGraph{}, fmt.Errorf("failed to get todo: %w", err)
+		}
+	}
+
+	nodes, err := s.deps.ListNodes(ctx, userID)
+	if err != nil {
+		return model.DependencyGraph{}, fmt.Errorf("failed to list dependency nodes: %w", err)
+	}
+	edges, err := s.deps.ListEdges(ctx, userID)
+	if err != nil {
+		return model.DependencyGraph{}, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+
+	if todoID != "" {
+		nodes, edges = component(nodes, edges, todoID)
+		if len(nodes) == 0 {
+			nodes = []model.Todo{todo}
+		}
+	}
+
+	sorted, err := topoSort(nodes, edges)
+	if err != nil {
+		return model.DependencyGraph{}, err
+	}
+	if edges == nil {
+		edges = []model.TodoDependency{}
+	}
+
+	return model.DependencyGraph{Todos: sorted, Edges: edges}, nil
+}
+
+// DependencyEdges returns all of the user's dependencies. It returns none
+// when dependency tracking is not configured.
+func (s *TodoService) DependencyEdges(ctx context.Context, userID string) ([]model.TodoDependency, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.DependencyEdges")
+	defer span.End()
+
+	if s.deps == nil {
+		return nil, nil
+	}
+	edges, err := s.deps.ListEdges(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+	return edges, nil
+}
+
+func (s *TodoService) loadDependencies(ctx context.Context, userID string, todo *model.Todo) error {
+	blockers, err := s.deps.ListBlockers(ctx, userID, todo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list blockers: %w", err)
+	}
+	blocking, err := s.deps.ListBlocking(ctx, userID, todo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list blocked todos: %w", err)
+	}
+
+	todo.BlockedBy = todoIDs(blockers)
+	todo.Blocking = todoIDs(blocking)
+	return nil
+}
+
+func (s *TodoService) openBlockers(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	blockers, err := s.deps.ListBlockers(ctx, userID, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+
+	var open []model.Todo
+	for _, b := range blockers {
+		if b.Status != model.TodoStatusCompleted {
+			open = append(open, b)
+		}
+	}
+	return open, nil
+}
+
+func todoIDs(todos []model.Todo) []string {
+	ids := make([]string, len(todos))
+	for i, t := range todos {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// reachable reports whether target can be reached from start by following
+// blocked-by edges (todo -> blocker).
+func reachable(edges []model.TodoDependency, start, target string) bool {
+	next := make(map[string][]string)
+	for _, e := range edges {
+		next[e.TodoID] = append(next[e.TodoID], e.BlockerID)
+	}
+
+	visited := map[string]bool{start: true}
+	stack := []string{start}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		for _, n := range next[id] {
+			if !visited[n] {
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return false
+}
+
+// component keeps the todos and edges connected to start, following edges
+// in both directions.
+func component(todos []model.Todo, edges []model.TodoDependency, start string) ([]model.Todo, []model.TodoDependency) {
+	neighbours := make(map[string][]string)
+	for _, e := range edges {
+		neighbours[e.TodoID] = append(neighbours[e.TodoID], e.BlockerID)
+		neighbours[e.BlockerID] = append(neighbours[e.BlockerID], e.TodoID)
+	}
+
+	visited := map[string]bool{start: true}
+	stack := []string{start}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range neighbours[id] {
+			if !visited[n] {
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	var keptTodos []model.Todo
+	for _, t := range todos {
+		if visited[t.ID] {
+			keptTodos = append(keptTodos, t)
+		}
+	}
+	var keptEdges []model.TodoDependency
+	for _, e := range edges {
+		if visited[e.TodoID] {
+			keptEdges = append(keptEdges, e)
+		}
+	}
+	return keptTodos, keptEdges
+}
+
+// topoSort orders todos so that every blocker precedes the todos it blocks
+// (Kahn's algorithm). Ties keep the input order, which makes the output stable.
+func topoSort(todos []model.Todo, edges []model.TodoDependency) ([]model.Todo, error) {
+	index := make(map[string]int, len(todos))
+	for i, t := range todos {
+		index[t.ID] = i
+	}
+
+	inDegree := make([]int, len(todos))
+	unblocks := make([][]int, len(todos))
+	for _, e := range edges {
+		from, okFrom := index[e.BlockerID]
+		to, okTo := index[e.TodoID]
+		if !okFrom || !okTo {
+			continue
+		}
+		unblocks[from] = append(unblocks[from], to)
+		inDegree[to]++
+	}
+
+	var queue []int
+	for i := range todos {
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	sorted := make([]model.Todo, 0, len(todos))
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, todos[i])
+		for _, j := range unblocks[i] {
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	if len(sorted) != len(todos) {
+		return nil, fmt.Errorf("%w: dependency graph contains a cycle", ErrConflict)
+	}
+	return sorted, nil
+}
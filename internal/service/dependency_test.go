@@ -0,0 +1,346 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+// memDependencyRepo is an in-memory repository.TodoDependencyRepository.
+type memDependencyRepo struct {
+	todos map[string]model.Todo
+	edges []model.TodoDependency
+}
+
+func newMemDependencyRepo(todos ...model.Todo) *memDependencyRepo {
+	m := &memDependencyRepo{todos: make(map[string]model.Todo)}
+	for _, t := range todos {
+		m.todos[t.ID] = t
+	}
+	return m
+}
+
+func (m *memDependencyRepo) Add(ctx context.Context, userID string, dep model.TodoDependency, check func([]model.TodoDependency) error) error {
+	if err := check(m.edges); err != nil {
+		return err
+	}
+	m.edges = append(m.edges, dep)
+	return nil
+}
+
+func (m *memDependencyRepo) Remove(ctx context.Context, userID string, dep model.TodoDependency) error {
+	for i, e := range m.edges {
+		if e == dep {
+			m.edges = append(m.edges[:i], m.edges[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *memDependencyRepo) ListBlockers(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	var out []model.Todo
+	for _, e := range m.edges {
+		if e.TodoID == todoID {
+			out = append(out, m.todos[e.BlockerID])
+		}
+	}
+	return out, nil
+}
+
+func (m *memDependencyRepo) ListBlocking(ctx context.Context, userID, todoID string) ([]model.Todo, error) {
+	var out []model.Todo
+	for _, e := range m.edges {
+		if e.BlockerID == todoID {
+			out = append(out, m.todos[e.TodoID])
+		}
+	}
+	return out, nil
+}
+
+func (m *memDependencyRepo) ListEdges(ctx context.Context, userID string) ([]model.TodoDependency, error) {
+	return m.edges, nil
+}
+
+func (m *memDependencyRepo) ListNodes(ctx context.Context, userID string) ([]model.Todo, error) {
+	seen := make(map[string]bool)
+	var out []model.Todo
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		for _, e := range m.edges {
+			if (e.TodoID == id || e.BlockerID == id) && !seen[id] {
+				seen[id] = true
+				out = append(out, m.todos[id])
+			}
+		}
+	}
+	return out, nil
+}
+
+func todoWithID(id string, status model.TodoStatus) model.Todo {
+	t := sampleTodo()
+	t.ID = id
+	t.Status = status
+	return t
+}
+
+func newDependencyTestService(deps *memDependencyRepo) *service.TodoService {
+	repo := &mockTodoRepo{
+		getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+			if t, ok := deps.todos[todoID]; ok {
+				return t, nil
+			}
+			return model.Todo{}, fmt.Errorf("scan: %w", sql.ErrNoRows)
+		},
+		updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			return todo, nil
+		},
+	}
+	return service.NewTodoService(repo, service.WithDependencies(deps))
+}
+
+func TestAddBlocker(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []model.TodoDependency
+		todoID    string
+		blockerID string
+		wantErr   error
+	}{
+		{name: "success", todoID: "b", blockerID: "a"},
+		{name: "self dependency", todoID: "a", blockerID: "a", wantErr: service.ErrInvalidInput},
+		{name: "missing blocker id", todoID: "a", blockerID: "", wantErr: service.ErrInvalidInput},
+		{name: "unknown blocker", todoID: "a", blockerID: "zzz", wantErr: service.ErrNotFound},
+		{
+			name:      "direct cycle",
+			existing:  []model.TodoDependency{{TodoID: "b", BlockerID: "a"}},
+			todoID:    "a",
+			blockerID: "b",
+			wantErr:   service.ErrConflict,
+		},
+		{
+			name: "transitive cycle",
+			existing: []model.TodoDependency{
+				{TodoID: "b", BlockerID: "a"},
+				{TodoID: "c", BlockerID: "b"},
+			},
+			todoID:    "a",
+			blockerID: "c",
+			wantErr:   service.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newMemDependencyRepo(
+				todoWithID("a", model.TodoStatusPending),
+				todoWithID("b", model.TodoStatusPending),
+				todoWithID("c", model.TodoStatusPending),
+			)
+			deps.edges = append(deps.edges, tt.existing...)
+			svc := newDependencyTestService(deps)
+
+			got, err := svc.AddBlocker(context.Background(), "user-1", tt.todoID, tt.blockerID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got.BlockedBy) != 1 || got.BlockedBy[0] != tt.blockerID {
+				t.Errorf("expected blocked_by=[%s], got %v", tt.blockerID, got.BlockedBy)
+			}
+		})
+	}
+}
+
+func TestRemoveBlocker(t *testing.T) {
+	deps := newMemDependencyRepo(todoWithID("a", model.TodoStatusPending), todoWithID("b", model.TodoStatusPending))
+	deps.edges = []model.TodoDependency{{TodoID: "b", BlockerID: "a"}}
+	svc := newDependencyTestService(deps)
+
+	if err := svc.RemoveBlocker(context.Background(), "user-1", "b", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RemoveBlocker(context.Background(), "user-1", "b", "a"); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on second removal, got %v", err)
+	}
+}
+
+func TestUpdateStatus_Blocked(t *testing.T) {
+	tests := []struct {
+		name          string
+		blockerStatus model.TodoStatus
+		force         bool
+		wantErr       error
+	}{
+		{"open blocker", model.TodoStatusPending, false, service.ErrConflict},
+		{"open blocker forced", model.TodoStatusPending, true, nil},
+		{"completed blocker", model.TodoStatusCompleted, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := newMemDependencyRepo(todoWithID("a", tt.blockerStatus), todoWithID("b", model.TodoStatusPending))
+			deps.edges = []model.TodoDependency{{TodoID: "b", BlockerID: "a"}}
+			svc := newDependencyTestService(deps)
+
+			_, err := svc.UpdateStatus(context.Background(), "user-1", "b", model.TodoStatusCompleted, tt.force)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	deps := newMemDependencyRepo(
+		todoWithID("a", model.TodoStatusPending),
+		todoWithID("b", model.TodoStatusPending),
+		todoWithID("c", model.TodoStatusPending),
+		todoWithID("d", model.TodoStatusPending),
+	)
+	// d <- c <- a, d <- b: a and b must come after their blockers.
+	deps.edges = []model.TodoDependency{
+		{TodoID: "a", BlockerID: "c"},
+		{TodoID: "c", BlockerID: "d"},
+		{TodoID: "b", BlockerID: "d"},
+	}
+	svc := newDependencyTestService(deps)
+
+	graph, err := svc.DependencyGraph(context.Background(), "user-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pos := make(map[string]int)
+	for i, todo := range graph.Todos {
+		pos[todo.ID] = i
+	}
+	if len(pos) != 4 {
+		t.Fatalf("expected 4 nodes, got %d", len(pos))
+	}
+	for _, e := range graph.Edges {
+		if pos[e.BlockerID] > pos[e.TodoID] {
+			t.Errorf("blocker %s appears after %s", e.BlockerID, e.TodoID)
+		}
+	}
+}
+
+func TestDependencyGraph_Component(t *testing.T) {
+	deps := newMemDependencyRepo(
+		todoWithID("a", model.TodoStatusPending),
+		todoWithID("b", model.TodoStatusPending),
+		todoWithID("c", model.TodoStatusPending),
+		todoWithID("d", model.TodoStatusPending),
+		todoWithID("e", model.TodoStatusPending),
+	)
+	// Two plans: b <- a <- c and e <- d. From a blocker, the rest of its
+	// plan is only reached by following edges both ways.
+	deps.edges = []model.TodoDependency{
+		{TodoID: "a", BlockerID: "b"},
+		{TodoID: "c", BlockerID: "a"},
+		{TodoID: "d", BlockerID: "e"},
+	}
+	svc := newDependencyTestService(deps)
+
+	tests := []struct {
+		name      string
+		todoID    string
+		wantTodos []string
+		wantEdges int
+		wantErr   error
+	}{
+		{"first plan from the middle", "a", []string{"b", "a", "c"}, 2, nil},
+		{"second plan from a blocker", "e", []string{"e", "d"}, 1, nil},
+		{"missing todo", "x", nil, 0, service.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph, err := svc.DependencyGraph(context.Background(), "user-1", tt.todoID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, todo := range graph.Todos {
+				got = append(got, todo.ID)
+			}
+			if !slices.Equal(got, tt.wantTodos) {
+				t.Errorf("expected todos %v, got %v", tt.wantTodos, got)
+			}
+			if len(graph.Edges) != tt.wantEdges {
+				t.Errorf("expected %d edges, got %d", tt.wantEdges, len(graph.Edges))
+			}
+		})
+	}
+}
+
+func TestDependencyGraph_Isolated(t *testing.T) {
+	deps := newMemDependencyRepo(
+		todoWithID("a", model.TodoStatusPending),
+		todoWithID("b", model.TodoStatusPending),
+		todoWithID("c", model.TodoStatusPending),
+	)
+	deps.edges = []model.TodoDependency{{TodoID: "a", BlockerID: "b"}}
+	svc := newDependencyTestService(deps)
+
+	graph, err := svc.DependencyGraph(context.Background(), "user-1", "c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graph.Todos) != 1 || graph.Todos[0].ID != "c" || len(graph.Edges) != 0 {
+		t.Errorf("expected c alone, got %+v", graph)
+	}
+}
+
+func TestDependencyGraph_Cycle(t *testing.T) {
+	deps := newMemDependencyRepo(
+		todoWithID("a", model.TodoStatusPending),
+		todoWithID("b", model.TodoStatusPending),
+	)
+	// Written directly, as a race between two adds could have.
+	deps.edges = []model.TodoDependency{
+		{TodoID: "a", BlockerID: "b"},
+		{TodoID: "b", BlockerID: "a"},
+	}
+	svc := newDependencyTestService(deps)
+
+	if _, err := svc.DependencyGraph(context.Background(), "user-1", ""); !errors.Is(err, service.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestDependencies_Disabled(t *testing.T) {
+	svc := service.NewTodoService(&mockTodoRepo{})
+
+	if _, err := svc.AddBlocker(context.Background(), "user-1", "a", "b"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("AddBlocker: expected ErrNotFound, got %v", err)
+	}
+	if _, err := svc.DependencyGraph(context.Background(), "user-1", ""); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("DependencyGraph: expected ErrNotFound, got %v", err)
+	}
+}
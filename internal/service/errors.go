@@ -6,4 +6,5 @@ var (
 	ErrNotFound       = errors.New("not found")
 	ErrInvalidInput   = errors.New("invalid input")
 	ErrForbidden      = errors.New("forbidden")
+	ErrConflict       = errors.New("conflict")
 )
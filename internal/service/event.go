@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// EventPublisher receives the domain events raised by TodoService mutations.
+// Publish is called synchronously after the change is stored, so
+// implementations must hand slow work off rather than block the request.
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.Event)
+}
+
+// WithEventPublisher subscribes p to todo events. It may be given more than
+// once; publishers are called in registration order.
+func WithEventPublisher(p EventPublisher) TodoServiceOption {
+	return func(s *TodoService) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+func (s *TodoService) publish(ctx context.Context, eventType model.EventType, userID, todoID string, todo *model.Todo) {
+	if len(s.publishers) == 0 {
+		return
+	}
+
+	event := model.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		UserID:     userID,
+		TodoID:     todoID,
+		Todo:       todo,
+		OccurredAt: time.Now().UTC(),
+	}
+	for _, p := range s.publishers {
+		p.Publish(ctx, event)
+	}
+}
+
+// statusEvent returns the event raised when a todo moves to status.
+func statusEvent(status model.TodoStatus) model.EventType {
+	if status == model.TodoStatusCompleted {
+		return model.EventTodoCompleted
+	}
+	return model.EventTodoReopened
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
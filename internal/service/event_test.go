@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+type recordingPublisher struct {
+	events []model.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event model.Event) {
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) types() []model.EventType {
+	types := make([]model.EventType, len(p.events))
+	for i, e := range p.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestTodoService_PublishesEvents(t *testing.T) {
+	stored := sampleTodo()
+	repo := &mockTodoRepo{
+		createFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			todo.ID = stored.ID
+			stored = todo
+			return todo, nil
+		},
+		getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+			return stored, nil
+		},
+		updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			stored = todo
+			return todo, nil
+		},
+		deleteFn: func(ctx context.Context, userID, todoID string) error {
+			return nil
+		},
+	}
+	pub := &recordingPublisher{}
+	svc := service.NewTodoService(repo, service.WithEventPublisher(pub))
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "user-1", service.CreateTodoInput{Title: "Buy groceries"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Update(ctx, "user-1", "todo-1", service.UpdateTodoInput{Title: strPtr("Buy milk")}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := svc.UpdateStatus(ctx, "user-1", "todo-1", model.TodoStatusCompleted, false); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	// Completing an already completed todo is not a change.
+	if _, err := svc.UpdateStatus(ctx, "user-1", "todo-1", model.TodoStatusCompleted, false); err != nil {
+		t.Fatalf("complete again: %v", err)
+	}
+	if _, err := svc.UpdateStatus(ctx, "user-1", "todo-1", model.TodoStatusPending, false); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if err := svc.Delete(ctx, "user-1", "todo-1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []model.EventType{
+		model.EventTodoCreated,
+		model.EventTodoUpdated,
+		model.EventTodoCompleted,
+		model.EventTodoReopened,
+		model.EventTodoDeleted,
+	}
+	got := pub.types()
+	if len(got) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+
+	for _, e := range pub.events {
+		if e.ID == "" || e.UserID != "user-1" || e.TodoID != "todo-1" {
+			t.Errorf("incomplete event: %+v", e)
+		}
+	}
+	if pub.events[1].Todo == nil || pub.events[1].Todo.Title != "Buy milk" {
+		t.Errorf("expected updated todo snapshot, got %+v", pub.events[1].Todo)
+	}
+	if pub.events[4].Todo != nil {
+		t.Error("expected no todo snapshot on delete")
+	}
+}
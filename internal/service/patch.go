@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// patchDocument is the todo that patches are applied to. Nullable fields are
+// always present, so that a JSON Patch can test or remove them. The read-only
+// fields are there for test operations and must not be changed.
+type patchDocument struct {
+	ID        string           `json:"id"`
+	Status    model.TodoStatus `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	Priority        model.TodoPriority `json:"priority"`
+	Important       bool               `json:"important"`
+	Urgent          bool               `json:"urgent"`
+	DueAt           *time.Time         `json:"due_at"`
+	EstimateMinutes *int               `json:"estimate_minutes"`
+}
+
+var readOnlyPatchFields = []string{"id", "status", "created_at", "updated_at"}
+
+var editablePatchFields = []string{"title", "description", "priority", "important", "urgent", "due_at", "estimate_minutes"}
+
+const (
+	// maxPatchOperations bounds a JSON Patch. A todo has few fields, so
+	// longer patches only do work for nothing.
+	maxPatchOperations = 100
+	// maxPatchCopySize bounds what copy operations may add to the document,
+	// in case one slips past the operation check.
+	maxPatchCopySize = 1 << 20
+	// maxPatchAttempts is how many times a patch is applied to a todo that
+	// keeps changing underneath it before giving up with a conflict.
+	maxPatchAttempts = 3
+)
+
+// MergePatch updates a todo with an RFC 7396 JSON Merge Patch. Members set
+// to null are removed: due_at and estimate_minutes are cleared, and the other
+// editable fields return to their defaults, except title, which is required.
+func (s *TodoService) MergePatch(ctx context.Context, userID, todoID string, patch []byte) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.MergePatch")
+	defer span.End()
+
+	return s.patch(ctx, userID, todoID, func(doc []byte) ([]byte, error) {
+		patched, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid merge patch", ErrInvalidInput)
+		}
+		return patched, nil
+	})
+}
+
+// JSONPatch updates a todo with an RFC 6902 JSON Patch. Removed members are
+// treated like null members of a merge patch. Operations may only target the
+// todo's top-level fields, and copy and move are not supported. A failed test
+// operation is a conflict, so clients can test updated_at to guard against
+// lost updates: the patched todo is only saved if it has not changed since
+// the test ran.
+func (s *TodoService) JSONPatch(ctx context.Context, userID, todoID string, patch []byte) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.JSONPatch")
+	defer span.End()
+
+	ops, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("%w: invalid JSON patch", ErrInvalidInput)
+	}
+	if err := checkPatchOperations(ops); err != nil {
+		return model.Todo{}, err
+	}
+
+	opts := jsonpatch.NewApplyOptions()
+	opts.AccumulatedCopySizeLimit = maxPatchCopySize
+	return s.patch(ctx, userID, todoID, func(doc []byte) ([]byte, error) {
+		patched, err := ops.ApplyWithOptions(doc, opts)
+		switch {
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			return nil, fmt.Errorf("%w: patch test failed", ErrConflict)
+		case err != nil:
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		return patched, nil
+	})
+}
+
+// checkPatchOperations rejects operations that patchedInput could not make
+// sense of before any are applied.
+func checkPatchOperations(ops jsonpatch.Patch) error {
+	if len(ops) > maxPatchOperations {
+		return fmt.Errorf("%w: at most %d operations per JSON patch", ErrInvalidInput, maxPatchOperations)
+	}
+
+	var errs validate.Errors
+	for i, op := range ops {
+		kind := op.Kind()
+		switch kind {
+		case "add", "remove", "replace", "test":
+		default:
+			return fmt.Errorf("%w: operation %d: unsupported op %q", ErrInvalidInput, i, kind)
+		}
+		path, err := op.Path()
+		if err != nil || !strings.HasPrefix(path, "/") || strings.Count(path, "/") != 1 {
+			return fmt.Errorf("%w: operation %d: path must name a todo field, such as /title", ErrInvalidInput, i)
+		}
+		field := path[1:]
+		switch {
+		case slices.Contains(editablePatchFields, field):
+		case slices.Contains(readOnlyPatchFields, field):
+			if kind != "test" {
+				errs = append(errs, validate.FieldError{Field: field, Reason: validate.ReasonReadOnly})
+			}
+		default:
+			errs = append(errs, validate.FieldError{Field: field, Reason: validate.ReasonUnknownField})
+		}
+	}
+	if len(errs) > 0 {
+		return invalidInput(errs)
+	}
+	return nil
+}
+
+// patch applies a patch to the stored todo and saves the result if the todo
+// has not changed in the meantime, starting over if it has.
+func (s *TodoService) patch(ctx context.Context, userID, todoID string, apply func(doc []byte) ([]byte, error)) (model.Todo, error) {
+	for attempt := 1; ; attempt++ {
+		existing, input, err := s.patchOnce(ctx, userID, todoID, apply)
+		if err != nil {
+			return model.Todo{}, err
+		}
+
+		updated, err := s.saveIfUnchanged(ctx, userID, existing, input, nil, s.now())
+		if errors.Is(err, errTodoChanged) {
+			if attempt < maxPatchAttempts {
+				continue
+			}
+			return model.Todo{}, fmt.Errorf("%w: todo changed while being patched", ErrConflict)
+		}
+		return updated, err
+	}
+}
+
+// patchOnce reads the todo and applies the patch to it, returning the todo
+// as read and the validated update the patch makes.
+func (s *TodoService) patchOnce(ctx context.Context, userID, todoID string, apply func(doc []byte) ([]byte, error)) (model.Todo, UpdateTodoInput, error) {
+	existing, err := s.repo.GetByID(ctx, userID, todoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Todo{}, UpdateTodoInput{}, ErrNotFound
+		}
+		return model.Todo{}, UpdateTodoInput{}, fmt.Errorf("failed to get todo for patch: %w", err)
+	}
+
+	doc, err := json.Marshal(patchDocument{
+		ID:              existing.ID,
+		Status:          existing.Status,
+		CreatedAt:       existing.CreatedAt,
+		UpdatedAt:       existing.UpdatedAt,
+		Title:           existing.Title,
+		Description:     existing.Description,
+		Priority:        existing.Priority,
+		Important:       existing.Important,
+		Urgent:          existing.Urgent,
+		DueAt:           existing.DueAt,
+		EstimateMinutes: existing.EstimateMinutes,
+	})
+	if err != nil {
+		return model.Todo{}, UpdateTodoInput{}, fmt.Errorf("failed to encode todo for patch: %w", err)
+	}
+
+	patched, err := apply(doc)
+	if err != nil {
+		return model.Todo{}, UpdateTodoInput{}, err
+	}
+	input, err := patchedInput(doc, patched)
+	if err != nil {
+		return model.Todo{}, UpdateTodoInput{}, err
+	}
+	if err := input.validate(); err != nil {
+		return model.Todo{}, UpdateTodoInput{}, err
+	}
+	return existing, input, nil
+}
+
+// patchedInput turns a patched document back into an update of every
+// editable field, reporting unknown members and changed read-only ones.
+func patchedInput(original, patched []byte) (UpdateTodoInput, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(original, &before); err != nil {
+		return UpdateTodoInput{}, fmt.Errorf("failed to decode todo for patch: %w", err)
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return UpdateTodoInput{}, fmt.Errorf("%w: patched todo must be a JSON object", ErrInvalidInput)
+	}
+
+	var errs validate.Errors
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			errs = append(errs, validate.FieldError{Field: name, Reason: validate.ReasonUnknownField})
+		}
+	}
+	for _, name := range readOnlyPatchFields {
+		if !jsonEqual(before[name], after[name]) {
+			errs = append(errs, validate.FieldError{Field: name, Reason: validate.ReasonReadOnly})
+		}
+	}
+
+	var input UpdateTodoInput
+	if raw, ok := after["title"]; !ok || string(raw) == "null" {
+		errs = append(errs, validate.FieldError{Field: "title", Reason: validate.ReasonRequired})
+	}
+	input.Title = patchField[string](after, "title", &errs)
+	input.Description = orDefault(patchField[string](after, "description", &errs))
+	input.Priority = orDefault(patchField[string](after, "priority", &errs))
+	input.Important = orDefault(patchField[bool](after, "important", &errs))
+	input.Urgent = orDefault(patchField[bool](after, "urgent", &errs))
+	input.DueAt = patchField[string](after, "due_at", &errs)
+	input.ClearDueAt = input.DueAt == nil
+	input.EstimateMinutes = patchField[int](after, "estimate_minutes", &errs)
+	input.ClearEstimate = input.EstimateMinutes == nil
+
+	if len(errs) > 0 {
+		return UpdateTodoInput{}, invalidInput(errs)
+	}
+	return input, nil
+}
+
+// patchField decodes a member of a patched document. It returns nil if the
+// member is missing, null or of the wrong type, reporting the last.
+func patchField[T any](doc map[string]json.RawMessage, name string, errs *validate.Errors) *T {
+	raw, ok := doc[name]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		*errs = append(*errs, validate.FieldError{Field: name, Reason: validate.ReasonInvalidType})
+		return nil
+	}
+	return &v
+}
+
+func orDefault[T any](p *T) *T {
+	if p == nil {
+		return new(T)
+	}
+	return p
+}
+
+// jsonEqual compares JSON values regardless of how they are encoded.
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,306 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+func TestTodoService_Patch(t *testing.T) {
+	due := time.Date(2025, 12, 31, 9, 0, 0, 0, time.UTC)
+	estimate := 30
+	existing := sampleTodo()
+	existing.Priority = model.TodoPriorityHigh
+	existing.Important = true
+	existing.DueAt = &due
+	existing.EstimateMinutes = &estimate
+
+	updatedAt, _ := json.Marshal(existing.UpdatedAt)
+
+	tests := []struct {
+		name       string
+		json       bool // JSON Patch rather than Merge Patch
+		patch      string
+		wantErr    error
+		wantFields []validate.FieldError
+		check      func(t *testing.T, got model.Todo)
+	}{
+		{
+			name:  "merge patch changes the given fields",
+			patch: `{"title": "Buy milk", "urgent": true}`,
+			check: func(t *testing.T, got model.Todo) {
+				if got.Title != "Buy milk" || !got.Urgent {
+					t.Errorf("expected title and urgent to change, got %+v", got)
+				}
+				if got.Description != existing.Description || got.Priority != model.TodoPriorityHigh || got.DueAt == nil {
+					t.Errorf("expected other fields to be kept, got %+v", got)
+				}
+			},
+		},
+		{
+			name:  "merge patch null clears nullable fields",
+			patch: `{"due_at": null, "estimate_minutes": null}`,
+			check: func(t *testing.T, got model.Todo) {
+				if got.DueAt != nil || got.EstimateMinutes != nil {
+					t.Errorf("expected due_at and estimate_minutes to be cleared, got %v, %v", got.DueAt, got.EstimateMinutes)
+				}
+			},
+		},
+		{
+			name:  "merge patch null resets defaults",
+			patch: `{"description": null, "priority": null, "important": null}`,
+			check: func(t *testing.T, got model.Todo) {
+				if got.Description != "" || got.Priority != model.TodoPriorityNone || got.Important {
+					t.Errorf("expected defaults, got %+v", got)
+				}
+			},
+		},
+		{
+			name:       "merge patch cannot remove the title",
+			patch:      `{"title": null}`,
+			wantFields: []validate.FieldError{{Field: "title", Reason: validate.ReasonRequired}},
+		},
+		{
+			name:       "merge patch rejects unknown and read-only fields",
+			patch:      `{"colour": "red", "status": "completed"}`,
+			wantFields: []validate.FieldError{{Field: "colour", Reason: validate.ReasonUnknownField}, {Field: "status", Reason: validate.ReasonReadOnly}},
+		},
+		{
+			name:       "merge patch checks types",
+			patch:      `{"estimate_minutes": 1.5}`,
+			wantFields: []validate.FieldError{{Field: "estimate_minutes", Reason: validate.ReasonInvalidType}},
+		},
+		{
+			name:       "merge patch validates values",
+			patch:      `{"due_at": "tomorrow"}`,
+			wantFields: []validate.FieldError{{Field: "due_at", Reason: validate.ReasonInvalidDateTime}},
+		},
+		{
+			name:    "malformed merge patch",
+			patch:   `{"title":`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:  "json patch with a passing test",
+			json:  true,
+			patch: `[{"op": "test", "path": "/updated_at", "value": ` + string(updatedAt) + `}, {"op": "replace", "path": "/title", "value": "Buy milk"}, {"op": "remove", "path": "/due_at"}]`,
+			check: func(t *testing.T, got model.Todo) {
+				if got.Title != "Buy milk" || got.DueAt != nil {
+					t.Errorf("expected new title and no due_at, got %+v", got)
+				}
+				if got.EstimateMinutes == nil || *got.EstimateMinutes != estimate {
+					t.Errorf("expected estimate_minutes to be kept, got %v", got.EstimateMinutes)
+				}
+			},
+		},
+		{
+			name:    "json patch with a failing test",
+			json:    true,
+			patch:   `[{"op": "test", "path": "/updated_at", "value": "2000-01-01T00:00:00Z"}, {"op": "replace", "path": "/title", "value": "Buy milk"}]`,
+			wantErr: service.ErrConflict,
+		},
+		{
+			name:    "json patch on a missing path",
+			json:    true,
+			patch:   `[{"op": "replace", "path": "/colour", "value": "red"}]`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:       "json patch with a mistyped value",
+			json:       true,
+			patch:      `[{"op": "replace", "path": "/important", "value": "yes"}]`,
+			wantFields: []validate.FieldError{{Field: "important", Reason: validate.ReasonInvalidType}},
+		},
+		{
+			name:    "json patch copy is not supported",
+			json:    true,
+			patch:   `[{"op": "copy", "from": "/description", "path": "/title"}]`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:    "json patch move is not supported",
+			json:    true,
+			patch:   `[{"op": "move", "from": "/description", "path": "/title"}]`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:    "json patch into a field",
+			json:    true,
+			patch:   `[{"op": "add", "path": "/title/0", "value": "x"}]`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:       "json patch rejects read-only and unknown paths before applying",
+			json:       true,
+			patch:      `[{"op": "replace", "path": "/status", "value": "completed"}, {"op": "add", "path": "/colour", "value": "red"}]`,
+			wantFields: []validate.FieldError{{Field: "status", Reason: validate.ReasonReadOnly}, {Field: "colour", Reason: validate.ReasonUnknownField}},
+		},
+		{
+			name:    "json patch with too many operations",
+			json:    true,
+			patch:   `[` + strings.Repeat(`{"op": "test", "path": "/title", "value": "Buy groceries"},`, 100) + `{"op": "remove", "path": "/due_at"}]`,
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:    "malformed json patch",
+			json:    true,
+			patch:   `{"op": "remove"}`,
+			wantErr: service.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *model.Todo
+			repo := &mockTodoRepo{
+				getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+					return existing, nil
+				},
+				updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+					saved = &todo
+					return todo, nil
+				},
+			}
+			svc := service.NewTodoService(repo)
+
+			var got model.Todo
+			var err error
+			if tt.json {
+				got, err = svc.JSONPatch(context.Background(), "user-1", "todo-1", []byte(tt.patch))
+			} else {
+				got, err = svc.MergePatch(context.Background(), "user-1", "todo-1", []byte(tt.patch))
+			}
+
+			if tt.wantErr != nil || tt.wantFields != nil {
+				if saved != nil {
+					t.Error("expected nothing to be saved")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if tt.wantFields != nil {
+					var fields validate.Errors
+					if !errors.As(err, &fields) {
+						t.Fatalf("expected field errors, got %v", err)
+					}
+					if !sameFieldErrors(fields, tt.wantFields) {
+						t.Errorf("expected %v, got %v", tt.wantFields, fields)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestTodoService_Patch_ConcurrentChange(t *testing.T) {
+	stored := sampleTodo()
+	updatedAt, _ := json.Marshal(stored.UpdatedAt)
+
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr error
+	}{
+		{"patch is applied again", `[{"op": "replace", "path": "/title", "value": "Buy milk"}]`, nil},
+		{"test of updated_at now fails", `[{"op": "test", "path": "/updated_at", "value": ` + string(updatedAt) + `}, {"op": "replace", "path": "/title", "value": "Buy milk"}]`, service.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := stored
+			reads, writes := 0, 0
+			repo := &mockTodoRepo{
+				getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+					reads++
+					return current, nil
+				},
+				updateIfUnchangedFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+					writes++
+					if writes == 1 {
+						// Another request got there first.
+						current.Description = "changed elsewhere"
+						current.UpdatedAt = current.UpdatedAt.Add(time.Second)
+					}
+					if !todo.UpdatedAt.Equal(current.UpdatedAt) {
+						return model.Todo{}, sql.ErrNoRows
+					}
+					return todo, nil
+				},
+			}
+
+			got, err := service.NewTodoService(repo).JSONPatch(context.Background(), "user-1", "todo-1", []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reads != 2 {
+				t.Errorf("expected the todo to be read again, got %d reads", reads)
+			}
+			if got.Title != "Buy milk" || got.Description != "changed elsewhere" {
+				t.Errorf("expected the patch applied to the latest todo, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestTodoService_Patch_KeepsChanging(t *testing.T) {
+	repo := &mockTodoRepo{
+		getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+			return sampleTodo(), nil
+		},
+		updateIfUnchangedFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			return model.Todo{}, sql.ErrNoRows
+		},
+	}
+
+	_, err := service.NewTodoService(repo).MergePatch(context.Background(), "user-1", "todo-1", []byte(`{"title": "Buy milk"}`))
+	if !errors.Is(err, service.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestTodoService_Patch_NotFound(t *testing.T) {
+	repo := &mockTodoRepo{
+		getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+			return model.Todo{}, sql.ErrNoRows
+		},
+	}
+	svc := service.NewTodoService(repo)
+
+	_, err := svc.MergePatch(context.Background(), "user-1", "todo-1", []byte(`{"title": "Buy milk"}`))
+	if !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// sameFieldErrors compares field errors in any order, as patched documents
+// are walked as maps.
+func sameFieldErrors(got validate.Errors, want []validate.FieldError) bool {
+	count := func(errs []validate.FieldError) map[validate.FieldError]int {
+		m := make(map[validate.FieldError]int)
+		for _, e := range errs {
+			m[e]++
+		}
+		return m
+	}
+	return reflect.DeepEqual(count(got), count(want))
+}
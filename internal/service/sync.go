@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+)
+
+// MaxSyncMutations is the largest batch accepted by ApplyMutations.
+const MaxSyncMutations = 100
+
+// maxSyncAttempts is how many times a mutation is checked and written when
+// the todo keeps changing in between.
+const maxSyncAttempts = 3
+
+const syncTokenVersion = "v1"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// SyncMutation is one offline change sent by a client. Fields holds only the
+// fields the client changed. Status is written together with them.
+//
+// BaseUpdatedAt is the updated_at of the client's copy before the change;
+// the todo is in conflict if the server copy has changed since. ModifiedAt is
+// when the client made the change: it decides which write wins each field
+// of a merge, and is recorded as the time the fields it writes changed.
+type SyncMutation struct {
+	Op            model.SyncOp
+	ClientID      string // client's temporary ID for creates, echoed back
+	ID            string
+	Fields        UpdateTodoInput
+	Status        *string
+	BaseUpdatedAt *time.Time
+	ModifiedAt    *time.Time
+	OnConflict    model.ConflictStrategy // defaults to merge
+}
+
+// SyncService serves offline-first clients: a change feed to pull from and
+// batched mutations to push.
+type SyncService struct {
+	todos     *TodoService
+	changes   repository.TodoChangeRepository
+	retention time.Duration
+	now       func() time.Time
+}
+
+// SyncServiceOption configures optional SyncService behavior.
+type SyncServiceOption func(*SyncService)
+
+// WithTombstoneRetention keeps deleted todos' tombstones for d before
+// PruneTombstones may delete them. Clients that have not synced for longer
+// than d must sync again from scratch. Without it tombstones are kept forever.
+func WithTombstoneRetention(d time.Duration) SyncServiceOption {
+	return func(s *SyncService) {
+		s.retention = d
+	}
+}
+
+// NewSyncService creates a new SyncService. Mutations go through todos so
+// they are validated and raise events like any other write.
+func NewSyncService(todos *TodoService, changes repository.TodoChangeRepository, opts ...SyncServiceOption) *SyncService {
+	s := &SyncService{todos: todos, changes: changes, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Changes returns the todos created, updated or deleted since token. An
+// empty token starts from the beginning. Clients call again with NextToken
+// while HasMore is set, and keep NextToken for their next sync.
+//
+// A token older than the tombstone retention is a conflict: deletions since
+// it may have been forgotten, so the client must discard its copy and sync
+// again without a token.
+func (s *SyncService) Changes(ctx context.Context, userID, token string, limit int) (model.SyncResult, error) {
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	changes, err := s.changes.Changes(ctx, userID, since, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrSyncPositionPruned) {
+			return model.SyncResult{}, fmt.Errorf("%w: sync token has expired; sync again without a token", ErrConflict)
+		}
+		return model.SyncResult{}, fmt.Errorf("failed to list changes: %w", err)
+	}
+
+	return model.SyncResult{
+		Todos:     changes.Todos,
+		Deleted:   changes.Deleted,
+		NextToken: encodeSyncToken(changes.Next),
+		HasMore:   changes.HasMore,
+	}, nil
+}
+
+// PruneTombstones deletes tombstones older than the retention and returns
+// how many were deleted. It does nothing without WithTombstoneRetention.
+func (s *SyncService) PruneTombstones(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	n, err := s.changes.PruneTombstones(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune tombstones: %w", err)
+	}
+	return n, nil
+}
+
+// RunTombstoneCleanup calls PruneTombstones every interval until ctx is
+// cancelled.
+func (s *SyncService) RunTombstoneCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PruneTombstones(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "tombstone cleanup failed", "error", err)
+				continue
+			}
+			if n > 0 {
+				slog.InfoContext(ctx, "pruned tombstones", "count", n)
+			}
+		}
+	}
+}
+
+// ApplyMutations applies a batch of client mutations in order. Each
+// mutation succeeds or fails on its own; only a malformed batch is an error.
+func (s *SyncService) ApplyMutations(ctx context.Context, userID string, mutations []SyncMutation) ([]model.MutationResult, error) {
+	if len(mutations) == 0 {
+		return nil, fmt.Errorf("%w: mutations must not be empty", ErrInvalidInput)
+	}
+	if len(mutations) > MaxSyncMutations {
+		return nil, fmt.Errorf("%w: at most %d mutations per batch", ErrInvalidInput, MaxSyncMutations)
+	}
+
+	results := make([]model.MutationResult, len(mutations))
+	for i, m := range mutations {
+		result, err := s.apply(ctx, userID, m)
+		if err != nil {
+			// Keep the ID of a todo created before a later step failed.
+			result = model.MutationResult{ID: result.ID, Status: model.MutationFailed, Error: mutationError(err)}
+		}
+		result.Index = i
+		result.ClientID = m.ClientID
+		if result.ID == "" {
+			result.ID = m.ID
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (s *SyncService) apply(ctx context.Context, userID string, m SyncMutation) (model.MutationResult, error) {
+	if m.OnConflict == "" {
+		m.OnConflict = model.ConflictMerge
+	}
+	if !m.OnConflict.IsValid() {
+		return model.MutationResult{}, fmt.Errorf("%w: invalid on_conflict %q", ErrInvalidInput, m.OnConflict)
+	}
+
+	switch m.Op {
+	case model.SyncOpCreate:
+		return s.applyCreate(ctx, userID, m)
+	case model.SyncOpUpdate, model.SyncOpDelete:
+		if m.ID == "" {
+			return model.MutationResult{}, fmt.Errorf("%w: id is required", ErrInvalidInput)
+		}
+		if m.BaseUpdatedAt == nil || m.ModifiedAt == nil {
+			return model.MutationResult{}, fmt.Errorf("%w: base_updated_at and modified_at are required", ErrInvalidInput)
+		}
+		if m.Op == model.SyncOpUpdate {
+			return s.applyUpdate(ctx, userID, m)
+		}
+		return s.applyDelete(ctx, userID, m)
+	default:
+		return model.MutationResult{}, fmt.Errorf("%w: invalid op %q", ErrInvalidInput, m.Op)
+	}
+}
+
+func (s *SyncService) applyCreate(ctx context.Context, userID string, m SyncMutation) (model.MutationResult, error) {
+	f := m.Fields
+	input := CreateTodoInput{DueAt: f.DueAt, EstimateMinutes: f.EstimateMinutes}
+	if f.Title != nil {
+		input.Title = *f.Title
+	}
+	if f.Description != nil {
+		input.Description = *f.Description
+	}
+	if f.Priority != nil {
+		input.Priority = *f.Priority
+	}
+	if f.Important != nil {
+		input.Important = *f.Important
+	}
+	if f.Urgent != nil {
+		input.Urgent = *f.Urgent
+	}
+
+	todo, err := s.todos.Create(ctx, userID, input)
+	if err != nil {
+		return model.MutationResult{}, err
+	}
+	if m.Status != nil && model.TodoStatus(*m.Status) != todo.Status {
+		updated, err := s.todos.UpdateStatus(ctx, userID, todo.ID, model.TodoStatus(*m.Status), false)
+		if err != nil {
+			return model.MutationResult{ID: todo.ID}, err
+		}
+		todo = updated
+	}
+	return model.MutationResult{ID: todo.ID, Status: model.MutationApplied, Todo: &todo}, nil
+}
+
+// applyUpdate writes the fields the client changed. If the server copy has
+// changed since the client's base, ConflictReject returns it untouched, and
+// ConflictMerge keeps each field the client changed after the server last
+// changed that field; if it keeps none, the mutation is a conflict. The
+// write is made only if the todo is still as checked, and the check is
+// repeated if it is not.
+func (s *SyncService) applyUpdate(ctx context.Context, userID string, m SyncMutation) (model.MutationResult, error) {
+	if err := m.Fields.validate(); err != nil {
+		return model.MutationResult{}, err
+	}
+	var status *model.TodoStatus
+	if m.Status != nil {
+		st := model.TodoStatus(*m.Status)
+		if err := validateStatus(st); err != nil {
+			return model.MutationResult{}, err
+		}
+		status = &st
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, err := s.todos.GetByID(ctx, userID, m.ID)
+		if err != nil {
+			return model.MutationResult{}, err
+		}
+
+		result, fields, newStatus := model.MutationApplied, m.Fields, status
+		if current.UpdatedAt.After(*m.BaseUpdatedAt) {
+			if m.OnConflict == model.ConflictReject {
+				return model.MutationResult{Status: model.MutationConflict, Todo: &current}, nil
+			}
+			fields, newStatus = newerFields(current, m.Fields, status, *m.ModifiedAt)
+			if fields == (UpdateTodoInput{}) && newStatus == nil {
+				return model.MutationResult{Status: model.MutationConflict, Todo: &current}, nil
+			}
+			result = model.MutationMerged
+		}
+
+		todo, err := s.todos.saveIfUnchanged(ctx, userID, current, fields, newStatus, *m.ModifiedAt)
+		if errors.Is(err, errTodoChanged) {
+			if attempt < maxSyncAttempts {
+				continue
+			}
+			return model.MutationResult{Status: model.MutationConflict, Todo: &current}, nil
+		}
+		if err != nil {
+			return model.MutationResult{}, err
+		}
+		return model.MutationResult{Status: result, Todo: &todo}, nil
+	}
+}
+
+// newerFields keeps the fields in input, and status, that were changed at
+// modifiedAt after the server copy of the field was last changed.
+func newerFields(current model.Todo, input UpdateTodoInput, status *model.TodoStatus, modifiedAt time.Time) (UpdateTodoInput, *model.TodoStatus) {
+	newer := func(field string) bool {
+		return modifiedAt.After(current.FieldModifiedAt(field))
+	}
+
+	var kept UpdateTodoInput
+	if input.Title != nil && newer("title") {
+		kept.Title = input.Title
+	}
+	if input.Description != nil && newer("description") {
+		kept.Description = input.Description
+	}
+	if (input.DueAt != nil || input.ClearDueAt) && newer("due_at") {
+		kept.DueAt, kept.ClearDueAt = input.DueAt, input.ClearDueAt
+	}
+	if input.Priority != nil && newer("priority") {
+		kept.Priority = input.Priority
+	}
+	if input.Important != nil && newer("important") {
+		kept.Important = input.Important
+	}
+	if input.Urgent != nil && newer("urgent") {
+		kept.Urgent = input.Urgent
+	}
+	if (input.EstimateMinutes != nil || input.ClearEstimate) && newer("estimate_minutes") {
+		kept.EstimateMinutes, kept.ClearEstimate = input.EstimateMinutes, input.ClearEstimate
+	}
+	if status != nil && !newer("status") {
+		status = nil
+	}
+	return kept, status
+}
+
+// applyDelete deletes the todo. If the server copy has changed since the
+// client's base, ConflictReject returns it untouched, and ConflictMerge
+// deletes it only if the client deleted it after the server copy was last
+// changed. The delete is made only if the todo is still as checked, and the
+// check is repeated if it is not.
+func (s *SyncService) applyDelete(ctx context.Context, userID string, m SyncMutation) (model.MutationResult, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := s.todos.GetByID(ctx, userID, m.ID)
+		if errors.Is(err, ErrNotFound) {
+			// Already gone: deleting is idempotent.
+			return model.MutationResult{Status: model.MutationApplied}, nil
+		}
+		if err != nil {
+			return model.MutationResult{}, err
+		}
+
+		result := model.MutationApplied
+		if current.UpdatedAt.After(*m.BaseUpdatedAt) {
+			if m.OnConflict == model.ConflictReject || !m.ModifiedAt.After(current.UpdatedAt) {
+				return model.MutationResult{Status: model.MutationConflict, Todo: &current}, nil
+			}
+			result = model.MutationMerged
+		}
+
+		err = s.todos.deleteIfUnchanged(ctx, userID, current)
+		if errors.Is(err, errTodoChanged) {
+			if attempt < maxSyncAttempts {
+				continue
+			}
+			return model.MutationResult{Status: model.MutationConflict, Todo: &current}, nil
+		}
+		if err != nil {
+			return model.MutationResult{}, err
+		}
+		return model.MutationResult{Status: result}, nil
+	}
+}
+
+func mutationError(err error) *model.MutationError {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &model.MutationError{Code: "NOT_FOUND", Message: "resource not found"}
+	case errors.Is(err, ErrInvalidInput):
+		return &model.MutationError{Code: "INVALID_INPUT", Message: err.Error()}
+	case errors.Is(err, ErrConflict):
+		return &model.MutationError{Code: "CONFLICT", Message: err.Error()}
+	default:
+		return &model.MutationError{Code: "INTERNAL_ERROR", Message: "internal server error"}
+	}
+}
+
+// Sync tokens are opaque to clients: "v1:<seq>[:<todo id>]", base64url encoded.
+func encodeSyncToken(p model.SyncPosition) string {
+	raw := syncTokenVersion + ":" + strconv.FormatUint(p.Seq, 10)
+	if p.TodoID != "" {
+		raw += ":" + p.TodoID
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncToken(token string) (model.SyncPosition, error) {
+	if token == "" {
+		return model.SyncPosition{}, nil
+	}
+
+	invalid := fmt.Errorf("%w: invalid sync token", ErrInvalidInput)
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return model.SyncPosition{}, invalid
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != syncTokenVersion {
+		return model.SyncPosition{}, invalid
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return model.SyncPosition{}, invalid
+	}
+
+	p := model.SyncPosition{Seq: seq}
+	if len(parts) == 3 {
+		if !uuidPattern.MatchString(parts[2]) {
+			return model.SyncPosition{}, invalid
+		}
+		p.TodoID = parts[2]
+	}
+	return p, nil
+}
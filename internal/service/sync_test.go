@@ -0,0 +1,382 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+type mockChangeRepo struct {
+	since  model.SyncPosition
+	result model.TodoChanges
+	err    error
+	cutoff time.Time
+}
+
+func (m *mockChangeRepo) Changes(ctx context.Context, userID string, since model.SyncPosition, limit int) (model.TodoChanges, error) {
+	m.since = since
+	return m.result, m.err
+}
+
+func (m *mockChangeRepo) PruneTombstones(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.cutoff = cutoff
+	return 3, nil
+}
+
+// syncTodoRepo is a mockTodoRepo backed by a single stored todo that bumps
+// updated_at on every write.
+func syncTodoRepo(stored *model.Todo, clock *time.Time) *mockTodoRepo {
+	repo := &mockTodoRepo{
+		createFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			todo.ID = "todo-new"
+			todo.CreatedAt, todo.UpdatedAt = *clock, *clock
+			return todo, nil
+		},
+		getByIDFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+			if stored.ID != todoID {
+				return model.Todo{}, sql.ErrNoRows
+			}
+			return *stored, nil
+		},
+		updateFn: func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+			*clock = clock.Add(time.Second)
+			todo.UpdatedAt = *clock
+			*stored = todo
+			return todo, nil
+		},
+		deleteFn: func(ctx context.Context, userID, todoID string) error {
+			if stored.ID != todoID {
+				return sql.ErrNoRows
+			}
+			stored.ID = ""
+			return nil
+		},
+	}
+	repo.updateIfUnchangedFn = func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+		if stored.ID != todo.ID || !stored.UpdatedAt.Equal(todo.UpdatedAt) {
+			return model.Todo{}, sql.ErrNoRows
+		}
+		return repo.updateFn(ctx, todo)
+	}
+	repo.deleteIfUnchangedFn = func(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+		if stored.ID != todoID || !stored.UpdatedAt.Equal(updatedAt) {
+			return sql.ErrNoRows
+		}
+		return repo.deleteFn(ctx, userID, todoID)
+	}
+	return repo
+}
+
+func TestSyncChanges_Tokens(t *testing.T) {
+	changes := &mockChangeRepo{result: model.TodoChanges{
+		Next:    model.SyncPosition{Seq: 42, TodoID: "0b7e1a6e-8f4c-4d5e-9a3b-1c2d3e4f5a6b"},
+		HasMore: true,
+	}}
+	svc := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), changes)
+	ctx := context.Background()
+
+	first, err := svc.Changes(ctx, "user-1", "", 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changes.since.IsZero() {
+		t.Errorf("expected empty token to start from zero, got %+v", changes.since)
+	}
+	if !first.HasMore || first.NextToken == "" {
+		t.Fatalf("unexpected result: %+v", first)
+	}
+
+	if _, err := svc.Changes(ctx, "user-1", first.NextToken, 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changes.since != changes.result.Next {
+		t.Errorf("expected token to round trip to %+v, got %+v", changes.result.Next, changes.since)
+	}
+
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for _, token := range []string{
+		"not base64!",
+		encode("v2:42"),
+		encode("v1:abc"),
+		encode("v1:42:not-a-uuid"),
+		encode("v1:42:x:y"),
+	} {
+		if _, err := svc.Changes(ctx, "user-1", token, 100); !errors.Is(err, service.ErrInvalidInput) {
+			t.Errorf("token %q: expected ErrInvalidInput, got %v", token, err)
+		}
+	}
+}
+
+func TestSyncChanges_PrunedToken(t *testing.T) {
+	changes := &mockChangeRepo{err: repository.ErrSyncPositionPruned}
+	svc := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), changes)
+
+	token := base64.RawURLEncoding.EncodeToString([]byte("v1:42"))
+	if _, err := svc.Changes(context.Background(), "user-1", token, 100); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("expected ErrConflict for a pruned token, got %v", err)
+	}
+}
+
+func TestSyncPruneTombstones(t *testing.T) {
+	changes := &mockChangeRepo{}
+	ctx := context.Background()
+
+	n, err := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), changes).PruneTombstones(ctx)
+	if err != nil || n != 0 || !changes.cutoff.IsZero() {
+		t.Fatalf("expected no pruning without a retention, got n=%d err=%v cutoff=%v", n, err, changes.cutoff)
+	}
+
+	svc := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), changes, service.WithTombstoneRetention(30*24*time.Hour))
+	n, err = svc.PruneTombstones(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 pruned, got %d", n)
+	}
+	want := time.Now().Add(-30 * 24 * time.Hour)
+	if d := changes.cutoff.Sub(want); d < -time.Minute || d > time.Minute {
+		t.Errorf("expected cutoff near %v, got %v", want, changes.cutoff)
+	}
+}
+
+func TestSyncApplyMutations(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := base.Add(-time.Hour)
+	after := base.Add(time.Hour)
+	completed := string(model.TodoStatusCompleted)
+
+	tests := []struct {
+		name       string
+		mutation   service.SyncMutation
+		wantStatus model.MutationStatus
+		wantTitle  string // stored title afterwards
+		wantCode   string
+	}{
+		{
+			name:       "create",
+			mutation:   service.SyncMutation{Op: model.SyncOpCreate, ClientID: "tmp-1", Fields: service.UpdateTodoInput{Title: strPtr("New")}},
+			wantStatus: model.MutationApplied,
+			wantTitle:  "Buy groceries",
+		},
+		{
+			name:       "create without title",
+			mutation:   service.SyncMutation{Op: model.SyncOpCreate, ClientID: "tmp-1"},
+			wantStatus: model.MutationFailed,
+			wantTitle:  "Buy groceries",
+			wantCode:   "INVALID_INPUT",
+		},
+		{
+			name:       "update without concurrent change",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, Status: &completed, BaseUpdatedAt: &base, ModifiedAt: &before},
+			wantStatus: model.MutationApplied,
+			wantTitle:  "Client",
+		},
+		{
+			name:       "update conflict rejected",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, BaseUpdatedAt: &before, ModifiedAt: &after, OnConflict: model.ConflictReject},
+			wantStatus: model.MutationConflict,
+			wantTitle:  "Buy groceries",
+		},
+		{
+			name:       "update conflict merged when client is newer",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, BaseUpdatedAt: &before, ModifiedAt: &after},
+			wantStatus: model.MutationMerged,
+			wantTitle:  "Client",
+		},
+		{
+			name:       "update conflict kept when server is newer",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, BaseUpdatedAt: &before, ModifiedAt: &before},
+			wantStatus: model.MutationConflict,
+			wantTitle:  "Buy groceries",
+		},
+		{
+			name:       "update missing timestamps",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}},
+			wantStatus: model.MutationFailed,
+			wantTitle:  "Buy groceries",
+			wantCode:   "INVALID_INPUT",
+		},
+		{
+			name:       "update deleted todo",
+			mutation:   service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-gone", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, BaseUpdatedAt: &base, ModifiedAt: &after},
+			wantStatus: model.MutationFailed,
+			wantTitle:  "Buy groceries",
+			wantCode:   "NOT_FOUND",
+		},
+		{
+			name:       "delete already deleted",
+			mutation:   service.SyncMutation{Op: model.SyncOpDelete, ID: "todo-gone", BaseUpdatedAt: &base, ModifiedAt: &after},
+			wantStatus: model.MutationApplied,
+			wantTitle:  "Buy groceries",
+		},
+		{
+			name:       "delete conflict rejected",
+			mutation:   service.SyncMutation{Op: model.SyncOpDelete, ID: "todo-1", BaseUpdatedAt: &before, ModifiedAt: &after, OnConflict: model.ConflictReject},
+			wantStatus: model.MutationConflict,
+			wantTitle:  "Buy groceries",
+		},
+		{
+			name:       "invalid strategy",
+			mutation:   service.SyncMutation{Op: model.SyncOpDelete, ID: "todo-1", BaseUpdatedAt: &base, ModifiedAt: &after, OnConflict: "ours"},
+			wantStatus: model.MutationFailed,
+			wantTitle:  "Buy groceries",
+			wantCode:   "INVALID_INPUT",
+		},
+		{
+			name:       "invalid op",
+			mutation:   service.SyncMutation{Op: "archive", ID: "todo-1"},
+			wantStatus: model.MutationFailed,
+			wantTitle:  "Buy groceries",
+			wantCode:   "INVALID_INPUT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := sampleTodo()
+			stored.UpdatedAt = base
+			stored.FieldsModifiedAt = map[string]time.Time{"title": base}
+			clock := base
+			svc := service.NewSyncService(service.NewTodoService(syncTodoRepo(&stored, &clock)), &mockChangeRepo{})
+
+			results, err := svc.ApplyMutations(context.Background(), "user-1", []service.SyncMutation{tt.mutation})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := results[0]
+
+			if got.Status != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s (error: %+v)", tt.wantStatus, got.Status, got.Error)
+			}
+			if got.ClientID != tt.mutation.ClientID {
+				t.Errorf("expected client_id %q echoed, got %q", tt.mutation.ClientID, got.ClientID)
+			}
+			if tt.wantCode != "" && (got.Error == nil || got.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, got.Error)
+			}
+			if got.Status == model.MutationConflict && (got.Todo == nil || got.Todo.Title != "Buy groceries") {
+				t.Errorf("expected server copy on conflict, got %+v", got.Todo)
+			}
+			if stored.Title != tt.wantTitle {
+				t.Errorf("expected stored title %q, got %q", tt.wantTitle, stored.Title)
+			}
+		})
+	}
+}
+
+// TestSyncApplyMutations_MergesByField changes two fields offline while the
+// server changed one of them later, and expects only the other to be kept.
+func TestSyncApplyMutations_MergesByField(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clientBase := base.Add(-time.Hour)
+	modifiedAt := base.Add(-30 * time.Minute)
+
+	stored := sampleTodo()
+	stored.UpdatedAt = base
+	stored.FieldsModifiedAt = map[string]time.Time{"title": base}
+	clock := base
+	svc := service.NewSyncService(service.NewTodoService(syncTodoRepo(&stored, &clock)), &mockChangeRepo{})
+
+	results, err := svc.ApplyMutations(context.Background(), "user-1", []service.SyncMutation{{
+		Op:            model.SyncOpUpdate,
+		ID:            "todo-1",
+		Fields:        service.UpdateTodoInput{Title: strPtr("Client"), Description: strPtr("Client notes")},
+		BaseUpdatedAt: &clientBase,
+		ModifiedAt:    &modifiedAt,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results[0].Status != model.MutationMerged {
+		t.Fatalf("expected merged, got %s (error: %+v)", results[0].Status, results[0].Error)
+	}
+	if stored.Title != "Buy groceries" || stored.Description != "Client notes" {
+		t.Errorf("expected the server title and the client description, got %q and %q", stored.Title, stored.Description)
+	}
+	if !stored.FieldModifiedAt("description").Equal(modifiedAt) || !stored.FieldModifiedAt("title").Equal(base) {
+		t.Errorf("unexpected field times %v", stored.FieldsModifiedAt)
+	}
+}
+
+// TestSyncApplyMutations_ConcurrentChange changes the todo on the server
+// between the conflict check and the write, and expects the mutation to be
+// checked again rather than written over the change.
+func TestSyncApplyMutations_ConcurrentChange(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	modifiedAt := base.Add(time.Minute)
+	later := base.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		mutation service.SyncMutation
+	}{
+		{"update", service.SyncMutation{Op: model.SyncOpUpdate, ID: "todo-1", Fields: service.UpdateTodoInput{Title: strPtr("Client")}, BaseUpdatedAt: &base, ModifiedAt: &modifiedAt}},
+		{"delete", service.SyncMutation{Op: model.SyncOpDelete, ID: "todo-1", BaseUpdatedAt: &base, ModifiedAt: &modifiedAt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := sampleTodo()
+			stored.UpdatedAt = base
+			clock := base
+			repo := syncTodoRepo(&stored, &clock)
+			// Another device renames the todo just before the first write.
+			serverWrite := func() {
+				stored.Title = "Server"
+				stored.UpdatedAt = later
+				stored.FieldsModifiedAt = map[string]time.Time{"title": later}
+			}
+			update, del := repo.updateIfUnchangedFn, repo.deleteIfUnchangedFn
+			repo.updateIfUnchangedFn = func(ctx context.Context, todo model.Todo) (model.Todo, error) {
+				if stored.UpdatedAt.Equal(base) {
+					serverWrite()
+				}
+				return update(ctx, todo)
+			}
+			repo.deleteIfUnchangedFn = func(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+				if stored.UpdatedAt.Equal(base) {
+					serverWrite()
+				}
+				return del(ctx, userID, todoID, updatedAt)
+			}
+			svc := service.NewSyncService(service.NewTodoService(repo), &mockChangeRepo{})
+
+			results, err := svc.ApplyMutations(context.Background(), "user-1", []service.SyncMutation{tt.mutation})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if results[0].Status != model.MutationConflict {
+				t.Fatalf("expected conflict, got %s (error: %+v)", results[0].Status, results[0].Error)
+			}
+			if results[0].Todo == nil || results[0].Todo.Title != "Server" {
+				t.Errorf("expected the changed server copy, got %+v", results[0].Todo)
+			}
+			if stored.ID != "todo-1" || stored.Title != "Server" {
+				t.Errorf("expected the server change to be kept, got %+v", stored)
+			}
+		})
+	}
+}
+
+func TestSyncApplyMutations_Batch(t *testing.T) {
+	svc := service.NewSyncService(service.NewTodoService(&mockTodoRepo{}), &mockChangeRepo{})
+
+	if _, err := svc.ApplyMutations(context.Background(), "user-1", nil); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for empty batch, got %v", err)
+	}
+
+	tooMany := make([]service.SyncMutation, service.MaxSyncMutations+1)
+	if _, err := svc.ApplyMutations(context.Background(), "user-1", tooMany); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for oversized batch, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+)
+
+const (
+	defaultReportRange = 7 * 24 * time.Hour
+	maxReportRange     = 366 * 24 * time.Hour
+)
+
+type StartTimerInput struct {
+	TodoID string
+	Note   string
+}
+
+type AddTimeEntryInput struct {
+	TodoID    string
+	Note      string
+	StartedAt string // RFC3339
+	EndedAt   string // RFC3339
+}
+
+// TimeService tracks time spent on todos.
+type TimeService struct {
+	entries  repository.TimeEntryRepository
+	todos    repository.TodoRepository
+	maxTimer time.Duration
+	now      func() time.Time
+}
+
+// NewTimeService creates a new TimeService. Timers running longer than
+// maxTimer are stopped automatically at started_at + maxTimer.
+func NewTimeService(entries repository.TimeEntryRepository, todos repository.TodoRepository, maxTimer time.Duration) *TimeService {
+	return &TimeService{
+		entries:  entries,
+		todos:    todos,
+		maxTimer: maxTimer,
+		now:      time.Now,
+	}
+}
+
+// StartTimer starts a timer on a todo. Only one timer may run per user.
+func (s *TimeService) StartTimer(ctx context.Context, userID string, input StartTimerInput) (model.TimeEntry, error) {
+	if input.TodoID == "" {
+		return model.TimeEntry{}, fmt.Errorf("%w: todo_id is required", ErrInvalidInput)
+	}
+	if err := s.ensureTodo(ctx, userID, input.TodoID); err != nil {
+		return model.TimeEntry{}, err
+	}
+
+	running, err := s.runningTimer(ctx, userID)
+	if err != nil {
+		return model.TimeEntry{}, err
+	}
+	if running != nil {
+		return model.TimeEntry{}, fmt.Errorf("%w: a timer is already running on todo %s", ErrConflict, running.TodoID)
+	}
+
+	entry, err := s.entries.Create(ctx, model.TimeEntry{
+		UserID:    userID,
+		TodoID:    input.TodoID,
+		Note:      input.Note,
+		StartedAt: s.now(),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrTimerRunning) {
+			return model.TimeEntry{}, fmt.Errorf("%w: a timer is already running", ErrConflict)
+		}
+		return model.TimeEntry{}, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// StopTimer stops the user's running timer.
+func (s *TimeService) StopTimer(ctx context.Context, userID string) (model.TimeEntry, error) {
+	running, err := s.runningTimer(ctx, userID)
+	if err != nil {
+		return model.TimeEntry{}, err
+	}
+	if running == nil {
+		return model.TimeEntry{}, ErrNotFound
+	}
+
+	stopped, err := s.entries.Stop(ctx, userID, running.ID, s.now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TimeEntry{}, ErrNotFound
+		}
+		return model.TimeEntry{}, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return stopped, nil
+}
+
+// RunningTimer returns the user's running timer, or ErrNotFound.
+func (s *TimeService) RunningTimer(ctx context.Context, userID string) (model.TimeEntry, error) {
+	running, err := s.runningTimer(ctx, userID)
+	if err != nil {
+		return model.TimeEntry{}, err
+	}
+	if running == nil {
+		return model.TimeEntry{}, ErrNotFound
+	}
+	return *running, nil
+}
+
+// AddEntry records a completed span of time entered manually.
+func (s *TimeService) AddEntry(ctx context.Context, userID string, input AddTimeEntryInput) (model.TimeEntry, error) {
+	if input.TodoID == "" {
+		return model.TimeEntry{}, fmt.Errorf("%w: todo_id is required", ErrInvalidInput)
+	}
+	startedAt, err := time.Parse(time.RFC3339, input.StartedAt)
+	if err != nil {
+		return model.TimeEntry{}, fmt.Errorf("%w: invalid started_at format, expected RFC3339", ErrInvalidInput)
+	}
+	endedAt, err := time.Parse(time.RFC3339, input.EndedAt)
+	if err != nil {
+		return model.TimeEntry{}, fmt.Errorf("%w: invalid ended_at format, expected RFC3339", ErrInvalidInput)
+	}
+	if !endedAt.After(startedAt) {
+		return model.TimeEntry{}, fmt.Errorf("%w: ended_at must be after started_at", ErrInvalidInput)
+	}
+	if endedAt.After(s.now()) {
+		return model.TimeEntry{}, fmt.Errorf("%w: ended_at must not be in the future", ErrInvalidInput)
+	}
+	if err := s.ensureTodo(ctx, userID, input.TodoID); err != nil {
+		return model.TimeEntry{}, err
+	}
+
+	entry, err := s.entries.Create(ctx, model.TimeEntry{
+		UserID:    userID,
+		TodoID:    input.TodoID,
+		Note:      input.Note,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+	})
+	if err != nil {
+		return model.TimeEntry{}, fmt.Errorf("failed to add time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// DeleteEntry removes a time entry.
+func (s *TimeService) DeleteEntry(ctx context.Context, userID, entryID string) error {
+	if err := s.entries.Delete(ctx, userID, entryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+	return nil
+}
+
+// ListEntries returns time entries that overlap [from, to), optionally
+// restricted to one todo. Entries are returned whole.
+func (s *TimeService) ListEntries(ctx context.Context, userID, todoID, from, to string) ([]model.TimeEntry, error) {
+	fromT, toT, err := s.parseRange(from, to, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entries.List(ctx, userID, todoID, fromT, toT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Report aggregates tracked time by day and by todo over a date range and
+// compares it with the todos' estimates. Days and date-only bounds are in the
+// IANA time zone tz, UTC when empty; an entry that crosses midnight counts
+// towards each day it covers.
+func (s *TimeService) Report(ctx context.Context, userID, from, to, tz string) (model.TimeReport, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "Local" {
+		return model.TimeReport{}, fmt.Errorf("%w: invalid tz, expected an IANA time zone such as Asia/Seoul", ErrInvalidInput)
+	}
+	fromT, toT, err := s.parseRange(from, to, loc)
+	if err != nil {
+		return model.TimeReport{}, err
+	}
+
+	byDay, err := s.entries.ReportByDay(ctx, userID, fromT, toT, loc)
+	if err != nil {
+		return model.TimeReport{}, fmt.Errorf("failed to build daily report: %w", err)
+	}
+	byTodo, err := s.entries.ReportByTodo(ctx, userID, fromT, toT)
+	if err != nil {
+		return model.TimeReport{}, fmt.Errorf("failed to build todo report: %w", err)
+	}
+
+	report := model.TimeReport{
+		TimeZone: loc.String(),
+		From:     fromT,
+		To:       toT,
+		ByDay:    byDay,
+		ByTodo:   byTodo,
+	}
+	for _, t := range byTodo {
+		report.TrackedMinutes += t.TrackedMinutes
+		if t.EstimateMinutes != nil {
+			report.EstimateMinutes += *t.EstimateMinutes
+		}
+	}
+
+	return report, nil
+}
+
+// StopExpiredTimers stops every timer that has exceeded the configured limit.
+func (s *TimeService) StopExpiredTimers(ctx context.Context) (int64, error) {
+	n, err := s.entries.StopExpired(ctx, s.maxTimer)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stop expired timers: %w", err)
+	}
+	return n, nil
+}
+
+// RunAutoStop calls StopExpiredTimers every interval until ctx is cancelled.
+func (s *TimeService) RunAutoStop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.StopExpiredTimers(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "timer auto-stop failed", "error", err)
+				continue
+			}
+			if n > 0 {
+				slog.InfoContext(ctx, "stopped expired timers", "count", n)
+			}
+		}
+	}
+}
+
+// runningTimer returns the user's running timer, or nil. A timer found past
+// the limit is stopped on the spot instead of waiting for the next sweep.
+func (s *TimeService) runningTimer(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	running, err := s.entries.GetRunning(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+
+	if s.maxTimer > 0 && running.Duration(s.now()) > s.maxTimer {
+		if _, err := s.entries.Stop(ctx, userID, running.ID, running.StartedAt.Add(s.maxTimer)); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to stop expired timer: %w", err)
+		}
+		return nil, nil
+	}
+
+	return &running, nil
+}
+
+func (s *TimeService) ensureTodo(ctx context.Context, userID, todoID string) error {
+	if _, err := s.todos.GetByID(ctx, userID, todoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+	return nil
+}
+
+// parseRange parses an optional [from, to) range. Bounds may be RFC3339
+// timestamps or YYYY-MM-DD dates in loc; a date-only "to" includes that whole
+// day. The range defaults to the last seven days.
+func (s *TimeService) parseRange(from, to string, loc *time.Location) (time.Time, time.Time, error) {
+	toT := s.now()
+	if to != "" {
+		t, dateOnly, err := parseReportTime(to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid to, expected RFC3339 or YYYY-MM-DD", ErrInvalidInput)
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		toT = t
+	}
+
+	fromT := toT.Add(-defaultReportRange)
+	if from != "" {
+		t, _, err := parseReportTime(from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid from, expected RFC3339 or YYYY-MM-DD", ErrInvalidInput)
+		}
+		fromT = t
+	}
+
+	if !toT.After(fromT) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be after from", ErrInvalidInput)
+	}
+	if toT.Sub(fromT) > maxReportRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: range must not exceed 366 days", ErrInvalidInput)
+	}
+	return fromT, toT, nil
+}
+
+func parseReportTime(s string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	return t, false, err
+}
@@ -0,0 +1,285 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+// mockTimeEntryRepo implements repository.TimeEntryRepository for testing
+type mockTimeEntryRepo struct {
+	createFn       func(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error)
+	getRunningFn   func(ctx context.Context, userID string) (model.TimeEntry, error)
+	stopFn         func(ctx context.Context, userID, entryID string, endedAt time.Time) (model.TimeEntry, error)
+	reportByDayFn  func(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error)
+	reportByTodoFn func(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error)
+}
+
+func (m *mockTimeEntryRepo) Create(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error) {
+	return m.createFn(ctx, entry)
+}
+func (m *mockTimeEntryRepo) GetRunning(ctx context.Context, userID string) (model.TimeEntry, error) {
+	return m.getRunningFn(ctx, userID)
+}
+func (m *mockTimeEntryRepo) Stop(ctx context.Context, userID, entryID string, endedAt time.Time) (model.TimeEntry, error) {
+	return m.stopFn(ctx, userID, entryID, endedAt)
+}
+func (m *mockTimeEntryRepo) Delete(ctx context.Context, userID, entryID string) error {
+	return nil
+}
+func (m *mockTimeEntryRepo) List(ctx context.Context, userID, todoID string, from, to time.Time) ([]model.TimeEntry, error) {
+	return []model.TimeEntry{}, nil
+}
+func (m *mockTimeEntryRepo) StopExpired(ctx context.Context, maxDuration time.Duration) (int64, error) {
+	return 0, nil
+}
+func (m *mockTimeEntryRepo) ReportByDay(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error) {
+	return m.reportByDayFn(ctx, userID, from, to, loc)
+}
+func (m *mockTimeEntryRepo) ReportByTodo(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error) {
+	return m.reportByTodoFn(ctx, userID, from, to)
+}
+
+func noRunningTimer(ctx context.Context, userID string) (model.TimeEntry, error) {
+	return model.TimeEntry{}, fmt.Errorf("scan: %w", sql.ErrNoRows)
+}
+
+func existingTodo(ctx context.Context, userID, todoID string) (model.Todo, error) {
+	return sampleTodo(), nil
+}
+
+func TestStartTimer(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		todoID    string
+		getFn     func(ctx context.Context, userID, todoID string) (model.Todo, error)
+		runningFn func(ctx context.Context, userID string) (model.TimeEntry, error)
+		wantStop  bool
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			todoID:    "todo-1",
+			getFn:     existingTodo,
+			runningFn: noRunningTimer,
+		},
+		{
+			name:    "missing todo id",
+			todoID:  "",
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:   "todo not found",
+			todoID: "todo-1",
+			getFn: func(ctx context.Context, userID, todoID string) (model.Todo, error) {
+				return model.Todo{}, fmt.Errorf("scan: %w", sql.ErrNoRows)
+			},
+			wantErr: service.ErrNotFound,
+		},
+		{
+			name:   "timer already running",
+			todoID: "todo-1",
+			getFn:  existingTodo,
+			runningFn: func(ctx context.Context, userID string) (model.TimeEntry, error) {
+				return model.TimeEntry{ID: "entry-1", TodoID: "todo-2", StartedAt: startedAt}, nil
+			},
+			wantErr: service.ErrConflict,
+		},
+		{
+			name:   "expired timer is stopped first",
+			todoID: "todo-1",
+			getFn:  existingTodo,
+			runningFn: func(ctx context.Context, userID string) (model.TimeEntry, error) {
+				return model.TimeEntry{ID: "entry-1", TodoID: "todo-2", StartedAt: startedAt.Add(-10 * time.Hour)}, nil
+			},
+			wantStop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stoppedAt *time.Time
+			entries := &mockTimeEntryRepo{
+				getRunningFn: tt.runningFn,
+				createFn: func(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error) {
+					entry.ID = "entry-new"
+					return entry, nil
+				},
+				stopFn: func(ctx context.Context, userID, entryID string, endedAt time.Time) (model.TimeEntry, error) {
+					stoppedAt = &endedAt
+					return model.TimeEntry{ID: entryID, EndedAt: &endedAt}, nil
+				},
+			}
+			svc := service.NewTimeService(entries, &mockTodoRepo{getByIDFn: tt.getFn}, 8*time.Hour)
+
+			got, err := svc.StartTimer(context.Background(), "user-1", service.StartTimerInput{TodoID: tt.todoID})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Running() {
+				t.Error("expected new entry to be running")
+			}
+			if tt.wantStop {
+				if stoppedAt == nil {
+					t.Fatal("expected expired timer to be stopped")
+				}
+				want := startedAt.Add(-10 * time.Hour).Add(8 * time.Hour)
+				if !stoppedAt.Equal(want) {
+					t.Errorf("expected expired timer capped at %v, got %v", want, *stoppedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestStopTimer_NoneRunning(t *testing.T) {
+	entries := &mockTimeEntryRepo{getRunningFn: noRunningTimer}
+	svc := service.NewTimeService(entries, &mockTodoRepo{}, 8*time.Hour)
+
+	if _, err := svc.StopTimer(context.Background(), "user-1"); !errors.Is(err, service.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAddEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   service.AddTimeEntryInput
+		wantErr error
+	}{
+		{
+			name:  "success",
+			input: service.AddTimeEntryInput{TodoID: "todo-1", StartedAt: "2025-01-01T09:00:00Z", EndedAt: "2025-01-01T10:30:00Z"},
+		},
+		{
+			name:    "end before start",
+			input:   service.AddTimeEntryInput{TodoID: "todo-1", StartedAt: "2025-01-01T10:00:00Z", EndedAt: "2025-01-01T09:00:00Z"},
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:    "invalid started_at",
+			input:   service.AddTimeEntryInput{TodoID: "todo-1", StartedAt: "yesterday", EndedAt: "2025-01-01T09:00:00Z"},
+			wantErr: service.ErrInvalidInput,
+		},
+		{
+			name:    "future end",
+			input:   service.AddTimeEntryInput{TodoID: "todo-1", StartedAt: "2025-01-01T10:00:00Z", EndedAt: "2999-01-01T09:00:00Z"},
+			wantErr: service.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := &mockTimeEntryRepo{
+				createFn: func(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error) {
+					return entry, nil
+				},
+			}
+			svc := service.NewTimeService(entries, &mockTodoRepo{getByIDFn: existingTodo}, 8*time.Hour)
+
+			got, err := svc.AddEntry(context.Background(), "user-1", tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Duration(time.Now()) != 90*time.Minute {
+				t.Errorf("expected 90m entry, got %v", got.Duration(time.Now()))
+			}
+		})
+	}
+}
+
+func TestTimeReport(t *testing.T) {
+	estimate := 60
+	var gotFrom, gotTo time.Time
+	entries := &mockTimeEntryRepo{
+		reportByDayFn: func(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error) {
+			gotFrom, gotTo = from, to
+			return []model.DailyTime{{Date: "2025-01-01", TrackedMinutes: 100}}, nil
+		},
+		reportByTodoFn: func(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error) {
+			return []model.TodoTime{
+				{TodoID: "todo-1", EstimateMinutes: &estimate, TrackedMinutes: 75},
+				{TodoID: "todo-2", TrackedMinutes: 25},
+			}, nil
+		},
+	}
+	svc := service.NewTimeService(entries, &mockTodoRepo{}, 8*time.Hour)
+
+	report, err := svc.Report(context.Background(), "user-1", "2025-01-01", "2025-01-31", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !gotFrom.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !gotTo.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected [2025-01-01, 2025-02-01), got [%v, %v)", gotFrom, gotTo)
+	}
+	if report.TrackedMinutes != 100 || report.EstimateMinutes != 60 {
+		t.Errorf("expected tracked=100 estimate=60, got tracked=%d estimate=%d", report.TrackedMinutes, report.EstimateMinutes)
+	}
+
+	if report.TimeZone != "UTC" {
+		t.Errorf("expected time zone UTC, got %q", report.TimeZone)
+	}
+
+	if _, err := svc.Report(context.Background(), "user-1", "2025-02-01", "2025-01-01", ""); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for inverted range, got %v", err)
+	}
+	if _, err := svc.Report(context.Background(), "user-1", "2020-01-01", "2025-01-01", ""); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for oversized range, got %v", err)
+	}
+	for _, tz := range []string{"Mars/Olympus", "Local"} {
+		if _, err := svc.Report(context.Background(), "user-1", "", "", tz); !errors.Is(err, service.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput for tz %q, got %v", tz, err)
+		}
+	}
+}
+
+func TestTimeReport_TimeZone(t *testing.T) {
+	var gotFrom, gotTo time.Time
+	var gotLoc *time.Location
+	entries := &mockTimeEntryRepo{
+		reportByDayFn: func(ctx context.Context, userID string, from, to time.Time, loc *time.Location) ([]model.DailyTime, error) {
+			gotFrom, gotTo, gotLoc = from, to, loc
+			return nil, nil
+		},
+		reportByTodoFn: func(ctx context.Context, userID string, from, to time.Time) ([]model.TodoTime, error) {
+			return nil, nil
+		},
+	}
+	svc := service.NewTimeService(entries, &mockTodoRepo{}, 8*time.Hour)
+
+	report, err := svc.Report(context.Background(), "user-1", "2025-01-01", "2025-01-01", "Asia/Seoul")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Midnight in Seoul is 15:00 UTC the day before.
+	if !gotFrom.Equal(time.Date(2024, 12, 31, 15, 0, 0, 0, time.UTC)) || !gotTo.Equal(time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the Seoul day 2025-01-01, got [%v, %v)", gotFrom, gotTo)
+	}
+	if gotLoc.String() != "Asia/Seoul" || report.TimeZone != "Asia/Seoul" {
+		t.Errorf("expected Asia/Seoul, got repository %q and report %q", gotLoc, report.TimeZone)
+	}
+}
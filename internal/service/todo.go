@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/jaekwang-park/todo-api/internal/model"
@@ -12,54 +13,101 @@ import (
 )
 
 // parseDueAt parses an RFC3339 string into *time.Time.
-// Returns nil if input is nil.
-func parseDueAt(s *string) (*time.Time, error) {
+// Returns nil if input is nil. Callers validate the input first.
+func parseDueAt(s *string) *time.Time {
 	if s == nil {
-		return nil, nil
+		return nil
 	}
-	t, err := time.Parse(time.RFC3339, *s)
-	if err != nil {
-		return nil, fmt.Errorf("%w: invalid due_at format, expected RFC3339", ErrInvalidInput)
+	t, _ := time.Parse(time.RFC3339, *s)
+	return &t
+}
+
+// parsePriority converts a validated priority. Empty input means no priority.
+func parsePriority(s string) model.TodoPriority {
+	if s == "" {
+		return model.TodoPriorityNone
 	}
-	return &t, nil
+	return model.TodoPriority(s)
 }
 
 type CreateTodoInput struct {
-	Title       string
-	Description string
-	DueAt       *string // RFC3339 string, parsed in handler
+	Title           string
+	Description     string
+	DueAt           *string // RFC3339 string, parsed in handler
+	Priority        string  // empty means none
+	Important       bool
+	Urgent          bool
+	EstimateMinutes *int
 }
 
 type UpdateTodoInput struct {
-	Title       *string
-	Description *string
-	DueAt       *string
+	Title           *string
+	Description     *string
+	DueAt           *string
+	Priority        *string
+	Important       *bool
+	Urgent          *bool
+	EstimateMinutes *int
+	// ClearDueAt and ClearEstimate remove the due date and the estimate.
+	// They take precedence over DueAt and EstimateMinutes.
+	ClearDueAt    bool
+	ClearEstimate bool
+}
+
+// MatrixParams selects the todos shown in the Eisenhower matrix view.
+// Each quadrant pages independently through its own cursor.
+type MatrixParams struct {
+	UserID  string
+	Status  *model.TodoStatus
+	Cursors map[model.EisenhowerQuadrant]string
+	Limit   int
+	Fields  []string
+	Expand  []model.TodoExpand
 }
 
 type TodoService struct {
-	repo repository.TodoRepository
+	repo       repository.TodoRepository
+	deps       repository.TodoDependencyRepository
+	publishers []EventPublisher
+	now        func() time.Time
+}
+
+// TodoServiceOption configures optional TodoService collaborators.
+type TodoServiceOption func(*TodoService)
+
+// WithDependencies enables blocked-by tracking backed by the given repository.
+func WithDependencies(deps repository.TodoDependencyRepository) TodoServiceOption {
+	return func(s *TodoService) {
+		s.deps = deps
+	}
 }
 
-func NewTodoService(repo repository.TodoRepository) *TodoService {
-	return &TodoService{repo: repo}
+func NewTodoService(repo repository.TodoRepository, opts ...TodoServiceOption) *TodoService {
+	s := &TodoService{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *TodoService) Create(ctx context.Context, userID string, input CreateTodoInput) (model.Todo, error) {
-	if input.Title == "" {
-		return model.Todo{}, fmt.Errorf("%w: title is required", ErrInvalidInput)
-	}
+	ctx, span := tracer.Start(ctx, "TodoService.Create")
+	defer span.End()
 
-	dueAt, err := parseDueAt(input.DueAt)
-	if err != nil {
+	if err := input.validate(); err != nil {
 		return model.Todo{}, err
 	}
 
 	todo := model.Todo{
-		UserID:      userID,
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      model.TodoStatusPending,
-		DueAt:       dueAt,
+		UserID:          userID,
+		Title:           input.Title,
+		Description:     input.Description,
+		Status:          model.TodoStatusPending,
+		Priority:        parsePriority(input.Priority),
+		Important:       input.Important,
+		Urgent:          input.Urgent,
+		DueAt:           parseDueAt(input.DueAt),
+		EstimateMinutes: input.EstimateMinutes,
 	}
 
 	created, err := s.repo.Create(ctx, todo)
@@ -67,10 +115,14 @@ func (s *TodoService) Create(ctx context.Context, userID string, input CreateTod
 		return model.Todo{}, fmt.Errorf("failed to create todo: %w", err)
 	}
 
+	s.publish(ctx, model.EventTodoCreated, userID, created.ID, &created)
 	return created, nil
 }
 
 func (s *TodoService) GetByID(ctx context.Context, userID, todoID string) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.GetByID")
+	defer span.End()
+
 	todo, err := s.repo.GetByID(ctx, userID, todoID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -78,10 +130,40 @@ func (s *TodoService) GetByID(ctx context.Context, userID, todoID string) (model
 		}
 		return model.Todo{}, fmt.Errorf("failed to get todo: %w", err)
 	}
+
+	if s.deps != nil {
+		if err := s.loadDependencies(ctx, userID, &todo); err != nil {
+			return model.Todo{}, err
+		}
+	}
+
 	return todo, nil
 }
 
+// GetByIDs returns the user's todos with the given IDs in one query, in no
+// particular order and without their dependencies. Missing IDs are left out.
+func (s *TodoService) GetByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.GetByIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	todos, err := s.repo.ListByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	return todos, nil
+}
+
 func (s *TodoService) Update(ctx context.Context, userID, todoID string, input UpdateTodoInput) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.Update")
+	defer span.End()
+
+	if err := input.validate(); err != nil {
+		return model.Todo{}, err
+	}
+
 	existing, err := s.repo.GetByID(ctx, userID, todoID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -90,32 +172,156 @@ func (s *TodoService) Update(ctx context.Context, userID, todoID string, input U
 		return model.Todo{}, fmt.Errorf("failed to get todo for update: %w", err)
 	}
 
-	if input.Title != nil {
-		if *input.Title == "" {
-			return model.Todo{}, fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
-		}
-		existing.Title = *input.Title
-	}
-	if input.Description != nil {
-		existing.Description = *input.Description
+	return s.update(ctx, userID, existing, input)
+}
+
+// update applies validated input to existing and saves it.
+func (s *TodoService) update(ctx context.Context, userID string, existing model.Todo, input UpdateTodoInput) (model.Todo, error) {
+	updated, err := s.repo.Update(ctx, withModifiedFields(existing, withInput(existing, input), s.now()))
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("failed to update todo: %w", err)
 	}
-	if input.DueAt != nil {
-		dueAt, err := parseDueAt(input.DueAt)
-		if err != nil {
-			return model.Todo{}, err
+
+	s.publish(ctx, model.EventTodoUpdated, userID, updated.ID, &updated)
+	return updated, nil
+}
+
+// errTodoChanged is returned by the conditional writes when the stored todo
+// has changed since it was read.
+var errTodoChanged = errors.New("todo changed since it was read")
+
+// saveIfUnchanged applies validated input, and status if set, to existing
+// as changes made at at, and saves the result only if the stored todo has
+// not changed since existing was read. It returns errTodoChanged if it has.
+// Completing a todo with incomplete blockers fails with ErrConflict.
+func (s *TodoService) saveIfUnchanged(ctx context.Context, userID string, existing model.Todo, input UpdateTodoInput, status *model.TodoStatus, at time.Time) (model.Todo, error) {
+	todo := withInput(existing, input)
+	if status != nil {
+		if *status == model.TodoStatusCompleted && s.deps != nil {
+			open, err := s.openBlockers(ctx, userID, existing.ID)
+			if err != nil {
+				return model.Todo{}, err
+			}
+			if len(open) > 0 {
+				return model.Todo{}, fmt.Errorf("%w: todo is blocked by %d incomplete todo(s)", ErrConflict, len(open))
+			}
 		}
-		existing.DueAt = dueAt
+		todo.Status = *status
 	}
 
-	updated, err := s.repo.Update(ctx, existing)
+	updated, err := s.repo.UpdateIfUnchanged(ctx, withModifiedFields(existing, todo, at))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Todo{}, errTodoChanged
+	}
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("failed to update todo: %w", err)
 	}
 
+	if status == nil || input != (UpdateTodoInput{}) {
+		s.publish(ctx, model.EventTodoUpdated, userID, updated.ID, &updated)
+	}
+	if updated.Status != existing.Status {
+		s.publish(ctx, statusEvent(updated.Status), userID, updated.ID, &updated)
+	}
 	return updated, nil
 }
 
+// deleteIfUnchanged deletes existing only if the stored todo has not
+// changed since it was read. It returns errTodoChanged if it has or is gone.
+func (s *TodoService) deleteIfUnchanged(ctx context.Context, userID string, existing model.Todo) error {
+	err := s.repo.DeleteIfUnchanged(ctx, userID, existing.ID, existing.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errTodoChanged
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete todo: %w", err)
+	}
+
+	s.publish(ctx, model.EventTodoDeleted, userID, existing.ID, nil)
+	return nil
+}
+
+// withModifiedFields returns updated with at recorded as the time each field
+// that differs from existing was changed.
+func withModifiedFields(existing, updated model.Todo, at time.Time) model.Todo {
+	modified := maps.Clone(existing.FieldsModifiedAt)
+	if modified == nil {
+		modified = make(map[string]time.Time)
+	}
+	for _, field := range changedFields(existing, updated) {
+		modified[field] = at
+	}
+	updated.FieldsModifiedAt = modified
+	return updated
+}
+
+// changedFields returns the JSON names of the fields that differ between a
+// and b.
+func changedFields(a, b model.Todo) []string {
+	var fields []string
+	if a.Title != b.Title {
+		fields = append(fields, "title")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if a.Status != b.Status {
+		fields = append(fields, "status")
+	}
+	if a.Priority != b.Priority {
+		fields = append(fields, "priority")
+	}
+	if a.Important != b.Important {
+		fields = append(fields, "important")
+	}
+	if a.Urgent != b.Urgent {
+		fields = append(fields, "urgent")
+	}
+	if (a.DueAt == nil) != (b.DueAt == nil) || (a.DueAt != nil && !a.DueAt.Equal(*b.DueAt)) {
+		fields = append(fields, "due_at")
+	}
+	if (a.EstimateMinutes == nil) != (b.EstimateMinutes == nil) || (a.EstimateMinutes != nil && *a.EstimateMinutes != *b.EstimateMinutes) {
+		fields = append(fields, "estimate_minutes")
+	}
+	return fields
+}
+
+// withInput returns existing with the fields set by input changed.
+func withInput(existing model.Todo, input UpdateTodoInput) model.Todo {
+	if input.Title != nil {
+		existing.Title = *input.Title
+	}
+	if input.Description != nil {
+		existing.Description = *input.Description
+	}
+	if input.DueAt != nil {
+		existing.DueAt = parseDueAt(input.DueAt)
+	}
+	if input.Priority != nil {
+		existing.Priority = parsePriority(*input.Priority)
+	}
+	if input.Important != nil {
+		existing.Important = *input.Important
+	}
+	if input.Urgent != nil {
+		existing.Urgent = *input.Urgent
+	}
+	if input.EstimateMinutes != nil {
+		existing.EstimateMinutes = input.EstimateMinutes
+	}
+	if input.ClearDueAt {
+		existing.DueAt = nil
+	}
+	if input.ClearEstimate {
+		existing.EstimateMinutes = nil
+	}
+	return existing
+}
+
 func (s *TodoService) Delete(ctx context.Context, userID, todoID string) error {
+	ctx, span := tracer.Start(ctx, "TodoService.Delete")
+	defer span.End()
+
 	err := s.repo.Delete(ctx, userID, todoID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -123,12 +329,19 @@ func (s *TodoService) Delete(ctx context.Context, userID, todoID string) error {
 		}
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
+
+	s.publish(ctx, model.EventTodoDeleted, userID, todoID, nil)
 	return nil
 }
 
-func (s *TodoService) UpdateStatus(ctx context.Context, userID, todoID string, status model.TodoStatus) (model.Todo, error) {
-	if !status.IsValid() {
-		return model.Todo{}, fmt.Errorf("%w: invalid status %q", ErrInvalidInput, status)
+// UpdateStatus changes a todo's status. Completing a todo that still has
+// incomplete blockers fails with ErrConflict unless force is set.
+func (s *TodoService) UpdateStatus(ctx context.Context, userID, todoID string, status model.TodoStatus, force bool) (model.Todo, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.UpdateStatus")
+	defer span.End()
+
+	if err := validateStatus(status); err != nil {
+		return model.Todo{}, err
 	}
 
 	existing, err := s.repo.GetByID(ctx, userID, todoID)
@@ -139,20 +352,71 @@ func (s *TodoService) UpdateStatus(ctx context.Context, userID, todoID string, s
 		return model.Todo{}, fmt.Errorf("failed to get todo for status update: %w", err)
 	}
 
-	existing.Status = status
+	if status == model.TodoStatusCompleted && !force && s.deps != nil {
+		open, err := s.openBlockers(ctx, userID, todoID)
+		if err != nil {
+			return model.Todo{}, err
+		}
+		if len(open) > 0 {
+			return model.Todo{}, fmt.Errorf("%w: todo is blocked by %d incomplete todo(s)", ErrConflict, len(open))
+		}
+	}
+
+	changed := existing.Status != status
+	todo := existing
+	todo.Status = status
 
-	updated, err := s.repo.Update(ctx, existing)
+	updated, err := s.repo.Update(ctx, withModifiedFields(existing, todo, s.now()))
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("failed to update todo status: %w", err)
 	}
 
+	if changed {
+		s.publish(ctx, statusEvent(status), userID, updated.ID, &updated)
+	}
 	return updated, nil
 }
 
 func (s *TodoService) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.List")
+	defer span.End()
+
 	result, err := s.repo.List(ctx, params)
 	if err != nil {
 		return model.TodoListResult{}, fmt.Errorf("failed to list todos: %w", err)
 	}
 	return result, nil
 }
+
+// Matrix returns the user's todos grouped into the four Eisenhower quadrants.
+func (s *TodoService) Matrix(ctx context.Context, params MatrixParams) (model.MatrixResult, error) {
+	ctx, span := tracer.Start(ctx, "TodoService.Matrix")
+	defer span.End()
+
+	results := make(map[model.EisenhowerQuadrant]model.TodoListResult, len(model.Quadrants))
+	for _, q := range model.Quadrants {
+		important, urgent := q.Flags()
+		result, err := s.repo.List(ctx, model.TodoListParams{
+			UserID:    params.UserID,
+			Status:    params.Status,
+			Important: &important,
+			Urgent:    &urgent,
+			Sort:      model.TodoSortPriority,
+			Cursor:    params.Cursors[q],
+			Limit:     params.Limit,
+			Fields:    params.Fields,
+			Expand:    params.Expand,
+		})
+		if err != nil {
+			return model.MatrixResult{}, fmt.Errorf("failed to list %s quadrant: %w", q, err)
+		}
+		results[q] = result
+	}
+
+	return model.MatrixResult{
+		Do:        results[model.QuadrantDo],
+		Schedule:  results[model.QuadrantSchedule],
+		Delegate:  results[model.QuadrantDelegate],
+		Eliminate: results[model.QuadrantEliminate],
+	}, nil
+}
@@ -19,6 +19,11 @@ type mockTodoRepo struct {
 	updateFn func(ctx context.Context, todo model.Todo) (model.Todo, error)
 	deleteFn func(ctx context.Context, userID, todoID string) error
 	listFn   func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error)
+	listIDsFn func(ctx context.Context, userID string, ids []string) ([]model.Todo, error)
+	// updateIfUnchangedFn defaults to updateFn
+	updateIfUnchangedFn func(ctx context.Context, todo model.Todo) (model.Todo, error)
+	// deleteIfUnchangedFn defaults to deleteFn
+	deleteIfUnchangedFn func(ctx context.Context, userID, todoID string, updatedAt time.Time) error
 }
 
 func (m *mockTodoRepo) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
@@ -30,12 +35,27 @@ func (m *mockTodoRepo) GetByID(ctx context.Context, userID, todoID string) (mode
 func (m *mockTodoRepo) Update(ctx context.Context, todo model.Todo) (model.Todo, error) {
 	return m.updateFn(ctx, todo)
 }
+func (m *mockTodoRepo) UpdateIfUnchanged(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	if m.updateIfUnchangedFn != nil {
+		return m.updateIfUnchangedFn(ctx, todo)
+	}
+	return m.updateFn(ctx, todo)
+}
 func (m *mockTodoRepo) Delete(ctx context.Context, userID, todoID string) error {
 	return m.deleteFn(ctx, userID, todoID)
 }
+func (m *mockTodoRepo) DeleteIfUnchanged(ctx context.Context, userID, todoID string, updatedAt time.Time) error {
+	if m.deleteIfUnchangedFn != nil {
+		return m.deleteIfUnchangedFn(ctx, userID, todoID, updatedAt)
+	}
+	return m.deleteFn(ctx, userID, todoID)
+}
 func (m *mockTodoRepo) List(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
 	return m.listFn(ctx, params)
 }
+func (m *mockTodoRepo) ListByIDs(ctx context.Context, userID string, ids []string) ([]model.Todo, error) {
+	return m.listIDsFn(ctx, userID, ids)
+}
 
 var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
@@ -77,6 +97,16 @@ func TestCreate(t *testing.T) {
 			input:   service.CreateTodoInput{Title: "Buy groceries", DueAt: strPtr("not-a-date")},
 			wantErr: "invalid input",
 		},
+		{
+			name:    "success with priority",
+			input:   service.CreateTodoInput{Title: "Buy groceries", Priority: "high", Important: true},
+			wantErr: "",
+		},
+		{
+			name:    "invalid priority",
+			input:   service.CreateTodoInput{Title: "Buy groceries", Priority: "critical"},
+			wantErr: "invalid input",
+		},
 		{
 			name:    "empty title",
 			input:   service.CreateTodoInput{Title: ""},
@@ -129,6 +159,17 @@ func TestCreate(t *testing.T) {
 			if got.Status != model.TodoStatusPending {
 				t.Errorf("expected status=pending, got %s", got.Status)
 			}
+			wantPriority := model.TodoPriority(tt.input.Priority)
+			if wantPriority == "" {
+				wantPriority = model.TodoPriorityNone
+			}
+			if capturedTodo.Priority != wantPriority {
+				t.Errorf("expected priority=%s, got %s", wantPriority, capturedTodo.Priority)
+			}
+			if capturedTodo.Important != tt.input.Important || capturedTodo.Urgent != tt.input.Urgent {
+				t.Errorf("expected important=%v urgent=%v, got important=%v urgent=%v",
+					tt.input.Important, tt.input.Urgent, capturedTodo.Important, capturedTodo.Urgent)
+			}
 			if tt.wantDueAt {
 				if capturedTodo.DueAt == nil {
 					t.Fatal("expected DueAt to be set, got nil")
@@ -387,7 +428,7 @@ func TestUpdateStatus(t *testing.T) {
 				},
 			}
 			svc := service.NewTodoService(repo)
-			got, err := svc.UpdateStatus(context.Background(), "user-1", "todo-1", tt.status)
+			got, err := svc.UpdateStatus(context.Background(), "user-1", "todo-1", tt.status, false)
 
 			if tt.wantErr != "" {
 				if err == nil {
@@ -481,6 +522,71 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestMatrix(t *testing.T) {
+	t.Run("queries each quadrant with its own cursor", func(t *testing.T) {
+		type call struct {
+			important, urgent bool
+			cursor            string
+			sort              model.TodoSort
+		}
+		var calls []call
+		repo := &mockTodoRepo{
+			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+				calls = append(calls, call{*params.Important, *params.Urgent, params.Cursor, params.Sort})
+				todo := sampleTodo()
+				todo.Important = *params.Important
+				todo.Urgent = *params.Urgent
+				return model.TodoListResult{Todos: []model.Todo{todo}}, nil
+			},
+		}
+		svc := service.NewTodoService(repo)
+		got, err := svc.Matrix(context.Background(), service.MatrixParams{
+			UserID: "user-1",
+			Cursors: map[model.EisenhowerQuadrant]string{
+				model.QuadrantSchedule: "schedule-cursor",
+			},
+			Limit: 10,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []call{
+			{true, true, "", model.TodoSortPriority},
+			{true, false, "schedule-cursor", model.TodoSortPriority},
+			{false, true, "", model.TodoSortPriority},
+			{false, false, "", model.TodoSortPriority},
+		}
+		if len(calls) != len(want) {
+			t.Fatalf("expected %d list calls, got %d", len(want), len(calls))
+		}
+		for i := range want {
+			if calls[i] != want[i] {
+				t.Errorf("call %d: expected %+v, got %+v", i, want[i], calls[i])
+			}
+		}
+
+		if !got.Do.Todos[0].Important || !got.Do.Todos[0].Urgent {
+			t.Error("expected do quadrant to hold important+urgent todos")
+		}
+		if got.Eliminate.Todos[0].Important || got.Eliminate.Todos[0].Urgent {
+			t.Error("expected eliminate quadrant to hold neither important nor urgent todos")
+		}
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &mockTodoRepo{
+			listFn: func(ctx context.Context, params model.TodoListParams) (model.TodoListResult, error) {
+				return model.TodoListResult{}, fmt.Errorf("db error")
+			},
+		}
+		svc := service.NewTodoService(repo)
+		if _, err := svc.Matrix(context.Background(), service.MatrixParams{UserID: "user-1"}); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
 func containsStr(s, substr string) bool {
 	return len(s) >= len(substr) && searchStr(s, substr)
 }
@@ -0,0 +1,7 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer records a span for each TodoService and AuthService call. It uses
+// the global provider, which is a no-op unless tracing is configured.
+var tracer = otel.Tracer("github.com/jaekwang-park/todo-api/internal/service")
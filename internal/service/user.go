@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+)
+
+// UserService reads user profiles.
+type UserService struct {
+	repo repository.UserRepository
+}
+
+func NewUserService(repo repository.UserRepository) *UserService {
+	return &UserService{repo: repo}
+}
+
+// GetByIDs returns the users with the given IDs in one query, in no
+// particular order. Missing IDs are left out.
+func (s *UserService) GetByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetByIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	users, err := s.repo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return users, nil
+}
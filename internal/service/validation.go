@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+// Field limits. Emails follow RFC 5321 and passwords Cognito's maximum.
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 10000
+	MaxEmailLength       = 254
+	MaxPasswordLength    = 256
+)
+
+var todoPriorities = []model.TodoPriority{
+	model.TodoPriorityNone,
+	model.TodoPriorityLow,
+	model.TodoPriorityMedium,
+	model.TodoPriorityHigh,
+	model.TodoPriorityUrgent,
+}
+
+// invalidInput marks a validation failure as ErrInvalidInput while keeping
+// the field errors reachable through errors.As.
+func invalidInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+func (in CreateTodoInput) validate() error {
+	return invalidInput(validate.Check(
+		validate.Required("title", in.Title),
+		validate.MaxLength("title", in.Title, MaxTitleLength),
+		validate.MaxLength("description", in.Description, MaxDescriptionLength),
+		validate.When(in.DueAt != nil, func() *validate.FieldError {
+			return dateTimeOrRequired("due_at", *in.DueAt)
+		}),
+		validate.OneOf("priority", model.TodoPriority(in.Priority), todoPriorities...),
+		validate.NonNegative("estimate_minutes", in.EstimateMinutes),
+	))
+}
+
+func (in UpdateTodoInput) validate() error {
+	return invalidInput(validate.Check(
+		validate.When(in.Title != nil, func() *validate.FieldError {
+			return validate.Required("title", *in.Title)
+		}),
+		validate.MaxLength("title", deref(in.Title), MaxTitleLength),
+		validate.MaxLength("description", deref(in.Description), MaxDescriptionLength),
+		validate.When(in.DueAt != nil, func() *validate.FieldError {
+			return dateTimeOrRequired("due_at", *in.DueAt)
+		}),
+		validate.OneOf("priority", model.TodoPriority(deref(in.Priority)), todoPriorities...),
+		validate.NonNegative("estimate_minutes", in.EstimateMinutes),
+	))
+}
+
+// dateTimeOrRequired rejects a timestamp that was sent but is empty, which
+// DateTime alone would accept.
+func dateTimeOrRequired(field, value string) *validate.FieldError {
+	if value == "" {
+		return validate.Fail(field, validate.ReasonInvalidDateTime)
+	}
+	return validate.DateTime(field, value)
+}
+
+func validateStatus(status model.TodoStatus) error {
+	if !status.IsValid() {
+		return invalidInput(validate.Check(validate.Fail("status", validate.ReasonInvalidValue)))
+	}
+	return nil
+}
+
+func emailRules(field, value string) []*validate.FieldError {
+	return []*validate.FieldError{
+		validate.Required(field, value),
+		validate.MaxLength(field, value, MaxEmailLength),
+		validate.Email(field, value),
+	}
+}
+
+func passwordRules(field, value string) []*validate.FieldError {
+	return []*validate.FieldError{
+		validate.Required(field, value),
+		validate.MaxLength(field, value, MaxPasswordLength),
+	}
+}
+
+func checkAll(groups ...[]*validate.FieldError) error {
+	var rules []*validate.FieldError
+	for _, g := range groups {
+		rules = append(rules, g...)
+	}
+	return invalidInput(validate.Check(rules...))
+}
+
+func (in SignUpInput) validate() error {
+	return checkAll(
+		emailRules("email", in.Email),
+		passwordRules("password", in.Password),
+		[]*validate.FieldError{validate.OneOf("language", in.Language, "en", "ko")},
+	)
+}
+
+func (in ConfirmSignUpInput) validate() error {
+	return checkAll(emailRules("email", in.Email), []*validate.FieldError{validate.Required("code", in.Code)})
+}
+
+func (in ResendCodeInput) validate() error {
+	return checkAll(emailRules("email", in.Email))
+}
+
+func (in LoginInput) validate() error {
+	return checkAll(emailRules("email", in.Email), passwordRules("password", in.Password))
+}
+
+func (in RefreshInput) validate() error {
+	return checkAll(emailRules("email", in.Email), []*validate.FieldError{validate.Required("refresh_token", in.RefreshToken)})
+}
+
+func (in ForgotPasswordInput) validate() error {
+	return checkAll(emailRules("email", in.Email))
+}
+
+func (in ConfirmForgotPasswordInput) validate() error {
+	return checkAll(
+		emailRules("email", in.Email),
+		[]*validate.FieldError{validate.Required("code", in.Code)},
+		passwordRules("new_password", in.NewPassword),
+	)
+}
+
+func (in ChangePasswordInput) validate() error {
+	return checkAll(
+		[]*validate.FieldError{validate.Required("access_token", in.AccessToken)},
+		passwordRules("previous_password", in.PreviousPassword),
+		passwordRules("new_password", in.NewPassword),
+	)
+}
+
+func (in LogoutInput) validate() error {
+	return checkAll([]*validate.FieldError{validate.Required("access_token", in.AccessToken)})
+}
@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/service"
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+func TestValidation_CollectsAllFieldErrors(t *testing.T) {
+	negative := -5
+	todos := service.NewTodoService(&mockTodoRepo{})
+	auth := service.NewAuthService(&mockCognitoClient{}, nil)
+
+	tests := []struct {
+		name string
+		call func() error
+		want validate.Errors
+	}{
+		{
+			name: "create todo",
+			call: func() error {
+				_, err := todos.Create(context.Background(), "user-1", service.CreateTodoInput{
+					Description:     strings.Repeat("a", service.MaxDescriptionLength+1),
+					DueAt:           strPtr("tomorrow"),
+					Priority:        "soon",
+					EstimateMinutes: &negative,
+				})
+				return err
+			},
+			want: validate.Errors{
+				{Field: "title", Reason: validate.ReasonRequired},
+				{Field: "description", Reason: validate.ReasonTooLong},
+				{Field: "due_at", Reason: validate.ReasonInvalidDateTime},
+				{Field: "priority", Reason: validate.ReasonInvalidValue},
+				{Field: "estimate_minutes", Reason: validate.ReasonNegative},
+			},
+		},
+		{
+			name: "update todo checks before loading",
+			call: func() error {
+				_, err := todos.Update(context.Background(), "user-1", "todo-1", service.UpdateTodoInput{
+					Title: strPtr(strings.Repeat("가", service.MaxTitleLength+1)),
+					DueAt: strPtr(""),
+				})
+				return err
+			},
+			want: validate.Errors{
+				{Field: "title", Reason: validate.ReasonTooLong},
+				{Field: "due_at", Reason: validate.ReasonInvalidDateTime},
+			},
+		},
+		{
+			name: "sign up",
+			call: func() error {
+				_, err := auth.SignUp(context.Background(), service.SignUpInput{Email: "not-an-email"})
+				return err
+			},
+			want: validate.Errors{
+				{Field: "email", Reason: validate.ReasonInvalidEmail},
+				{Field: "password", Reason: validate.ReasonRequired},
+			},
+		},
+		{
+			name: "change password",
+			call: func() error {
+				return auth.ChangePassword(context.Background(), service.ChangePasswordInput{
+					NewPassword: strings.Repeat("p", service.MaxPasswordLength+1),
+				})
+			},
+			want: validate.Errors{
+				{Field: "access_token", Reason: validate.ReasonRequired},
+				{Field: "previous_password", Reason: validate.ReasonRequired},
+				{Field: "new_password", Reason: validate.ReasonTooLong},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, service.ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+			var got validate.Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("expected field errors, got %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("fields[%d]: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
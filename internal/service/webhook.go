@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+)
+
+const minWebhookSecretLength = 16
+
+// DeliveryNotifier is told when a delivery has been queued outside the
+// normal event flow, such as a manual redelivery.
+type DeliveryNotifier interface {
+	Wake()
+}
+
+type CreateWebhookInput struct {
+	URL        string
+	EventTypes []string
+	Secret     string // generated when empty
+}
+
+type UpdateWebhookInput struct {
+	URL        *string
+	EventTypes []string // nil leaves the subscription unchanged
+	Active     *bool
+}
+
+// WebhookService manages a user's webhook subscriptions and delivery log.
+type WebhookService struct {
+	repo        repository.WebhookRepository
+	notifier    DeliveryNotifier
+	checkTarget func(ctx context.Context, host string) error
+}
+
+// WebhookServiceOption configures optional WebhookService behavior.
+type WebhookServiceOption func(*WebhookService)
+
+// WithWebhookTargetCheck vets the host of every webhook URL before it is
+// saved, such as by refusing hosts that resolve to internal addresses. The
+// check's error is shown to the user.
+func WithWebhookTargetCheck(check func(ctx context.Context, host string) error) WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.checkTarget = check
+	}
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(repo repository.WebhookRepository, notifier DeliveryNotifier, opts ...WebhookServiceOption) *WebhookService {
+	s := &WebhookService{repo: repo, notifier: notifier}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create registers a webhook. The returned webhook is the only place the
+// signing secret is ever shown.
+func (s *WebhookService) Create(ctx context.Context, userID string, input CreateWebhookInput) (model.Webhook, error) {
+	if err := s.validateURL(ctx, input.URL); err != nil {
+		return model.Webhook{}, err
+	}
+	eventTypes, err := parseEventTypes(input.EventTypes)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		secret = newWebhookSecret()
+	} else if len(secret) < minWebhookSecretLength {
+		return model.Webhook{}, fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidInput, minWebhookSecretLength)
+	}
+
+	created, err := s.repo.Create(ctx, model.Webhook{
+		UserID:     userID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+	})
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *WebhookService) List(ctx context.Context, userID string) ([]model.Webhook, error) {
+	webhooks, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, userID, webhookID string) (model.Webhook, error) {
+	webhook, err := s.repo.GetByID(ctx, userID, webhookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// Update changes a webhook's URL, event types or active flag. Re-activating
+// a webhook that was disabled after repeated failures resets its failure count.
+func (s *WebhookService) Update(ctx context.Context, userID, webhookID string, input UpdateWebhookInput) (model.Webhook, error) {
+	existing, err := s.GetByID(ctx, userID, webhookID)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	if input.URL != nil {
+		if err := s.validateURL(ctx, *input.URL); err != nil {
+			return model.Webhook{}, err
+		}
+		existing.URL = *input.URL
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := parseEventTypes(input.EventTypes)
+		if err != nil {
+			return model.Webhook{}, err
+		}
+		existing.EventTypes = eventTypes
+	}
+	if input.Active != nil {
+		existing.Active = *input.Active
+	}
+
+	updated, err := s.repo.Update(ctx, existing)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	updated.Secret = ""
+	return updated, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, userID, webhookID string) error {
+	if err := s.repo.Delete(ctx, userID, webhookID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent deliveries for a webhook.
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	if _, err := s.GetByID(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, userID, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a new delivery of a previous delivery's payload.
+func (s *WebhookService) Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (model.WebhookDelivery, error) {
+	webhook, err := s.GetByID(ctx, userID, webhookID)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if !webhook.Active {
+		return model.WebhookDelivery{}, fmt.Errorf("%w: webhook is disabled", ErrConflict)
+	}
+
+	original, err := s.repo.GetDelivery(ctx, userID, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, ErrNotFound
+		}
+		return model.WebhookDelivery{}, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if original.WebhookID != webhookID {
+		return model.WebhookDelivery{}, ErrNotFound
+	}
+
+	now := time.Now()
+	delivery, err := s.repo.CreateDelivery(ctx, model.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		UserID:        userID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	})
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to queue redelivery: %w", err)
+	}
+
+	if s.notifier != nil {
+		s.notifier.Wake()
+	}
+	return delivery, nil
+}
+
+func (s *WebhookService) validateURL(ctx context.Context, raw string) error {
+	if raw == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidInput)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidInput)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: url must not contain credentials", ErrInvalidInput)
+	}
+	if s.checkTarget != nil {
+		if err := s.checkTarget(ctx, u.Hostname()); err != nil {
+			return fmt.Errorf("%w: url %v", ErrInvalidInput, err)
+		}
+	}
+	return nil
+}
+
+func parseEventTypes(raw []string) ([]model.EventType, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: event_types must not be empty", ErrInvalidInput)
+	}
+
+	seen := make(map[model.EventType]bool, len(raw))
+	types := make([]model.EventType, 0, len(raw))
+	for _, r := range raw {
+		t := model.EventType(r)
+		if !t.IsValid() {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, r)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
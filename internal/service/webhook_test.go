@@ -0,0 +1,260 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/service"
+)
+
+type mockWebhookRepo struct {
+	webhooks   map[string]model.Webhook
+	deliveries map[string]model.WebhookDelivery
+	created    []model.WebhookDelivery
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{
+		webhooks:   make(map[string]model.Webhook),
+		deliveries: make(map[string]model.WebhookDelivery),
+	}
+}
+
+func (m *mockWebhookRepo) Create(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	w.ID = "wh-1"
+	m.webhooks[w.ID] = w
+	return w, nil
+}
+func (m *mockWebhookRepo) GetByID(ctx context.Context, userID, id string) (model.Webhook, error) {
+	w, ok := m.webhooks[id]
+	if !ok || w.UserID != userID {
+		return model.Webhook{}, sql.ErrNoRows
+	}
+	return w, nil
+}
+func (m *mockWebhookRepo) Get(ctx context.Context, id string) (model.Webhook, error) {
+	return m.webhooks[id], nil
+}
+func (m *mockWebhookRepo) List(ctx context.Context, userID string) ([]model.Webhook, error) {
+	var out []model.Webhook
+	for _, w := range m.webhooks {
+		if w.UserID == userID {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+func (m *mockWebhookRepo) Update(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	stored := m.webhooks[w.ID]
+	w.Secret = stored.Secret
+	m.webhooks[w.ID] = w
+	return w, nil
+}
+func (m *mockWebhookRepo) Delete(ctx context.Context, userID, id string) error {
+	if _, ok := m.webhooks[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+func (m *mockWebhookRepo) ListSubscribed(ctx context.Context, userID string, et model.EventType) ([]model.Webhook, error) {
+	return nil, nil
+}
+func (m *mockWebhookRepo) ResetFailures(ctx context.Context, id string) error { return nil }
+func (m *mockWebhookRepo) RecordFailure(ctx context.Context, id string, max int) (bool, error) {
+	return false, nil
+}
+func (m *mockWebhookRepo) CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	d.ID = "dlv-new"
+	m.created = append(m.created, d)
+	return d, nil
+}
+func (m *mockWebhookRepo) GetDelivery(ctx context.Context, userID, id string) (model.WebhookDelivery, error) {
+	d, ok := m.deliveries[id]
+	if !ok || d.UserID != userID {
+		return model.WebhookDelivery{}, sql.ErrNoRows
+	}
+	return d, nil
+}
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	return nil
+}
+
+type countingNotifier struct{ wakes int }
+
+func (n *countingNotifier) Wake() { n.wakes++ }
+
+func TestWebhookService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   service.CreateWebhookInput
+		wantErr error
+	}{
+		{"valid", service.CreateWebhookInput{URL: "https://example.com/hook", EventTypes: []string{"todo.created"}}, nil},
+		{"custom secret", service.CreateWebhookInput{URL: "http://example.com", EventTypes: []string{"todo.deleted"}, Secret: "0123456789abcdef"}, nil},
+		{"missing url", service.CreateWebhookInput{EventTypes: []string{"todo.created"}}, service.ErrInvalidInput},
+		{"relative url", service.CreateWebhookInput{URL: "/hook", EventTypes: []string{"todo.created"}}, service.ErrInvalidInput},
+		{"unsupported scheme", service.CreateWebhookInput{URL: "ftp://example.com", EventTypes: []string{"todo.created"}}, service.ErrInvalidInput},
+		{"credentials in url", service.CreateWebhookInput{URL: "https://u:p@example.com", EventTypes: []string{"todo.created"}}, service.ErrInvalidInput},
+		{"no event types", service.CreateWebhookInput{URL: "https://example.com"}, service.ErrInvalidInput},
+		{"unknown event type", service.CreateWebhookInput{URL: "https://example.com", EventTypes: []string{"todo.archived"}}, service.ErrInvalidInput},
+		{"short secret", service.CreateWebhookInput{URL: "https://example.com", EventTypes: []string{"todo.created"}, Secret: "short"}, service.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewWebhookService(newMockWebhookRepo(), nil)
+			result, err := svc.Create(context.Background(), "user-1", tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Active {
+				t.Error("expected new webhook to be active")
+			}
+			if tt.input.Secret != "" && result.Secret != tt.input.Secret {
+				t.Errorf("expected given secret to be kept, got %q", result.Secret)
+			}
+			if tt.input.Secret == "" && !strings.HasPrefix(result.Secret, "whsec_") {
+				t.Errorf("expected generated secret, got %q", result.Secret)
+			}
+		})
+	}
+}
+
+func TestWebhookService_TargetCheck(t *testing.T) {
+	var checked []string
+	svc := service.NewWebhookService(newMockWebhookRepo(), nil, service.WithWebhookTargetCheck(func(ctx context.Context, host string) error {
+		checked = append(checked, host)
+		if host == "169.254.169.254" {
+			return errors.New("host 169.254.169.254 is not a public address")
+		}
+		return nil
+	}))
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "user-1", service.CreateWebhookInput{URL: "http://169.254.169.254/latest/meta-data", EventTypes: []string{"todo.created"}}); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for the metadata address, got %v", err)
+	}
+	if _, err := svc.Create(ctx, "user-1", service.CreateWebhookInput{URL: "https://example.com:8443/hook", EventTypes: []string{"todo.created"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	url := "http://169.254.169.254/"
+	if _, err := svc.Update(ctx, "user-1", "wh-1", service.UpdateWebhookInput{URL: &url}); !errors.Is(err, service.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput when updating to the metadata address, got %v", err)
+	}
+	if want := []string{"169.254.169.254", "example.com", "169.254.169.254"}; strings.Join(checked, ",") != strings.Join(want, ",") {
+		t.Errorf("expected hosts %v checked, got %v", want, checked)
+	}
+}
+
+func TestWebhookService_HidesSecret(t *testing.T) {
+	repo := newMockWebhookRepo()
+	svc := service.NewWebhookService(repo, nil)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "user-1", service.CreateWebhookInput{URL: "https://example.com", EventTypes: []string{"todo.created"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := svc.GetByID(ctx, "user-1", "wh-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Secret != "" {
+		t.Error("expected secret to be hidden on get")
+	}
+
+	list, _ := svc.List(ctx, "user-1")
+	if len(list) != 1 || list[0].Secret != "" {
+		t.Error("expected secret to be hidden on list")
+	}
+
+	url := "https://example.com/v2"
+	updated, err := svc.Update(ctx, "user-1", "wh-1", service.UpdateWebhookInput{URL: &url})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Secret != "" || updated.URL != url {
+		t.Errorf("unexpected update result: %+v", updated)
+	}
+	if repo.webhooks["wh-1"].Secret == "" {
+		t.Error("expected stored secret to survive update")
+	}
+
+	if _, err := svc.GetByID(ctx, "user-2", "wh-1"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for other user, got %v", err)
+	}
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	setup := func(active bool) (*mockWebhookRepo, *countingNotifier, *service.WebhookService) {
+		repo := newMockWebhookRepo()
+		repo.webhooks["wh-1"] = model.Webhook{ID: "wh-1", UserID: "user-1", Active: active}
+		repo.webhooks["wh-2"] = model.Webhook{ID: "wh-2", UserID: "user-1", Active: true}
+		repo.deliveries["dlv-1"] = model.WebhookDelivery{
+			ID:        "dlv-1",
+			WebhookID: "wh-1",
+			UserID:    "user-1",
+			EventID:   "evt_1",
+			EventType: model.EventTodoCreated,
+			Payload:   []byte(`{"id":"evt_1"}`),
+			Status:    model.DeliveryStatusFailed,
+		}
+		notifier := &countingNotifier{}
+		return repo, notifier, service.NewWebhookService(repo, notifier)
+	}
+
+	t.Run("queues new delivery", func(t *testing.T) {
+		repo, notifier, svc := setup(true)
+		d, err := svc.Redeliver(context.Background(), "user-1", "wh-1", "dlv-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Status != model.DeliveryStatusPending || d.EventID != "evt_1" || string(d.Payload) != `{"id":"evt_1"}` {
+			t.Errorf("unexpected delivery: %+v", d)
+		}
+		if len(repo.created) != 1 || notifier.wakes != 1 {
+			t.Errorf("expected 1 delivery and 1 wake, got %d and %d", len(repo.created), notifier.wakes)
+		}
+	})
+
+	t.Run("disabled webhook", func(t *testing.T) {
+		_, _, svc := setup(false)
+		if _, err := svc.Redeliver(context.Background(), "user-1", "wh-1", "dlv-1"); !errors.Is(err, service.ErrConflict) {
+			t.Errorf("expected ErrConflict, got %v", err)
+		}
+	})
+
+	t.Run("delivery of another webhook", func(t *testing.T) {
+		_, _, svc := setup(true)
+		if _, err := svc.Redeliver(context.Background(), "user-1", "wh-2", "dlv-1"); !errors.Is(err, service.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unknown delivery", func(t *testing.T) {
+		_, _, svc := setup(true)
+		if _, err := svc.Redeliver(context.Background(), "user-1", "wh-1", "missing"); !errors.Is(err, service.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+)
+
+// Config controls the broker's memory bounds. Zero values use the defaults.
+type Config struct {
+	// ReplaySize is how many recent events are kept for Last-Event-ID resume.
+	ReplaySize int
+	// BufferSize is how many undelivered events a single subscriber may have
+	// queued before it is dropped as too slow.
+	BufferSize int
+}
+
+func (c Config) withDefaults() Config {
+	if c.ReplaySize <= 0 {
+		c.ReplaySize = 1024
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = 64
+	}
+	return c
+}
+
+// Message is an event with its position in the broker's sequence.
+type Message struct {
+	ID    string
+	Seq   uint64
+	Event model.Event
+}
+
+// Broker fans todo events out to per-user subscribers and keeps a bounded
+// replay buffer so reconnecting clients can resume from their last event.
+// It implements service.EventPublisher.
+//
+// Message IDs are "<epoch>-<seq>". The epoch changes on every process start,
+// so an ID from a previous run is recognised as unresumable instead of being
+// compared against an unrelated sequence.
+type Broker struct {
+	cfg   Config
+	epoch string
+
+	mu     sync.Mutex
+	seq    uint64
+	replay []Message // ring buffer, oldest at replay[head] once full
+	head   int
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// NewBroker creates a new Broker.
+func NewBroker(cfg Config) *Broker {
+	cfg = cfg.withDefaults()
+	return &Broker{
+		cfg:    cfg,
+		epoch:  strconv.FormatInt(time.Now().UnixNano(), 36),
+		replay: make([]Message, 0, cfg.ReplaySize),
+		subs:   make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish records the event and delivers it to the user's subscribers.
+// It never blocks: a subscriber whose buffer is full is dropped and must
+// reconnect, resuming from the replay buffer.
+func (b *Broker) Publish(ctx context.Context, event model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	msg := Message{ID: b.messageID(b.seq), Seq: b.seq, Event: event}
+	if len(b.replay) < b.cfg.ReplaySize {
+		b.replay = append(b.replay, msg)
+	} else {
+		b.replay[b.head] = msg
+		b.head = (b.head + 1) % len(b.replay)
+	}
+
+	for sub := range b.subs[event.UserID] {
+		select {
+		case sub.ch <- msg:
+		default:
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for userID's events. When lastEventID is
+// set, the events the user missed since then are returned for replay; ok is
+// false if they can no longer be replayed, because the ID is unknown or the
+// events have been evicted, and the client should refetch its state instead.
+func (b *Broker) Subscribe(userID, lastEventID string) (sub *Subscription, missed []Message, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{
+		broker: b,
+		userID: userID,
+		ch:     make(chan Message, b.cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+	if b.closed {
+		close(sub.done)
+		return sub, nil, true
+	}
+
+	ok = true
+	if lastEventID != "" {
+		missed, ok = b.missedLocked(userID, lastEventID)
+	}
+
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+
+	return sub, missed, ok
+}
+
+// Close drops every subscriber and stops accepting events, letting open
+// streams finish so the HTTP server can shut down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.removeLocked(sub)
+		}
+	}
+}
+
+func (b *Broker) messageID(seq uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (b *Broker) parseMessageID(id string) (uint64, error) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != b.epoch {
+		return 0, fmt.Errorf("event id %q is not from this stream", id)
+	}
+	return strconv.ParseUint(seq, 10, 64)
+}
+
+func (b *Broker) missedLocked(userID, lastEventID string) ([]Message, bool) {
+	lastSeq, err := b.parseMessageID(lastEventID)
+	if err != nil || lastSeq > b.seq {
+		return nil, false
+	}
+	if lastSeq == b.seq {
+		return nil, true
+	}
+
+	oldest := b.replay[b.head].Seq
+	if lastSeq+1 < oldest {
+		return nil, false
+	}
+
+	var missed []Message
+	for i := range b.replay {
+		msg := b.replay[(b.head+i)%len(b.replay)]
+		if msg.Seq > lastSeq && msg.Event.UserID == userID {
+			missed = append(missed, msg)
+		}
+	}
+	return missed, true
+}
+
+func (b *Broker) removeLocked(sub *Subscription) {
+	subs := b.subs[sub.userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subs, sub.userID)
+	}
+	close(sub.done)
+}
+
+// Subscription is one client's view of the event stream.
+type Subscription struct {
+	broker *Broker
+	userID string
+	ch     chan Message
+	done   chan struct{}
+}
+
+// Messages delivers the subscriber's events in order.
+func (s *Subscription) Messages() <-chan Message {
+	return s.ch
+}
+
+// Done is closed when the subscription ends: it was closed, dropped for
+// falling behind, or the broker shut down. Messages still queued in
+// Messages may be discarded.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.removeLocked(s)
+}
@@ -0,0 +1,143 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/stream"
+)
+
+func event(userID, todoID string) model.Event {
+	return model.Event{ID: "evt_" + todoID, Type: model.EventTodoCreated, UserID: userID, TodoID: todoID}
+}
+
+func receive(t *testing.T, sub *stream.Subscription) stream.Message {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		return msg
+	default:
+		t.Fatal("expected a queued message")
+		return stream.Message{}
+	}
+}
+
+func TestBroker_DeliversToOwnerOnly(t *testing.T) {
+	b := stream.NewBroker(stream.Config{})
+	alice, _, _ := b.Subscribe("alice", "")
+	bob, _, _ := b.Subscribe("bob", "")
+	defer alice.Close()
+	defer bob.Close()
+
+	b.Publish(context.Background(), event("alice", "todo-1"))
+
+	if msg := receive(t, alice); msg.Event.TodoID != "todo-1" || msg.ID == "" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	select {
+	case msg := <-bob.Messages():
+		t.Errorf("bob received alice's event: %+v", msg)
+	default:
+	}
+}
+
+func TestBroker_Resume(t *testing.T) {
+	b := stream.NewBroker(stream.Config{ReplaySize: 4})
+	sub, _, _ := b.Subscribe("alice", "")
+	ctx := context.Background()
+
+	b.Publish(ctx, event("alice", "todo-1"))
+	first := receive(t, sub)
+	sub.Close()
+
+	b.Publish(ctx, event("bob", "todo-2"))
+	b.Publish(ctx, event("alice", "todo-3"))
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantTodos   []string
+		wantOK      bool
+	}{
+		{"missed events", first.ID, []string{"todo-3"}, true},
+		{"no id", "", nil, true},
+		{"foreign epoch", "abc-1", nil, false},
+		{"malformed", "nonsense", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, missed, ok := b.Subscribe("alice", tt.lastEventID)
+			defer sub.Close()
+
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if len(missed) != len(tt.wantTodos) {
+				t.Fatalf("expected %d missed, got %d", len(tt.wantTodos), len(missed))
+			}
+			for i, msg := range missed {
+				if msg.Event.TodoID != tt.wantTodos[i] {
+					t.Errorf("missed[%d]: expected %s, got %s", i, tt.wantTodos[i], msg.Event.TodoID)
+				}
+			}
+		})
+	}
+
+	t.Run("evicted", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			b.Publish(ctx, event("alice", "later"))
+		}
+		sub, missed, ok := b.Subscribe("alice", first.ID)
+		defer sub.Close()
+		if ok || len(missed) != 0 {
+			t.Errorf("expected unresumable, got ok=%v missed=%d", ok, len(missed))
+		}
+	})
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b := stream.NewBroker(stream.Config{BufferSize: 2})
+	slow, _, _ := b.Subscribe("alice", "")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		b.Publish(ctx, event("alice", "todo"))
+	}
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("expected slow subscriber to be dropped")
+	}
+
+	// A new subscription still works.
+	fresh, _, _ := b.Subscribe("alice", "")
+	defer fresh.Close()
+	b.Publish(ctx, event("alice", "todo-4"))
+	if msg := receive(t, fresh); msg.Event.TodoID != "todo-4" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	b := stream.NewBroker(stream.Config{})
+	sub, _, _ := b.Subscribe("alice", "")
+
+	b.Close()
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("expected subscription to end on close")
+	}
+	sub.Close() // idempotent
+
+	late, _, _ := b.Subscribe("alice", "")
+	select {
+	case <-late.Done():
+	default:
+		t.Error("expected subscriptions after close to end immediately")
+	}
+}
@@ -0,0 +1,40 @@
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/telemetry"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		exporter string
+		wantErr  bool
+	}{
+		{telemetry.ExporterNone, false},
+		{telemetry.ExporterStdout, false},
+		{"jaeger", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.exporter, func(t *testing.T) {
+			shutdown, err := telemetry.Setup(context.Background(), telemetry.Config{
+				Exporter:    tt.exporter,
+				ServiceName: "todo-api",
+				Environment: "local",
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown failed: %v", err)
+			}
+		})
+	}
+}
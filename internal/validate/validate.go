@@ -0,0 +1,142 @@
+// Package validate checks input fields and collects every failure, so a
+// request is rejected with all of its problems at once.
+//
+// Each rule returns nil when the value is acceptable. Rules other than
+// Required accept empty values, so an empty field is reported once, as
+// required, and only when it is.
+package validate
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Reasons reported in FieldError.
+const (
+	ReasonRequired        = "required"
+	ReasonTooLong         = "too_long"
+	ReasonInvalidUUID     = "invalid_uuid"
+	ReasonInvalidDateTime = "invalid_date_time"
+	ReasonInvalidEmail    = "invalid_email"
+	ReasonNegative        = "negative"
+	ReasonInvalidValue    = "invalid_value"
+	ReasonInvalidType     = "invalid_type"
+	ReasonUnknownField    = "unknown_field"
+	ReasonReadOnly        = "read_only"
+)
+
+// FieldError is one field that failed validation.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Errors is every field that failed validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Reason
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Check returns the failed rules as Errors, or nil when all passed.
+func Check(rules ...*FieldError) error {
+	var errs Errors
+	for _, fe := range rules {
+		if fe != nil {
+			errs = append(errs, *fe)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Fail reports field as failing for reason.
+func Fail(field, reason string) *FieldError {
+	return &FieldError{Field: field, Reason: reason}
+}
+
+// When applies rule only if cond holds, such as for fields of a partial
+// update that were sent.
+func When(cond bool, rule func() *FieldError) *FieldError {
+	if !cond {
+		return nil
+	}
+	return rule()
+}
+
+func Required(field, value string) *FieldError {
+	if value == "" {
+		return Fail(field, ReasonRequired)
+	}
+	return nil
+}
+
+// MaxLength limits value to max characters.
+func MaxLength(field, value string, max int) *FieldError {
+	if utf8.RuneCountInString(value) > max {
+		return Fail(field, ReasonTooLong)
+	}
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func UUID(field, value string) *FieldError {
+	if value != "" && !uuidPattern.MatchString(value) {
+		return Fail(field, ReasonInvalidUUID)
+	}
+	return nil
+}
+
+// DateTime requires an RFC 3339 timestamp.
+func DateTime(field, value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return Fail(field, ReasonInvalidDateTime)
+	}
+	return nil
+}
+
+// Email requires a bare address such as user@example.com.
+func Email(field, value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return Fail(field, ReasonInvalidEmail)
+	}
+	return nil
+}
+
+// NonNegative rejects negative values. Nil means not set.
+func NonNegative(field string, value *int) *FieldError {
+	if value != nil && *value < 0 {
+		return Fail(field, ReasonNegative)
+	}
+	return nil
+}
+
+// OneOf requires value to be one of allowed.
+func OneOf[T ~string](field string, value T, allowed ...T) *FieldError {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return Fail(field, ReasonInvalidValue)
+}
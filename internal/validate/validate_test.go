@@ -0,0 +1,87 @@
+package validate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/validate"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       *validate.FieldError
+		wantReason string // empty means the rule passes
+	}{
+		{"required ok", validate.Required("title", "x"), ""},
+		{"required empty", validate.Required("title", ""), validate.ReasonRequired},
+		{"max length ok", validate.MaxLength("title", "héllo", 5), ""},
+		{"max length counts characters", validate.MaxLength("title", "héllo!", 5), validate.ReasonTooLong},
+		{"uuid ok", validate.UUID("id", "6f1c2a4e-3b5d-4e7f-8a9b-0c1d2e3f4a5b"), ""},
+		{"uuid empty", validate.UUID("id", ""), ""},
+		{"uuid malformed", validate.UUID("id", "todo-1"), validate.ReasonInvalidUUID},
+		{"date time ok", validate.DateTime("due_at", "2025-01-01T09:00:00+09:00"), ""},
+		{"date time date only", validate.DateTime("due_at", "2025-01-01"), validate.ReasonInvalidDateTime},
+		{"email ok", validate.Email("email", "a@example.com"), ""},
+		{"email with name", validate.Email("email", "A <a@example.com>"), validate.ReasonInvalidEmail},
+		{"email malformed", validate.Email("email", "a.example.com"), validate.ReasonInvalidEmail},
+		{"non-negative nil", validate.NonNegative("estimate_minutes", nil), ""},
+		{"non-negative zero", validate.NonNegative("estimate_minutes", intPtr(0)), ""},
+		{"negative", validate.NonNegative("estimate_minutes", intPtr(-1)), validate.ReasonNegative},
+		{"one of ok", validate.OneOf("priority", "low", "low", "high"), ""},
+		{"one of other", validate.OneOf("priority", "soon", "low", "high"), validate.ReasonInvalidValue},
+		{"when false skips", validate.When(false, func() *validate.FieldError { return validate.Fail("x", "y") }), ""},
+		{"when true applies", validate.When(true, func() *validate.FieldError { return validate.Fail("x", "y") }), "y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantReason == "" {
+				if tt.rule != nil {
+					t.Fatalf("expected pass, got %+v", *tt.rule)
+				}
+				return
+			}
+			if tt.rule == nil {
+				t.Fatalf("expected %s, got pass", tt.wantReason)
+			}
+			if tt.rule.Reason != tt.wantReason {
+				t.Errorf("reason: got %q, want %q", tt.rule.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := validate.Check(validate.Required("title", "x"), nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	err := validate.Check(
+		validate.Required("title", ""),
+		validate.Required("email", "a@example.com"),
+		validate.UUID("id", "bad"),
+	)
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validate.Errors, got %T", err)
+	}
+	want := validate.Errors{
+		{Field: "title", Reason: validate.ReasonRequired},
+		{Field: "id", Reason: validate.ReasonInvalidUUID},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %v, want %v", errs, want)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("errs[%d]: got %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+	if got := err.Error(); !strings.Contains(got, "title: required; id: invalid_uuid") {
+		t.Errorf("message: got %q", got)
+	}
+}
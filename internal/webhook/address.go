@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// nonPublicPrefixes are ranges that netip does not classify as private or
+// link-local but that are still not reachable on the public internet, or
+// that translate to addresses that may not be.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// IsPublic reports whether ip is a publicly routable unicast address.
+// Loopback, private, link-local (which includes the cloud metadata and ECS
+// credential endpoints at 169.254.169.254 and 169.254.170.2) and other
+// special-purpose addresses are not.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves host and returns an error unless every address it
+// resolves to is public. The error is suitable for showing to the user who
+// registered the webhook.
+func CheckHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("host %s is not a public address", host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("host %s does not resolve", host)
+	}
+	for _, ip := range addrs {
+		if !IsPublic(ip) {
+			return fmt.Errorf("host %s resolves to a non-public address", host)
+		}
+	}
+	return nil
+}
+
+// dialPublicOnly is a net.Dialer Control function that refuses connections
+// to non-public addresses. It runs after name resolution, so a host that
+// passed CheckHost and later resolves elsewhere is still refused.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook address %s: %w", address, err)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+	}
+	return nil
+}
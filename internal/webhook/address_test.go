@@ -0,0 +1,65 @@
+package webhook_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/internal/webhook"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // instance metadata
+		{"169.254.170.2", false},   // ECS task credentials
+		{"fd00:ec2::254", false},
+		{"fe80::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := webhook.IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"93.184.216.34", false},
+		{"169.254.169.254", true},
+		{"::1", true},
+		{"localhost", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := webhook.CheckHost(context.Background(), tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckHost(%s) = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/repository"
+)
+
+// Config tunes delivery retries. Zero values fall back to the defaults.
+type Config struct {
+	MaxAttempts  int           // attempts per delivery before it is marked failed
+	MaxFailures  int           // consecutive failed attempts before a webhook is disabled
+	BaseBackoff  time.Duration // delay before the first retry; doubles per attempt
+	MaxBackoff   time.Duration
+	PollInterval time.Duration // how often due retries are picked up
+	Timeout      time.Duration // per-request timeout
+	BatchSize    int
+	Concurrency  int
+	QueueSize    int // published events buffered before deliveries are recorded inline
+
+	// AllowPrivateTargets delivers to loopback, private and other
+	// non-public addresses. It is for local development only.
+	AllowPrivateTargets bool
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 8
+	}
+	if c.MaxFailures <= 0 {
+		c.MaxFailures = 20
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 30 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 6 * time.Hour
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 10 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 256
+	}
+	return c
+}
+
+// Payload is the JSON body POSTed to subscribers.
+type Payload struct {
+	ID         string          `json:"id"`
+	Type       model.EventType `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       PayloadData     `json:"data"`
+}
+
+type PayloadData struct {
+	TodoID string      `json:"todo_id"`
+	Todo   *model.Todo `json:"todo,omitempty"`
+}
+
+// Dispatcher records a delivery for every webhook subscribed to an event and
+// sends them in the background, retrying failures with exponential backoff.
+// Deliveries live in the database, so pending retries survive restarts and
+// are shared by every instance.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	cfg    Config
+	client *http.Client
+	events chan model.Event
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+// NewDispatcher creates a Dispatcher. Call Run to start delivering.
+func NewDispatcher(repo repository.WebhookRepository, cfg Config) *Dispatcher {
+	cfg = cfg.withDefaults()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateTargets {
+		// Addresses are checked as connections are made, so neither DNS
+		// rebinding nor a proxy can reach an internal address.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialPublicOnly}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+
+	return &Dispatcher{
+		repo: repo,
+		cfg:  cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			// Subscribers must answer at the registered URL.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		events: make(chan model.Event, cfg.QueueSize),
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+}
+
+// Publish implements service.EventPublisher. It hands the event to Run,
+// which records the deliveries off the request path. When Run falls behind
+// and the queue is full, the deliveries are recorded inline rather than
+// dropped.
+func (d *Dispatcher) Publish(ctx context.Context, event model.Event) {
+	select {
+	case d.events <- event:
+	default:
+		d.Queue(context.WithoutCancel(ctx), event)
+	}
+}
+
+// Queue records a delivery of event for every webhook subscribed to it.
+func (d *Dispatcher) Queue(ctx context.Context, event model.Event) {
+	webhooks, err := d.repo.ListSubscribed(ctx, event.UserID, event.Type)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list webhooks for event", "event_type", event.Type, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       PayloadData{TodoID: event.TodoID, Todo: event.Todo},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode webhook payload", "event_type", event.Type, "error", err)
+		return
+	}
+
+	now := d.now()
+	for _, w := range webhooks {
+		_, err := d.repo.CreateDelivery(ctx, model.WebhookDelivery{
+			WebhookID:     w.ID,
+			UserID:        w.UserID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to queue webhook delivery", "webhook_id", w.ID, "error", err)
+		}
+	}
+
+	d.Wake()
+}
+
+// Wake asks the delivery loop to look for due deliveries now.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run records deliveries for published events and delivers due webhooks
+// until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.queueEvents(ctx)
+	}()
+	defer wg.Wait()
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			slog.ErrorContext(ctx, "webhook delivery round failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// queueEvents records deliveries for published events until ctx is
+// cancelled, and then for those still buffered.
+func (d *Dispatcher) queueEvents(ctx context.Context) {
+	for {
+		select {
+		case event := <-d.events:
+			d.Queue(ctx, event)
+		case <-ctx.Done():
+			ctx = context.WithoutCancel(ctx)
+			for {
+				select {
+				case event := <-d.events:
+					d.Queue(ctx, event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// ProcessDue attempts every delivery that is due and returns how many were
+// attempted.
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		// The lease must outlast an attempt so a slow receiver is not retried concurrently.
+		deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.cfg.BatchSize, 2*d.cfg.Timeout)
+		if err != nil {
+			return total, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return total, nil
+		}
+
+		sem := make(chan struct{}, d.cfg.Concurrency)
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(delivery model.WebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				d.attempt(ctx, delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		total += len(deliveries)
+		if len(deliveries) < d.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery model.WebhookDelivery) {
+	webhook, err := d.repo.Get(ctx, delivery.WebhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load webhook for delivery", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+
+	delivery.Attempts++
+	if !webhook.Active {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook is disabled"
+		d.saveDelivery(ctx, delivery)
+		return
+	}
+
+	status, sendErr := d.send(ctx, webhook, delivery)
+	if sendErr != nil && ctx.Err() != nil {
+		// Shutting down: the receiver is not at fault. Release the lease so
+		// another instance retries the attempt straight away.
+		now := d.now()
+		delivery.Attempts--
+		delivery.NextAttemptAt = &now
+		d.saveDelivery(context.WithoutCancel(ctx), delivery)
+		return
+	}
+	delivery.ResponseStatus = status
+
+	if sendErr == nil {
+		now := d.now()
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		d.saveDelivery(ctx, delivery)
+		if webhook.FailureCount > 0 {
+			if err := d.repo.ResetFailures(ctx, webhook.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to reset webhook failures", "webhook_id", webhook.ID, "error", err)
+			}
+		}
+		return
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+	} else {
+		next := d.now().Add(d.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	d.saveDelivery(ctx, delivery)
+
+	disabled, err := d.repo.RecordFailure(ctx, webhook.ID, d.cfg.MaxFailures)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook failure", "webhook_id", webhook.ID, "error", err)
+		return
+	}
+	if disabled {
+		slog.WarnContext(ctx, "webhook disabled after repeated failures",
+			"webhook_id", webhook.ID, "failures", d.cfg.MaxFailures)
+	}
+}
+
+// send POSTs the payload and returns the response status. Any non-2xx
+// response is an error.
+func (d *Dispatcher) send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+
+	ts := d.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhooks/1")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(ts.Unix()))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, ts, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the retry that follows the given attempt.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func (d *Dispatcher) saveDelivery(ctx context.Context, delivery model.WebhookDelivery) {
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "failed to save webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
@@ -0,0 +1,377 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/model"
+	"github.com/jaekwang-park/todo-api/internal/webhook"
+)
+
+// memWebhookRepo is an in-memory repository.WebhookRepository.
+type memWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   map[string]*model.Webhook
+	deliveries []*model.WebhookDelivery
+}
+
+func newMemWebhookRepo(webhooks ...model.Webhook) *memWebhookRepo {
+	m := &memWebhookRepo{webhooks: make(map[string]*model.Webhook)}
+	for i := range webhooks {
+		w := webhooks[i]
+		m.webhooks[w.ID] = &w
+	}
+	return m
+}
+
+func (m *memWebhookRepo) Create(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	return w, nil
+}
+func (m *memWebhookRepo) GetByID(ctx context.Context, userID, id string) (model.Webhook, error) {
+	return m.Get(ctx, id)
+}
+func (m *memWebhookRepo) Get(ctx context.Context, id string) (model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.webhooks[id]
+	if !ok {
+		return model.Webhook{}, sql.ErrNoRows
+	}
+	return *w, nil
+}
+func (m *memWebhookRepo) List(ctx context.Context, userID string) ([]model.Webhook, error) {
+	return nil, nil
+}
+func (m *memWebhookRepo) Update(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	return w, nil
+}
+func (m *memWebhookRepo) Delete(ctx context.Context, userID, id string) error {
+	return nil
+}
+func (m *memWebhookRepo) ListSubscribed(ctx context.Context, userID string, et model.EventType) ([]model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []model.Webhook
+	for _, w := range m.webhooks {
+		if w.UserID == userID && w.Active && w.Subscribes(et) {
+			out = append(out, *w)
+		}
+	}
+	return out, nil
+}
+func (m *memWebhookRepo) ResetFailures(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.webhooks[id].FailureCount = 0
+	return nil
+}
+func (m *memWebhookRepo) RecordFailure(ctx context.Context, id string, maxFailures int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := m.webhooks[id]
+	w.FailureCount++
+	if w.Active && w.FailureCount >= maxFailures {
+		w.Active = false
+		return true, nil
+	}
+	return false, nil
+}
+func (m *memWebhookRepo) CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d.ID = "dlv-" + strconv.Itoa(len(m.deliveries)+1)
+	m.deliveries = append(m.deliveries, &d)
+	return d, nil
+}
+func (m *memWebhookRepo) GetDelivery(ctx context.Context, userID, id string) (model.WebhookDelivery, error) {
+	return model.WebhookDelivery{}, sql.ErrNoRows
+}
+func (m *memWebhookRepo) ListDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *memWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var out []model.WebhookDelivery
+	for _, d := range m.deliveries {
+		if len(out) == limit {
+			break
+		}
+		if d.Status == model.DeliveryStatusPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			leased := now.Add(lease)
+			d.NextAttemptAt = &leased
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+func (m *memWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.deliveries {
+		if existing.ID == d.ID {
+			m.deliveries[i] = &d
+		}
+	}
+	return nil
+}
+
+// dueNow makes every pending delivery due immediately, skipping the backoff.
+func (m *memWebhookRepo) dueNow() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, d := range m.deliveries {
+		if d.Status == model.DeliveryStatusPending {
+			d.NextAttemptAt = &past
+		}
+	}
+}
+
+func (m *memWebhookRepo) delivery(i int) model.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.deliveries[i]
+}
+
+const testSecret = "whsec_0123456789abcdef"
+
+func subscribedWebhook(url string, types ...model.EventType) model.Webhook {
+	return model.Webhook{
+		ID:         "wh-1",
+		UserID:     "user-1",
+		URL:        url,
+		Secret:     testSecret,
+		EventTypes: types,
+		Active:     true,
+	}
+}
+
+func todoEvent(t model.EventType) model.Event {
+	todo := model.Todo{ID: "todo-1", UserID: "user-1", Title: "Write report", Status: model.TodoStatusCompleted}
+	return model.Event{
+		ID:         "evt_1",
+		Type:       t,
+		UserID:     "user-1",
+		TodoID:     todo.ID,
+		Todo:       &todo,
+		OccurredAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := newMemWebhookRepo(subscribedWebhook(receiver.URL, model.EventTodoCompleted))
+	d := webhook.NewDispatcher(repo, webhook.Config{AllowPrivateTargets: true})
+
+	d.Queue(context.Background(), todoEvent(model.EventTodoCompleted))
+	d.Queue(context.Background(), todoEvent(model.EventTodoDeleted)) // not subscribed
+
+	if n, err := d.ProcessDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 delivery attempted, got %d (err: %v)", n, err)
+	}
+
+	r := <-got
+	if r.header.Get(webhook.HeaderEvent) != "todo.completed" {
+		t.Errorf("expected event header todo.completed, got %q", r.header.Get(webhook.HeaderEvent))
+	}
+	if !webhook.Verify(testSecret, r.header.Get(webhook.HeaderTimestamp), r.header.Get(webhook.HeaderSignature), r.body, time.Minute, time.Now()) {
+		t.Error("signature did not verify")
+	}
+
+	var payload webhook.Payload
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.ID != "evt_1" || payload.Data.Todo == nil || payload.Data.Todo.Title != "Write report" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	delivery := repo.delivery(0)
+	if delivery.Status != model.DeliveryStatusSucceeded || delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("expected succeeded/204, got %s/%d", delivery.Status, delivery.ResponseStatus)
+	}
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := newMemWebhookRepo(subscribedWebhook(receiver.URL, model.EventTodoCreated))
+	d := webhook.NewDispatcher(repo, webhook.Config{BaseBackoff: time.Minute, MaxAttempts: 5, AllowPrivateTargets: true})
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+
+	start := time.Now()
+	if _, err := d.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := repo.delivery(0)
+	if first.Status != model.DeliveryStatusPending || first.Attempts != 1 || first.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("expected pending after first failure, got %+v", first)
+	}
+	if wait := first.NextAttemptAt.Sub(start); wait < time.Minute || wait > time.Minute+5*time.Second {
+		t.Errorf("expected first retry after ~1m, got %v", wait)
+	}
+
+	// Not due yet: nothing is attempted.
+	if n, _ := d.ProcessDue(context.Background()); n != 0 {
+		t.Fatalf("expected no attempts before backoff expires, got %d", n)
+	}
+
+	repo.dueNow()
+	_, _ = d.ProcessDue(context.Background())
+	second := repo.delivery(0)
+	if wait := second.NextAttemptAt.Sub(start); wait < 2*time.Minute {
+		t.Errorf("expected backoff to double to ~2m, got %v", wait)
+	}
+
+	repo.dueNow()
+	_, _ = d.ProcessDue(context.Background())
+	third := repo.delivery(0)
+	if third.Status != model.DeliveryStatusSucceeded || third.Attempts != 3 {
+		t.Errorf("expected success on third attempt, got %s after %d attempts", third.Status, third.Attempts)
+	}
+	if w, _ := repo.Get(context.Background(), "wh-1"); w.FailureCount != 0 {
+		t.Errorf("expected failure count reset after success, got %d", w.FailureCount)
+	}
+}
+
+func TestDispatcher_AutoDisable(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := newMemWebhookRepo(subscribedWebhook(receiver.URL, model.EventTodoCreated))
+	d := webhook.NewDispatcher(repo, webhook.Config{MaxAttempts: 2, MaxFailures: 3, AllowPrivateTargets: true})
+
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+	for i := 0; i < 3; i++ {
+		repo.dueNow()
+		_, _ = d.ProcessDue(context.Background())
+	}
+
+	w, _ := repo.Get(context.Background(), "wh-1")
+	if w.Active {
+		t.Fatalf("expected webhook disabled after %d failures, failure_count=%d", 3, w.FailureCount)
+	}
+	for i := 0; i < 2; i++ {
+		if got := repo.delivery(i).Status; got != model.DeliveryStatusFailed {
+			t.Errorf("delivery %d: expected failed, got %s", i, got)
+		}
+	}
+
+	// Disabled webhooks receive no new deliveries.
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+	repo.mu.Lock()
+	n := len(repo.deliveries)
+	repo.mu.Unlock()
+	if n != 2 {
+		t.Errorf("expected no delivery queued for disabled webhook, got %d deliveries", n)
+	}
+}
+
+func TestDispatcher_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	repo := newMemWebhookRepo(subscribedWebhook(receiver.URL, model.EventTodoCreated))
+	d := webhook.NewDispatcher(repo, webhook.Config{})
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+	_, _ = d.ProcessDue(context.Background())
+
+	if called {
+		t.Fatal("expected the loopback receiver not to be called")
+	}
+	delivery := repo.delivery(0)
+	if delivery.ResponseStatus != 0 || !strings.Contains(delivery.LastError, "not public") {
+		t.Errorf("expected a refused connection, got status %d, error %q", delivery.ResponseStatus, delivery.LastError)
+	}
+}
+
+func TestDispatcher_PublishRecordsInBackground(t *testing.T) {
+	repo := newMemWebhookRepo(subscribedWebhook("https://example.com/hook", model.EventTodoCreated))
+	// Deliveries are never due, so only the recording is exercised.
+	d := webhook.NewDispatcher(repo, webhook.Config{PollInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+
+	event := todoEvent(model.EventTodoCreated)
+	d.Publish(ctx, event)
+	cancel()
+	<-done
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if len(repo.deliveries) != 1 || repo.deliveries[0].EventID != event.ID {
+		t.Fatalf("expected one delivery recorded for %s, got %d", event.ID, len(repo.deliveries))
+	}
+}
+
+func TestDispatcher_ShutdownIsNotAFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-release
+	}))
+	defer receiver.Close()
+	defer close(release)
+
+	repo := newMemWebhookRepo(subscribedWebhook(receiver.URL, model.EventTodoCreated))
+	d := webhook.NewDispatcher(repo, webhook.Config{AllowPrivateTargets: true})
+	d.Queue(context.Background(), todoEvent(model.EventTodoCreated))
+	_, _ = d.ProcessDue(ctx)
+
+	delivery := repo.delivery(0)
+	if delivery.Status != model.DeliveryStatusPending || delivery.Attempts != 0 || delivery.LastError != "" {
+		t.Errorf("expected the delivery released untouched, got %s after %d attempts (%q)", delivery.Status, delivery.Attempts, delivery.LastError)
+	}
+	if delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(time.Now()) {
+		t.Errorf("expected the delivery due again now, got %v", delivery.NextAttemptAt)
+	}
+	if w, _ := repo.Get(context.Background(), "wh-1"); w.FailureCount != 0 {
+		t.Errorf("expected no failure recorded, got %d", w.FailureCount)
+	}
+}
@@ -0,0 +1,48 @@
+package webhook_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaekwang-park/todo-api/internal/webhook"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_test_secret"
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1700000000, 0)
+	sig := webhook.Sign(secret, now, body)
+
+	if !strings.HasPrefix(sig, "sha256=") {
+		t.Fatalf("expected sha256= prefix, got %s", sig)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		want      bool
+	}{
+		{"valid", secret, "1700000000", sig, body, now, true},
+		{"within tolerance", secret, "1700000000", sig, body, now.Add(4 * time.Minute), true},
+		{"wrong secret", "other", "1700000000", sig, body, now, false},
+		{"tampered body", secret, "1700000000", sig, []byte(`{"id":"evt_2"}`), now, false},
+		{"replayed timestamp", secret, "1700000001", sig, body, now, false},
+		{"stale", secret, "1700000000", sig, body, now.Add(10 * time.Minute), false},
+		{"malformed timestamp", secret, "yesterday", sig, body, now, false},
+		{"missing prefix", secret, "1700000000", strings.TrimPrefix(sig, "sha256="), body, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := webhook.Verify(tt.secret, tt.timestamp, tt.signature, tt.body, 5*time.Minute, tt.now)
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
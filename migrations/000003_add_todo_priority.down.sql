@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_todos_user_matrix;
+
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS urgent,
+    DROP COLUMN IF EXISTS important,
+    DROP COLUMN IF EXISTS priority;
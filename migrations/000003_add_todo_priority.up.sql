@@ -0,0 +1,7 @@
+ALTER TABLE todos
+    ADD COLUMN priority  TEXT    NOT NULL DEFAULT 'none'
+        CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+    ADD COLUMN important BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN urgent    BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_todos_user_matrix ON todos (user_id, important, urgent, created_at);
@@ -0,0 +1,11 @@
+CREATE TABLE todo_dependencies (
+    todo_id     UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    blocker_id  UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (todo_id, blocker_id),
+    CHECK (todo_id <> blocker_id)
+);
+
+CREATE INDEX idx_todo_dependencies_blocker ON todo_dependencies (blocker_id);
+CREATE INDEX idx_todo_dependencies_user ON todo_dependencies (user_id);
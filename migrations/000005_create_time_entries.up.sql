@@ -0,0 +1,18 @@
+ALTER TABLE todos
+    ADD COLUMN estimate_minutes INTEGER CHECK (estimate_minutes >= 0);
+
+CREATE TABLE time_entries (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES users(id),
+    todo_id     UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    note        TEXT NOT NULL DEFAULT '',
+    started_at  TIMESTAMPTZ NOT NULL,
+    ended_at    TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- At most one running timer per user
+CREATE UNIQUE INDEX idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_time_entries_user_started ON time_entries (user_id, started_at);
+CREATE INDEX idx_time_entries_todo ON time_entries (todo_id);
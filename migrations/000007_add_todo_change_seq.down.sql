@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS todo_tombstones;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS change_seq;
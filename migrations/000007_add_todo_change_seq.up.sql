@@ -0,0 +1,18 @@
+-- change_seq orders a user's changes for delta sync. It holds the ID of the
+-- writing transaction rather than a sequence value: readers only return
+-- changes below the oldest transaction still in flight, so a change that
+-- commits late can never land behind a token already handed to a client.
+ALTER TABLE todos
+    ADD COLUMN change_seq XID8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX idx_todos_user_change_seq ON todos (user_id, change_seq, id);
+
+-- Deleted todos are kept as tombstones so syncing clients learn of deletions.
+CREATE TABLE todo_tombstones (
+    todo_id     UUID PRIMARY KEY,
+    user_id     UUID NOT NULL REFERENCES users(id),
+    change_seq  XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    deleted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_todo_tombstones_user_change_seq ON todo_tombstones (user_id, change_seq, todo_id);
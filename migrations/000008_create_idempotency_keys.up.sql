@@ -0,0 +1,18 @@
+-- Responses stored for Idempotency-Key retries. scope is the user ID, or
+-- "anonymous" for unauthenticated endpoints such as sign-up. A row with a
+-- NULL status_code is a request still in flight; locked_until lets another
+-- request take over the key if the original never finished.
+CREATE TABLE idempotency_keys (
+    scope            TEXT NOT NULL,
+    key              TEXT NOT NULL,
+    request_hash     TEXT NOT NULL,
+    status_code      INTEGER,
+    response_headers JSONB,
+    response_body    BYTEA,
+    locked_until     TIMESTAMPTZ NOT NULL,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,11 @@
+-- Token buckets shared by every instance. key identifies the route and the
+-- client (user ID or IP address); a bucket is full again by expires_at and
+-- can then be dropped.
+CREATE TABLE rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON rate_limit_buckets (expires_at);
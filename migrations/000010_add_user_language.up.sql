@@ -0,0 +1,5 @@
+-- Preferred language for messages, taken from the Cognito locale attribute.
+-- Empty means no preference.
+ALTER TABLE users
+    ADD COLUMN language TEXT NOT NULL DEFAULT ''
+        CHECK (language IN ('', 'en', 'ko'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todo_tombstones_deleted_at;
+DROP TABLE IF EXISTS todo_sync_floors;
@@ -0,0 +1,9 @@
+-- Tombstones are pruned after a retention window. pruned_seq is the newest
+-- change_seq pruned for a user; a sync token at or below it may have missed
+-- a deletion, so the client has to sync again from scratch.
+CREATE TABLE todo_sync_floors (
+    user_id     UUID PRIMARY KEY REFERENCES users(id),
+    pruned_seq  XID8 NOT NULL
+);
+
+CREATE INDEX idx_todo_tombstones_deleted_at ON todo_tombstones (deleted_at);
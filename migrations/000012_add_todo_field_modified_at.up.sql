@@ -0,0 +1,9 @@
+-- When each field of a todo was last changed, keyed by its JSON name, so
+-- that offline clients' changes can be merged field by field. Fields that
+-- are missing have not changed since the todo was created; existing todos
+-- count every field as changed at their last update.
+ALTER TABLE todos ADD COLUMN field_modified_at JSONB NOT NULL DEFAULT '{}';
+
+UPDATE todos SET field_modified_at = jsonb_build_object(
+    'title', updated_at, 'description', updated_at, 'status', updated_at, 'priority', updated_at,
+    'important', updated_at, 'urgent', updated_at, 'due_at', updated_at, 'estimate_minutes', updated_at);
@@ -0,0 +1,17 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/jaekwang-park/todo-api/migrations"
+)
+
+func TestLatestVersion(t *testing.T) {
+	v, err := migrations.LatestVersion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v < 12 {
+		t.Errorf("expected at least version 12, got %d", v)
+	}
+}